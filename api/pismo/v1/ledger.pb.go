@@ -0,0 +1,865 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pismo/v1/ledger.proto
+
+package pismov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Account mirrors the HTTP account resource. Amounts are in the minor units of
+// the account's currency.
+type Account struct {
+	state                     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId                 int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	DocumentNumber            string                 `protobuf:"bytes,2,opt,name=document_number,json=documentNumber,proto3" json:"document_number,omitempty"`
+	AvailableCreditLimitCents int64                  `protobuf:"varint,3,opt,name=available_credit_limit_cents,json=availableCreditLimitCents,proto3" json:"available_credit_limit_cents,omitempty"`
+	ClosingDay                int32                  `protobuf:"varint,4,opt,name=closing_day,json=closingDay,proto3" json:"closing_day,omitempty"`
+	Currency                  string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status                    string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt                 *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Account) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Account) GetDocumentNumber() string {
+	if x != nil {
+		return x.DocumentNumber
+	}
+	return ""
+}
+
+func (x *Account) GetAvailableCreditLimitCents() int64 {
+	if x != nil {
+		return x.AvailableCreditLimitCents
+	}
+	return 0
+}
+
+func (x *Account) GetClosingDay() int32 {
+	if x != nil {
+		return x.ClosingDay
+	}
+	return 0
+}
+
+func (x *Account) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Account) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Account) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// CreateAccountRequest takes the same defaults as POST /accounts.
+type CreateAccountRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	DocumentNumber  string                 `protobuf:"bytes,1,opt,name=document_number,json=documentNumber,proto3" json:"document_number,omitempty"`
+	DocumentCountry string                 `protobuf:"bytes,2,opt,name=document_country,json=documentCountry,proto3" json:"document_country,omitempty"`
+	// Decimal string in the account's currency, such as "1000.00". Empty means
+	// the default limit of 1000 in the account's currency.
+	AvailableCreditLimit string `protobuf:"bytes,3,opt,name=available_credit_limit,json=availableCreditLimit,proto3" json:"available_credit_limit,omitempty"`
+	ClosingDay           int32  `protobuf:"varint,4,opt,name=closing_day,json=closingDay,proto3" json:"closing_day,omitempty"`
+	Currency             string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *CreateAccountRequest) Reset() {
+	*x = CreateAccountRequest{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccountRequest) ProtoMessage() {}
+
+func (x *CreateAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccountRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccountRequest) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAccountRequest) GetDocumentNumber() string {
+	if x != nil {
+		return x.DocumentNumber
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetDocumentCountry() string {
+	if x != nil {
+		return x.DocumentCountry
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetAvailableCreditLimit() string {
+	if x != nil {
+		return x.AvailableCreditLimit
+	}
+	return ""
+}
+
+func (x *CreateAccountRequest) GetClosingDay() int32 {
+	if x != nil {
+		return x.ClosingDay
+	}
+	return 0
+}
+
+func (x *CreateAccountRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAccountRequest) Reset() {
+	*x = GetAccountRequest{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAccountRequest) ProtoMessage() {}
+
+func (x *GetAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAccountRequest.ProtoReflect.Descriptor instead.
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetAccountRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+// Transaction mirrors the HTTP transaction resource. Amounts are signed and in
+// the minor units of their currency.
+type Transaction struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	TransactionId       int64                  `protobuf:"varint,1,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	AccountId           int64                  `protobuf:"varint,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OperationTypeId     int32                  `protobuf:"varint,3,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	AmountCents         int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	BalanceCents        int64                  `protobuf:"varint,5,opt,name=balance_cents,json=balanceCents,proto3" json:"balance_cents,omitempty"`
+	Currency            string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	OriginalAmountCents int64                  `protobuf:"varint,7,opt,name=original_amount_cents,json=originalAmountCents,proto3" json:"original_amount_cents,omitempty"`
+	OriginalCurrency    string                 `protobuf:"bytes,8,opt,name=original_currency,json=originalCurrency,proto3" json:"original_currency,omitempty"`
+	ReversedOf          int64                  `protobuf:"varint,9,opt,name=reversed_of,json=reversedOf,proto3" json:"reversed_of,omitempty"`
+	Status              string                 `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	ParentTransactionId int64                  `protobuf:"varint,11,opt,name=parent_transaction_id,json=parentTransactionId,proto3" json:"parent_transaction_id,omitempty"`
+	InstallmentNumber   int32                  `protobuf:"varint,12,opt,name=installment_number,json=installmentNumber,proto3" json:"installment_number,omitempty"`
+	Installments        int32                  `protobuf:"varint,13,opt,name=installments,proto3" json:"installments,omitempty"`
+	EventDate           *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=event_date,json=eventDate,proto3" json:"event_date,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Transaction) GetTransactionId() int64 {
+	if x != nil {
+		return x.TransactionId
+	}
+	return 0
+}
+
+func (x *Transaction) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *Transaction) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *Transaction) GetBalanceCents() int64 {
+	if x != nil {
+		return x.BalanceCents
+	}
+	return 0
+}
+
+func (x *Transaction) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Transaction) GetOriginalAmountCents() int64 {
+	if x != nil {
+		return x.OriginalAmountCents
+	}
+	return 0
+}
+
+func (x *Transaction) GetOriginalCurrency() string {
+	if x != nil {
+		return x.OriginalCurrency
+	}
+	return ""
+}
+
+func (x *Transaction) GetReversedOf() int64 {
+	if x != nil {
+		return x.ReversedOf
+	}
+	return 0
+}
+
+func (x *Transaction) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Transaction) GetParentTransactionId() int64 {
+	if x != nil {
+		return x.ParentTransactionId
+	}
+	return 0
+}
+
+func (x *Transaction) GetInstallmentNumber() int32 {
+	if x != nil {
+		return x.InstallmentNumber
+	}
+	return 0
+}
+
+func (x *Transaction) GetInstallments() int32 {
+	if x != nil {
+		return x.Installments
+	}
+	return 0
+}
+
+func (x *Transaction) GetEventDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventDate
+	}
+	return nil
+}
+
+type CreateTransactionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OperationTypeId int32                  `protobuf:"varint,2,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	// Decimal string, such as "123.45".
+	Amount        string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Installments  int32  `protobuf:"varint,5,opt,name=installments,proto3" json:"installments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateTransactionRequest) Reset() {
+	*x = CreateTransactionRequest{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTransactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTransactionRequest) ProtoMessage() {}
+
+func (x *CreateTransactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTransactionRequest.ProtoReflect.Descriptor instead.
+func (*CreateTransactionRequest) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateTransactionRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *CreateTransactionRequest) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+func (x *CreateTransactionRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateTransactionRequest) GetInstallments() int32 {
+	if x != nil {
+		return x.Installments
+	}
+	return 0
+}
+
+// ListTransactionsRequest pages through an account's transactions, newest
+// first, like GET /accounts/{id}/transactions.
+type ListTransactionsRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AccountId       int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	OperationTypeId int32                  `protobuf:"varint,2,opt,name=operation_type_id,json=operationTypeId,proto3" json:"operation_type_id,omitempty"`
+	From            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To              *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	Limit           int32                  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	Cursor          string                 `protobuf:"bytes,6,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListTransactionsRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetOperationTypeId() int32 {
+	if x != nil {
+		return x.OperationTypeId
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListTransactionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListTransactionsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Transactions []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	// Empty on the last page.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type WatchTransactionsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	AccountId int64                  `protobuf:"varint,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	// Resumes after the given event_id. Zero starts with what is posted from
+	// now on.
+	AfterEventId  int64 `protobuf:"varint,2,opt,name=after_event_id,json=afterEventId,proto3" json:"after_event_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTransactionsRequest) Reset() {
+	*x = WatchTransactionsRequest{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTransactionsRequest) ProtoMessage() {}
+
+func (x *WatchTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *WatchTransactionsRequest) GetAccountId() int64 {
+	if x != nil {
+		return x.AccountId
+	}
+	return 0
+}
+
+func (x *WatchTransactionsRequest) GetAfterEventId() int64 {
+	if x != nil {
+		return x.AfterEventId
+	}
+	return 0
+}
+
+type WatchTransactionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position in the stream, to resume from after a disconnect.
+	EventId       int64        `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	Transaction   *Transaction `protobuf:"bytes,2,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchTransactionsResponse) Reset() {
+	*x = WatchTransactionsResponse{}
+	mi := &file_pismo_v1_ledger_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchTransactionsResponse) ProtoMessage() {}
+
+func (x *WatchTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pismo_v1_ledger_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*WatchTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_pismo_v1_ledger_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *WatchTransactionsResponse) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *WatchTransactionsResponse) GetTransaction() *Transaction {
+	if x != nil {
+		return x.Transaction
+	}
+	return nil
+}
+
+var File_pismo_v1_ledger_proto protoreflect.FileDescriptor
+
+const file_pismo_v1_ledger_proto_rawDesc = "" +
+	"\n" +
+	"\x15pismo/v1/ledger.proto\x12\bpismo.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x02\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12'\n" +
+	"\x0fdocument_number\x18\x02 \x01(\tR\x0edocumentNumber\x12?\n" +
+	"\x1cavailable_credit_limit_cents\x18\x03 \x01(\x03R\x19availableCreditLimitCents\x12\x1f\n" +
+	"\vclosing_day\x18\x04 \x01(\x05R\n" +
+	"closingDay\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xdd\x01\n" +
+	"\x14CreateAccountRequest\x12'\n" +
+	"\x0fdocument_number\x18\x01 \x01(\tR\x0edocumentNumber\x12)\n" +
+	"\x10document_country\x18\x02 \x01(\tR\x0fdocumentCountry\x124\n" +
+	"\x16available_credit_limit\x18\x03 \x01(\tR\x14availableCreditLimit\x12\x1f\n" +
+	"\vclosing_day\x18\x04 \x01(\x05R\n" +
+	"closingDay\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"2\n" +
+	"\x11GetAccountRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\"\xbf\x04\n" +
+	"\vTransaction\x12%\n" +
+	"\x0etransaction_id\x18\x01 \x01(\x03R\rtransactionId\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x02 \x01(\x03R\taccountId\x12*\n" +
+	"\x11operation_type_id\x18\x03 \x01(\x05R\x0foperationTypeId\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12#\n" +
+	"\rbalance_cents\x18\x05 \x01(\x03R\fbalanceCents\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x122\n" +
+	"\x15original_amount_cents\x18\a \x01(\x03R\x13originalAmountCents\x12+\n" +
+	"\x11original_currency\x18\b \x01(\tR\x10originalCurrency\x12\x1f\n" +
+	"\vreversed_of\x18\t \x01(\x03R\n" +
+	"reversedOf\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06status\x122\n" +
+	"\x15parent_transaction_id\x18\v \x01(\x03R\x13parentTransactionId\x12-\n" +
+	"\x12installment_number\x18\f \x01(\x05R\x11installmentNumber\x12\"\n" +
+	"\finstallments\x18\r \x01(\x05R\finstallments\x129\n" +
+	"\n" +
+	"event_date\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\teventDate\"\xbd\x01\n" +
+	"\x18CreateTransactionRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12*\n" +
+	"\x11operation_type_id\x18\x02 \x01(\x05R\x0foperationTypeId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\"\n" +
+	"\finstallments\x18\x05 \x01(\x05R\finstallments\"\xee\x01\n" +
+	"\x17ListTransactionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12*\n" +
+	"\x11operation_type_id\x18\x02 \x01(\x05R\x0foperationTypeId\x12.\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05limit\x18\x05 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x06 \x01(\tR\x06cursor\"v\n" +
+	"\x18ListTransactionsResponse\x129\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x15.pismo.v1.TransactionR\ftransactions\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"_\n" +
+	"\x18WatchTransactionsRequest\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\x03R\taccountId\x12$\n" +
+	"\x0eafter_event_id\x18\x02 \x01(\x03R\fafterEventId\"o\n" +
+	"\x19WatchTransactionsResponse\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x127\n" +
+	"\vtransaction\x18\x02 \x01(\v2\x15.pismo.v1.TransactionR\vtransaction2\x9c\x03\n" +
+	"\rLedgerService\x12B\n" +
+	"\rCreateAccount\x12\x1e.pismo.v1.CreateAccountRequest\x1a\x11.pismo.v1.Account\x12<\n" +
+	"\n" +
+	"GetAccount\x12\x1b.pismo.v1.GetAccountRequest\x1a\x11.pismo.v1.Account\x12N\n" +
+	"\x11CreateTransaction\x12\".pismo.v1.CreateTransactionRequest\x1a\x15.pismo.v1.Transaction\x12Y\n" +
+	"\x10ListTransactions\x12!.pismo.v1.ListTransactionsRequest\x1a\".pismo.v1.ListTransactionsResponse\x12^\n" +
+	"\x11WatchTransactions\x12\".pismo.v1.WatchTransactionsRequest\x1a#.pismo.v1.WatchTransactionsResponse0\x01B<Z:github.com/nicolasmmb/pismo-challenge/api/pismo/v1;pismov1b\x06proto3"
+
+var (
+	file_pismo_v1_ledger_proto_rawDescOnce sync.Once
+	file_pismo_v1_ledger_proto_rawDescData []byte
+)
+
+func file_pismo_v1_ledger_proto_rawDescGZIP() []byte {
+	file_pismo_v1_ledger_proto_rawDescOnce.Do(func() {
+		file_pismo_v1_ledger_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pismo_v1_ledger_proto_rawDesc), len(file_pismo_v1_ledger_proto_rawDesc)))
+	})
+	return file_pismo_v1_ledger_proto_rawDescData
+}
+
+var file_pismo_v1_ledger_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_pismo_v1_ledger_proto_goTypes = []any{
+	(*Account)(nil),                   // 0: pismo.v1.Account
+	(*CreateAccountRequest)(nil),      // 1: pismo.v1.CreateAccountRequest
+	(*GetAccountRequest)(nil),         // 2: pismo.v1.GetAccountRequest
+	(*Transaction)(nil),               // 3: pismo.v1.Transaction
+	(*CreateTransactionRequest)(nil),  // 4: pismo.v1.CreateTransactionRequest
+	(*ListTransactionsRequest)(nil),   // 5: pismo.v1.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),  // 6: pismo.v1.ListTransactionsResponse
+	(*WatchTransactionsRequest)(nil),  // 7: pismo.v1.WatchTransactionsRequest
+	(*WatchTransactionsResponse)(nil), // 8: pismo.v1.WatchTransactionsResponse
+	(*timestamppb.Timestamp)(nil),     // 9: google.protobuf.Timestamp
+}
+var file_pismo_v1_ledger_proto_depIdxs = []int32{
+	9,  // 0: pismo.v1.Account.created_at:type_name -> google.protobuf.Timestamp
+	9,  // 1: pismo.v1.Transaction.event_date:type_name -> google.protobuf.Timestamp
+	9,  // 2: pismo.v1.ListTransactionsRequest.from:type_name -> google.protobuf.Timestamp
+	9,  // 3: pismo.v1.ListTransactionsRequest.to:type_name -> google.protobuf.Timestamp
+	3,  // 4: pismo.v1.ListTransactionsResponse.transactions:type_name -> pismo.v1.Transaction
+	3,  // 5: pismo.v1.WatchTransactionsResponse.transaction:type_name -> pismo.v1.Transaction
+	1,  // 6: pismo.v1.LedgerService.CreateAccount:input_type -> pismo.v1.CreateAccountRequest
+	2,  // 7: pismo.v1.LedgerService.GetAccount:input_type -> pismo.v1.GetAccountRequest
+	4,  // 8: pismo.v1.LedgerService.CreateTransaction:input_type -> pismo.v1.CreateTransactionRequest
+	5,  // 9: pismo.v1.LedgerService.ListTransactions:input_type -> pismo.v1.ListTransactionsRequest
+	7,  // 10: pismo.v1.LedgerService.WatchTransactions:input_type -> pismo.v1.WatchTransactionsRequest
+	0,  // 11: pismo.v1.LedgerService.CreateAccount:output_type -> pismo.v1.Account
+	0,  // 12: pismo.v1.LedgerService.GetAccount:output_type -> pismo.v1.Account
+	3,  // 13: pismo.v1.LedgerService.CreateTransaction:output_type -> pismo.v1.Transaction
+	6,  // 14: pismo.v1.LedgerService.ListTransactions:output_type -> pismo.v1.ListTransactionsResponse
+	8,  // 15: pismo.v1.LedgerService.WatchTransactions:output_type -> pismo.v1.WatchTransactionsResponse
+	11, // [11:16] is the sub-list for method output_type
+	6,  // [6:11] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_pismo_v1_ledger_proto_init() }
+func file_pismo_v1_ledger_proto_init() {
+	if File_pismo_v1_ledger_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pismo_v1_ledger_proto_rawDesc), len(file_pismo_v1_ledger_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pismo_v1_ledger_proto_goTypes,
+		DependencyIndexes: file_pismo_v1_ledger_proto_depIdxs,
+		MessageInfos:      file_pismo_v1_ledger_proto_msgTypes,
+	}.Build()
+	File_pismo_v1_ledger_proto = out.File
+	file_pismo_v1_ledger_proto_goTypes = nil
+	file_pismo_v1_ledger_proto_depIdxs = nil
+}
@@ -3,8 +3,11 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,11 +19,21 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/cache"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/clock"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/document"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/fx"
+	adaptergrpc "github.com/nicolasmmb/pismo-challenge/internal/adapter/grpc"
 	adapterhttp "github.com/nicolasmmb/pismo-challenge/internal/adapter/http"
 	loggeradapter "github.com/nicolasmmb/pismo-challenge/internal/adapter/logger"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/migration"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/publisher"
 	"github.com/nicolasmmb/pismo-challenge/internal/adapter/repository"
 	"github.com/nicolasmmb/pismo-challenge/internal/config"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+	"github.com/nicolasmmb/pismo-challenge/migrations"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
@@ -30,17 +43,48 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
 )
 
 func main() {
-	cfg := config.Load()
+	cfg, args, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(cfg.LogLevel)) // checked by cfg.Validate
+	slog.SetLogLoggerLevel(level)
 	log := loggeradapter.New()
 
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	if len(args) > 0 {
+		switch args[0] {
+		case "migrate":
+			if err := runMigrate(ctx, cfg, args[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		case "config":
+			if err := cfg.Dump(os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+			os.Exit(2)
+		}
+		return
+	}
+
 	if cfg.OTLPEndpoint != "" {
-		shutdownOtel, err := initTracer(ctx, cfg.OTLPEndpoint, "pismo-app")
+		shutdownOtel, err := initTracer(ctx, cfg.OTLPEndpoint, "pismo-app", newSampler(cfg))
 		if err != nil {
 			log.Error("failed to init otel", map[string]any{"error": err})
 		} else {
@@ -52,7 +96,17 @@ func main() {
 		}
 	}
 
-	log.Info("starting service", map[string]any{"port": cfg.Port, "db_driver": cfg.DBDriver})
+	// Only what differs from the defaults, so the log shows what this
+	// instance was given.
+	settings := make(map[string]any)
+	for _, s := range cfg.Settings() {
+		if s.Source != config.SourceDefault {
+			settings[s.Key] = s.Value
+		}
+	}
+	log.Info("loaded config", settings)
+
+	log.Info("starting service", map[string]any{"port": cfg.Port, "grpc_port": cfg.GRPCPort, "db_driver": cfg.DBDriver})
 
 	if err := run(ctx, cfg, log); err != nil {
 		log.Error("service failed", map[string]any{"error": err})
@@ -68,35 +122,249 @@ func run(ctx context.Context, cfg config.Config, log loggeradapter.SlogLogger) e
 
 	defer db.Close()
 
+	db.SetMaxOpenConns(cfg.DBMaxOpenConns)
+	db.SetMaxIdleConns(cfg.DBMaxIdleConns)
+	db.SetConnMaxLifetime(cfg.DBConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.DBConnMaxIdleTime)
+
 	if err := db.PingContext(ctx); err != nil {
 		return fmt.Errorf("failed to ping db: %w", err)
 	}
 
+	if cfg.MigrateOnStart {
+		runner, err := migration.NewRunner(db, migrations.FS)
+		if err != nil {
+			return err
+		}
+		applied, err := runner.Up(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to migrate: %w", err)
+		}
+		for _, m := range applied {
+			log.Info("applied migration", map[string]any{"version": m.Version, "name": m.Name})
+		}
+	}
+
 	prometheus.MustRegister(collectors.NewDBStatsCollector(db, "pismo"))
 
 	accountRepo := repository.NewAccountRepository(db)
-	opTypeRepo := repository.NewOperationTypeRepository(db)
+	opTypeStore := repository.NewOperationTypeRepository(db)
+	opTypeRepo := cache.NewOperationTypes(opTypeStore, cfg.OperationTypeCacheTTL, clock.System{})
+	if err := opTypeRepo.Warm(ctx); err != nil {
+		log.Error("failed to warm operation type cache", map[string]any{"error": err})
+	}
 	txRepo := repository.NewTransactionRepository(db)
+	balanceRepo := repository.NewAccountBalanceRepository(db)
+	idempotencyRepo := repository.NewIdempotencyRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
+	statusChangeRepo := repository.NewAccountStatusChangeRepository(db)
+	authorizationRepo := repository.NewAuthorizationRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	webhookRepo := repository.NewWebhookSubscriptionRepository(db)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	tm := repository.NewTransactionManager(db)
 
 	createAccountUC := &usecase.CreateAccount{
-		Accounts: accountRepo,
+		Accounts:           accountRepo,
+		Documents:          document.Default(),
+		Outbox:             outboxRepo,
+		TransactionManager: tm,
 	}
 	getAccountUC := &usecase.GetAccount{
 		Accounts: accountRepo,
 	}
+	listAccountsUC := &usecase.ListAccounts{
+		Accounts:  accountRepo,
+		Documents: document.Default(),
+	}
 
-	tm := repository.NewTransactionManager(db)
+	exchangeRates, err := domain.ParseExchangeRates(cfg.FXRates)
+	if err != nil {
+		return fmt.Errorf("failed to parse FX_RATES: %w", err)
+	}
+	rates := fx.NewFixedRates(exchangeRates)
+
+	getBalanceUC := &usecase.GetAccountBalance{
+		Accounts: accountRepo,
+		Balances: balanceRepo,
+	}
+	rebuildBalanceUC := &usecase.RebuildAccountBalance{
+		Accounts:           accountRepo,
+		Balances:           balanceRepo,
+		TransactionManager: tm,
+	}
+	updateLimitUC := &usecase.UpdateCreditLimit{
+		Accounts:           accountRepo,
+		TransactionManager: tm,
+	}
+	changeStatusUC := &usecase.ChangeAccountStatus{
+		Accounts:           accountRepo,
+		StatusChanges:      statusChangeRepo,
+		TransactionManager: tm,
+	}
+	statusHistoryUC := &usecase.ListAccountStatusChanges{
+		Accounts:      accountRepo,
+		StatusChanges: statusChangeRepo,
+	}
 	createTxUC := &usecase.CreateTransaction{
 		Accounts:           accountRepo,
 		OperationTypes:     opTypeRepo,
 		Transactions:       txRepo,
+		Balances:           balanceRepo,
 		TransactionManager: tm,
+		Outbox:             outboxRepo,
+		Rates:              rates,
+	}
+
+	listTxUC := &usecase.ListTransactions{
+		Accounts:     accountRepo,
+		Transactions: txRepo,
+	}
+
+	watchTxUC := &usecase.WatchTransactions{
+		Accounts:     accountRepo,
+		Transactions: txRepo,
+		Outbox:       outboxRepo,
 	}
 
-	accountHandler := adapterhttp.NewAccountHandler(createAccountUC, getAccountUC)
-	txHandler := adapterhttp.NewTransactionHandler(createTxUC)
+	getTxUC := &usecase.GetTransaction{
+		Accounts:       accountRepo,
+		OperationTypes: opTypeRepo,
+		Transactions:   txRepo,
+	}
 
-	handler := adapterhttp.NewRouter(log, accountHandler, txHandler)
+	reverseTxUC := &usecase.ReverseTransaction{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		TransactionManager: tm,
+		Outbox:             outboxRepo,
+	}
+
+	postInstallmentsUC := &usecase.PostDueInstallments{
+		Accounts:           accountRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		TransactionManager: tm,
+		Outbox:             outboxRepo,
+	}
+
+	closeStatementsUC := &usecase.CloseStatements{
+		Accounts:           accountRepo,
+		Transactions:       txRepo,
+		Statements:         statementRepo,
+		TransactionManager: tm,
+		Clock:              clock.System{},
+	}
+	listStatementsUC := &usecase.ListStatements{
+		Accounts:   accountRepo,
+		Statements: statementRepo,
+	}
+	getStatementUC := &usecase.GetStatement{
+		Statements: statementRepo,
+	}
+
+	authorizeUC := &usecase.AuthorizeTransaction{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		Authorizations:     authorizationRepo,
+		TransactionManager: tm,
+		Clock:              clock.System{},
+		TTL:                cfg.AuthorizationTTL,
+	}
+	getAuthorizationUC := &usecase.GetAuthorization{
+		Authorizations: authorizationRepo,
+	}
+	captureUC := &usecase.CaptureAuthorization{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		Authorizations:     authorizationRepo,
+		TransactionManager: tm,
+		Outbox:             outboxRepo,
+		Clock:              clock.System{},
+	}
+	voidUC := &usecase.VoidAuthorization{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		Authorizations:     authorizationRepo,
+		TransactionManager: tm,
+		Clock:              clock.System{},
+	}
+	expireAuthorizationsUC := &usecase.ExpireAuthorizations{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		Authorizations:     authorizationRepo,
+		TransactionManager: tm,
+	}
+
+	createOpTypeUC := &usecase.CreateOperationType{OperationTypes: opTypeRepo}
+	listOpTypesUC := &usecase.ListOperationTypes{OperationTypes: opTypeRepo}
+	getOpTypeUC := &usecase.GetOperationType{OperationTypes: opTypeRepo}
+	updateOpTypeUC := &usecase.UpdateOperationType{OperationTypes: opTypeStore, TransactionManager: tm, Cache: opTypeRepo}
+
+	createWebhookUC := &usecase.CreateWebhook{Subscriptions: webhookRepo, Accounts: accountRepo}
+	listWebhooksUC := &usecase.ListWebhooks{Subscriptions: webhookRepo}
+	getWebhookUC := &usecase.GetWebhook{Subscriptions: webhookRepo}
+	deleteWebhookUC := &usecase.DeleteWebhook{Subscriptions: webhookRepo}
+	listDeliveriesUC := &usecase.ListWebhookDeliveries{Subscriptions: webhookRepo, Deliveries: deliveryRepo}
+	redeliverUC := &usecase.RedeliverWebhook{
+		Subscriptions: webhookRepo,
+		Deliveries:    deliveryRepo,
+		Clock:         clock.System{},
+	}
+	deliverWebhooksUC := &usecase.DeliverWebhooks{
+		Subscriptions: webhookRepo,
+		Deliveries:    deliveryRepo,
+		Sender:        publisher.NewSignedSender(&http.Client{Timeout: cfg.WebhookTimeout, Transport: publisher.NewPublicTransport()}),
+		Clock:         clock.System{},
+		MaxAttempts:   cfg.WebhookMaxAttempts,
+	}
+
+	// Webhook subscriptions always get the events; the configured publisher,
+	// if any, gets them afterwards.
+	publishers := []port.EventPublisher{usecase.WebhookFanout{
+		Subscriptions: webhookRepo,
+		Deliveries:    deliveryRepo,
+		Clock:         clock.System{},
+	}}
+	events, err := newEventPublisher(cfg)
+	if err != nil {
+		return err
+	}
+	if events != nil {
+		defer events.Close()
+		publishers = append(publishers, events)
+	}
+	relayEventsUC := &usecase.RelayEvents{
+		Outbox:      outboxRepo,
+		Publisher:   publisher.NewMulti(publishers...),
+		Clock:       clock.System{},
+		MaxAttempts: cfg.EventMaxAttempts,
+		Logger:      log,
+	}
+
+	accountHandler := adapterhttp.NewAccountHandler(createAccountUC, getAccountUC, getBalanceUC, rebuildBalanceUC, updateLimitUC, changeStatusUC, statusHistoryUC, listAccountsUC)
+	txHandler := adapterhttp.NewTransactionHandler(createTxUC, listTxUC, getTxUC, reverseTxUC)
+	statementHandler := adapterhttp.NewStatementHandler(listStatementsUC, getStatementUC)
+	opTypeHandler := adapterhttp.NewOperationTypeHandler(createOpTypeUC, listOpTypesUC, getOpTypeUC, updateOpTypeUC)
+	authorizationHandler := adapterhttp.NewAuthorizationHandler(authorizeUC, getAuthorizationUC, captureUC, voidUC)
+	webhookHandler := adapterhttp.NewWebhookHandler(createWebhookUC, listWebhooksUC, getWebhookUC, deleteWebhookUC, listDeliveriesUC, redeliverUC)
+
+	idempotency := adapterhttp.WithIdempotency(idempotencyRepo, cfg.IdempotencyTTL, cfg.IdempotencyLease, log)
+
+	readiness := adapterhttp.NewReadiness(log)
+	readiness.AddCheck("database", db.PingContext)
+
+	handler := adapterhttp.NewRouter(log, readiness, accountHandler, txHandler, statementHandler, opTypeHandler, authorizationHandler, webhookHandler, idempotency, cfg.RequestTimeout)
 
 	srv := &http.Server{
 		Addr:    ":" + cfg.Port,
@@ -109,15 +377,160 @@ func run(ctx context.Context, cfg config.Config, log loggeradapter.SlogLogger) e
 		}
 	}()
 
+	ledgerServer := adaptergrpc.NewLedgerServer(createAccountUC, getAccountUC, createTxUC, listTxUC, watchTxUC)
+	grpcHealth := health.NewServer()
+	grpcSrv := adaptergrpc.NewServer(log, ledgerServer, grpcHealth, cfg.RequestTimeout)
+
+	lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen for grpc: %w", err)
+	}
+	go func() {
+		if err := grpcSrv.Serve(lis); err != nil {
+			slog.Error("grpc server failed", "error", err)
+		}
+	}()
+
+	// Producers come first: see workers.Stop.
+	jobs := newWorkers()
+
+	jobs.Every("post-installments", cfg.InstallmentPostingInterval, func(ctx context.Context) {
+		posted, err := postInstallmentsUC.Execute(ctx, time.Now())
+		if err != nil {
+			log.Error("failed to post due installments", map[string]any{"error": err})
+		}
+		if posted > 0 {
+			log.Info("posted due installments", map[string]any{"count": posted})
+		}
+	})
+
+	jobs.Every("close-statements", cfg.StatementClosingInterval, func(ctx context.Context) {
+		closed, err := closeStatementsUC.Execute(ctx)
+		if err != nil {
+			log.Error("failed to close statements", map[string]any{"error": err})
+		}
+		if closed > 0 {
+			log.Info("closed statements", map[string]any{"count": closed})
+		}
+	})
+
+	jobs.Every("expire-authorizations", cfg.AuthorizationExpiryInterval, func(ctx context.Context) {
+		expired, err := expireAuthorizationsUC.Execute(ctx, time.Now())
+		if err != nil {
+			log.Error("failed to expire authorizations", map[string]any{"error": err})
+		}
+		if expired > 0 {
+			log.Info("expired authorizations", map[string]any{"count": expired})
+		}
+	})
+
+	jobs.Every("relay-events", cfg.OutboxRelayInterval, func(ctx context.Context) {
+		// Drain the backlog before waiting for the next tick.
+		for ctx.Err() == nil {
+			published, err := relayEventsUC.Execute(ctx)
+			if err != nil {
+				log.Error("failed to relay events", map[string]any{"error": err})
+			}
+			if published == 0 {
+				return
+			}
+		}
+	})
+
+	jobs.Every("deliver-webhooks", cfg.WebhookDeliveryInterval, func(ctx context.Context) {
+		for ctx.Err() == nil {
+			delivered, err := deliverWebhooksUC.Execute(ctx)
+			if err != nil {
+				log.Error("failed to deliver webhooks", map[string]any{"error": err})
+			}
+			if delivered == 0 {
+				return
+			}
+		}
+	})
+
 	<-ctx.Done()
-	log.Info("shutting down http server", nil)
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// Fail readiness first and give load balancers time to notice while
+	// requests are still served.
+	log.Info("draining", map[string]any{"delay": cfg.ShutdownDrainDelay.String()})
+	readiness.Drain()
+	grpcHealth.Shutdown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	log.Info("shutting down servers", nil)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
-	return srv.Shutdown(shutdownCtx)
+
+	// Watch streams only end when their context does, so GracefulStop
+	// would wait for them forever; they are cut once the deadline passes.
+	grpcStopped := make(chan struct{})
+	go func() {
+		grpcSrv.GracefulStop()
+		close(grpcStopped)
+	}()
+	httpErr := srv.Shutdown(shutdownCtx)
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		grpcSrv.Stop()
+	}
+
+	// Servers are down, so nothing new reaches the workers.
+	if err := jobs.Stop(shutdownCtx, log); err != nil {
+		log.Error("workers did not stop in time", map[string]any{"error": err})
+	}
+
+	return httpErr
+}
+
+// eventPublisher is a port.EventPublisher holding resources until closed.
+type eventPublisher interface {
+	port.EventPublisher
+	Close() error
+}
+
+// newEventPublisher builds the publisher named by EVENT_PUBLISHER, or returns
+// nil when none is configured.
+func newEventPublisher(cfg config.Config) (eventPublisher, error) {
+	switch cfg.EventPublisher {
+	case "":
+		return nil, nil
+	case "webhook":
+		return nopCloser{publisher.NewWebhook(cfg.EventWebhookURL, &http.Client{Timeout: cfg.EventWebhookTimeout})}, nil
+	case "file":
+		return publisher.OpenFile(cfg.EventFile)
+	default:
+		return nil, fmt.Errorf("unknown EVENT_PUBLISHER %q", cfg.EventPublisher)
+	}
+}
+
+type nopCloser struct {
+	port.EventPublisher
+}
+
+func (nopCloser) Close() error { return nil }
+
+// newSampler builds the sampler named by OTEL_TRACES_SAMPLER, with the names
+// and meaning the OpenTelemetry SDKs give that variable.
+func newSampler(cfg config.Config) sdktrace.Sampler {
+	switch cfg.TracesSampler {
+	case "always_off":
+		return sdktrace.NeverSample()
+	case "traceidratio":
+		return sdktrace.TraceIDRatioBased(cfg.TracesSamplerArg)
+	case "parentbased_always_on":
+		return sdktrace.ParentBased(sdktrace.AlwaysSample())
+	case "parentbased_always_off":
+		return sdktrace.ParentBased(sdktrace.NeverSample())
+	case "parentbased_traceidratio":
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.TracesSamplerArg))
+	default:
+		return sdktrace.AlwaysSample()
+	}
 }
 
-func initTracer(ctx context.Context, otlpEndpoint string, serviceName string) (func(context.Context) error, error) {
+func initTracer(ctx context.Context, otlpEndpoint string, serviceName string, sampler sdktrace.Sampler) (func(context.Context) error, error) {
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceName(serviceName),
@@ -138,7 +551,7 @@ func initTracer(ctx context.Context, otlpEndpoint string, serviceName string) (f
 	}
 
 	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(res),
 		sdktrace.WithBatcher(exporter),
 	)
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// workers runs background jobs on a context of their own, so that a shutdown
+// signal does not abort the run in progress.
+type workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	jobs   []*job
+}
+
+type job struct {
+	name string
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newWorkers() *workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &workers{ctx: ctx, cancel: cancel}
+}
+
+// Every runs fn at each interval until the workers are stopped.
+func (w *workers) Every(name string, interval time.Duration, fn func(ctx context.Context)) {
+	j := &job{name: name, stop: make(chan struct{}), done: make(chan struct{})}
+	w.jobs = append(w.jobs, j)
+
+	go func() {
+		defer close(j.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-j.stop:
+				return
+			case <-ticker.C:
+				fn(w.ctx)
+			}
+		}
+	}()
+}
+
+// Stop stops the jobs one at a time, in the order they were started, letting
+// each finish its current run before the next one is stopped. Jobs should be
+// started producers first, so that the ones after them find nothing new to
+// pick up. Once ctx is done, the runs still going are cancelled.
+func (w *workers) Stop(ctx context.Context, log port.Logger) error {
+	defer w.cancel()
+
+	for i, j := range w.jobs {
+		close(j.stop)
+		select {
+		case <-j.done:
+			log.Info("stopped worker", map[string]any{"worker": j.name})
+		case <-ctx.Done():
+			w.cancel()
+			for _, rest := range w.jobs[i+1:] {
+				close(rest.stop)
+			}
+			for _, rest := range w.jobs[i:] {
+				<-rest.done
+			}
+			return ctx.Err()
+		}
+	}
+	return nil
+}
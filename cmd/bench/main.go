@@ -186,7 +186,7 @@ func runBenchmark(config Config, method, path, body string, rate int) EndpointMe
 }
 
 func createTestAccount(baseURL string) (int, error) {
-	payload := fmt.Sprintf(`{"document_number":"%d"}`, time.Now().UnixNano())
+	payload := fmt.Sprintf(`{"document_number":"%s"}`, randomCPF())
 	resp, err := http.Post(baseURL+"/accounts", "application/json", bytes.NewBufferString(payload))
 	if err != nil {
 		return 0, err
@@ -202,6 +202,24 @@ func createTestAccount(baseURL string) (int, error) {
 	return result.AccountID, nil
 }
 
+// randomCPF returns a CPF with valid check digits so that accounts pass
+// document validation.
+func randomCPF() string {
+	digits := []byte(fmt.Sprintf("%09d", time.Now().UnixNano()%1_000_000_000))
+	for _, weight := range []int{10, 11} {
+		sum := 0
+		for i, d := range digits {
+			sum += int(d-'0') * (weight - i)
+		}
+		check := byte('0')
+		if rem := sum % 11; rem >= 2 {
+			check = byte('0' + 11 - rem)
+		}
+		digits = append(digits, check)
+	}
+	return string(digits)
+}
+
 func printResult(r BenchmarkResult) {
 	m := r.Metrics
 
@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+var (
+	cacheHits = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_hits_total",
+		Help: "Total number of lookups served from an in-process cache.",
+	}, []string{"cache"})
+
+	cacheMisses = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_misses_total",
+		Help: "Total number of lookups an in-process cache passed to its source.",
+	}, []string{"cache"})
+)
+
+const operationTypesCache = "operation_types"
+
+type entry struct {
+	operationType domain.OperationType
+	expiresAt     time.Time
+}
+
+// OperationTypes keeps operation types in memory in front of a
+// port.OperationTypeRepository. Writes made through it invalidate the type
+// they touch; changes made by other replicas show up once the entry's TTL
+// runs out. Unknown IDs are not cached.
+type OperationTypes struct {
+	next  port.OperationTypeRepository
+	ttl   time.Duration
+	clock port.Clock
+
+	mu      sync.RWMutex
+	entries map[int]entry
+	// gen counts invalidations, so a lookup that raced with one does not
+	// store what it read before the write.
+	gen uint64
+}
+
+func NewOperationTypes(next port.OperationTypeRepository, ttl time.Duration, clock port.Clock) *OperationTypes {
+	return &OperationTypes{
+		next:    next,
+		ttl:     ttl,
+		clock:   clock,
+		entries: make(map[int]entry),
+	}
+}
+
+// Warm loads every operation type, so the first transactions after boot do
+// not have to go to the database.
+func (c *OperationTypes) Warm(ctx context.Context) error {
+	c.mu.RLock()
+	gen := c.gen
+	c.mu.RUnlock()
+
+	types, err := c.next.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := c.clock.Now().Add(c.ttl)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gen != gen {
+		return nil
+	}
+	for _, ot := range types {
+		c.entries[ot.ID] = entry{operationType: ot, expiresAt: expiresAt}
+	}
+	return nil
+}
+
+func (c *OperationTypes) FindByID(ctx context.Context, id int) (domain.OperationType, error) {
+	now := c.clock.Now()
+
+	c.mu.RLock()
+	e, ok := c.entries[id]
+	gen := c.gen
+	c.mu.RUnlock()
+	if ok && now.Before(e.expiresAt) {
+		cacheHits.WithLabelValues(operationTypesCache).Inc()
+		return e.operationType, nil
+	}
+
+	cacheMisses.WithLabelValues(operationTypesCache).Inc()
+	ot, err := c.next.FindByID(ctx, id)
+	if err != nil {
+		return domain.OperationType{}, err
+	}
+
+	c.mu.Lock()
+	if c.gen == gen {
+		c.entries[id] = entry{operationType: ot, expiresAt: now.Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	return ot, nil
+}
+
+// FindByIDForUpdate always reads from the source: the lock must be taken on
+// the row, and what it returns is about to change.
+func (c *OperationTypes) FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error) {
+	return c.next.FindByIDForUpdate(ctx, id)
+}
+
+// List always reads from the source: it backs the management API, which
+// must see types created by other replicas.
+func (c *OperationTypes) List(ctx context.Context) ([]domain.OperationType, error) {
+	return c.next.List(ctx)
+}
+
+func (c *OperationTypes) Create(ctx context.Context, ot domain.OperationType) (int, error) {
+	id, err := c.next.Create(ctx, ot)
+	if err != nil {
+		return 0, err
+	}
+	c.Invalidate(id)
+	return id, nil
+}
+
+func (c *OperationTypes) Update(ctx context.Context, ot domain.OperationType) error {
+	// Invalidate even on failure: the write may have landed before the
+	// error was reported.
+	defer c.Invalidate(ot.ID)
+	return c.next.Update(ctx, ot)
+}
+
+// Invalidate drops the cached operation types with the given IDs, or every
+// cached type when none are given.
+func (c *OperationTypes) Invalidate(ids ...int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gen++
+	if len(ids) == 0 {
+		c.entries = make(map[int]entry)
+		return
+	}
+	for _, id := range ids {
+		delete(c.entries, id)
+	}
+}
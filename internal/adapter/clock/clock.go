@@ -0,0 +1,10 @@
+package clock
+
+import "time"
+
+// System is the wall clock.
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,87 @@
+package document
+
+// CPF is the Brazilian individual taxpayer number: nine digits followed by
+// two mod-11 check digits, usually written 000.000.000-00.
+type CPF struct{}
+
+func (CPF) Normalize(number string) (string, bool) {
+	s, ok := strip(number)
+	if !ok || len(s) != 11 || !allDigits(s) || repeated(s) {
+		return "", false
+	}
+
+	if checkDigit(s[:9], 10) != s[9] || checkDigit(s[:10], 11) != s[10] {
+		return "", false
+	}
+	return s, true
+}
+
+// CNPJ is the Brazilian company registration number, usually written
+// 00.000.000/0000-00. Since July 2026 its first twelve positions may also hold
+// upper-case letters; both forms share the same check digit algorithm, with
+// each character weighted by its ASCII code minus 48.
+type CNPJ struct{}
+
+func (CNPJ) Normalize(number string) (string, bool) {
+	s, ok := strip(number)
+	if !ok || len(s) != 14 || !allDigits(s[12:]) || repeated(s) {
+		return "", false
+	}
+
+	if cnpjCheckDigit(s[:12]) != s[12] || cnpjCheckDigit(s[:13]) != s[13] {
+		return "", false
+	}
+	return s, true
+}
+
+// checkDigit computes a CPF check digit over digits, weighting them from
+// weight down to 2.
+func checkDigit(digits string, weight int) byte {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[i]-'0') * (weight - i)
+	}
+	return mod11(sum)
+}
+
+// cnpjCheckDigit computes a CNPJ check digit, weighting chars from right to
+// left with 2 through 9 and wrapping back to 2.
+func cnpjCheckDigit(chars string) byte {
+	sum, weight := 0, 2
+	for i := len(chars) - 1; i >= 0; i-- {
+		sum += int(chars[i]-'0') * weight
+		weight++
+		if weight > 9 {
+			weight = 2
+		}
+	}
+	return mod11(sum)
+}
+
+func mod11(sum int) byte {
+	rem := sum % 11
+	if rem < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - rem)
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// repeated reports whether s is a single character repeated, such as
+// 00000000000. Those numbers pass the checksum but are never issued.
+func repeated(s string) bool {
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[0] {
+			return false
+		}
+	}
+	return true
+}
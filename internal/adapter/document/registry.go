@@ -0,0 +1,77 @@
+package document
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// Format recognises one kind of document number.
+type Format interface {
+	// Normalize returns the canonical form of number and whether it is a
+	// valid document of this format.
+	Normalize(number string) (string, bool)
+}
+
+// Registry maps ISO 3166-1 alpha-2 country codes to the document formats
+// accepted for them. A number is valid when any format of its country
+// accepts it.
+type Registry struct {
+	mu      sync.RWMutex
+	formats map[string][]Format
+}
+
+func NewRegistry() *Registry {
+	return &Registry{formats: make(map[string][]Format)}
+}
+
+// Default returns a registry with the Brazilian CPF and CNPJ formats.
+func Default() *Registry {
+	r := NewRegistry()
+	r.Register("BR", CPF{}, CNPJ{})
+	return r
+}
+
+// Register adds formats for country. Formats are tried in registration order.
+func (r *Registry) Register(country string, formats ...Format) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	country = strings.ToUpper(country)
+	r.formats[country] = append(r.formats[country], formats...)
+}
+
+func (r *Registry) Normalize(country, number string) (string, error) {
+	r.mu.RLock()
+	formats := r.formats[strings.ToUpper(country)]
+	r.mu.RUnlock()
+
+	for _, f := range formats {
+		if normalized, ok := f.Normalize(number); ok {
+			return normalized, nil
+		}
+	}
+	return "", domain.ErrInvalidDocumentNumber
+}
+
+// strip removes the separators commonly used to format document numbers and
+// upper-cases the rest. It reports false when number holds anything other than
+// letters, digits and separators.
+func strip(number string) (string, bool) {
+	var b strings.Builder
+	b.Grow(len(number))
+
+	for _, c := range strings.TrimSpace(number) {
+		switch {
+		case c >= '0' && c <= '9', c >= 'A' && c <= 'Z':
+			b.WriteRune(c)
+		case c >= 'a' && c <= 'z':
+			b.WriteRune(c - 'a' + 'A')
+		case c == '.', c == '-', c == '/', c == ' ':
+		default:
+			return "", false
+		}
+	}
+	return b.String(), true
+}
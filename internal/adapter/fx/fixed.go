@@ -0,0 +1,36 @@
+package fx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// FixedRates serves rates from a static table. It stands in for a real rate
+// provider in local and test environments.
+type FixedRates struct {
+	rates map[string]domain.Decimal
+}
+
+// NewFixedRates serves the given rates, such as those parsed from FX_RATES
+// by domain.ParseExchangeRates. A later rate for the same pair wins.
+func NewFixedRates(rates []domain.ExchangeRate) *FixedRates {
+	r := &FixedRates{rates: make(map[string]domain.Decimal, len(rates))}
+	for _, rate := range rates {
+		r.rates[key(rate.From, rate.To)] = rate.Rate
+	}
+	return r
+}
+
+func (r *FixedRates) Rate(ctx context.Context, from, to string) (domain.Decimal, error) {
+	rate, ok := r.rates[key(from, to)]
+	if !ok {
+		return domain.Decimal{}, domain.ErrRateUnavailable
+	}
+	return rate, nil
+}
+
+func key(from, to string) string {
+	return strings.ToUpper(from) + "/" + strings.ToUpper(to)
+}
@@ -0,0 +1,231 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// serverStream is a grpc.ServerStream carrying the context built by the
+// interceptors.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+func unaryRecovery(log port.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Error("panic recovered", map[string]any{"error": rec})
+				err = toStatus(ctx, fmt.Errorf("panic: %v", rec))
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func streamRecovery(log port.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				log.Error("panic recovered", map[string]any{"error": rec})
+				err = toStatus(ss.Context(), fmt.Errorf("panic: %v", rec))
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// unaryTimeout bounds each call as the HTTP API bounds each request; a
+// sooner deadline set by the client still applies. Streams are left alone:
+// a watch lasts as long as the client wants it.
+func unaryTimeout(d time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, cancel := context.WithTimeout(ctx, d)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+func unaryLogging(log port.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		// Methods leave masked internal errors here, see toStatus.
+		var internalErr error
+		ctx = context.WithValue(ctx, errorSlotKey{}, &internalErr)
+
+		resp, err := handler(ctx, req)
+		logCall(ctx, log, info.FullMethod, err, internalErr, start)
+		return resp, err
+	}
+}
+
+func streamLogging(log port.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+
+		var internalErr error
+		ctx := context.WithValue(ss.Context(), errorSlotKey{}, &internalErr)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, log, info.FullMethod, err, internalErr, start)
+		return err
+	}
+}
+
+func logCall(ctx context.Context, log port.Logger, method string, err, internalErr error, start time.Time) {
+	fields := map[string]any{
+		"method": method,
+		"code":   status.Code(err).String(),
+		"dur_ms": time.Since(start).Milliseconds(),
+	}
+	if internalErr != nil {
+		fields["error"] = internalErr.Error()
+		if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+			fields["trace_id"] = sc.TraceID().String()
+		}
+		log.Error("rpc failed", fields)
+		return
+	}
+	log.Info("rpc", fields)
+}
+
+var (
+	grpcDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "grpc_request_duration_seconds",
+		Help: "Duration of gRPC calls. Streams are measured until they end.",
+	}, []string{"method"})
+
+	grpcRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "grpc_requests_total",
+		Help: "Total number of gRPC calls.",
+	}, []string{"method", "code"})
+)
+
+func unaryMetrics() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeCall(info.FullMethod, err, start)
+		return resp, err
+	}
+}
+
+func streamMetrics() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeCall(info.FullMethod, err, start)
+		return err
+	}
+}
+
+func observeCall(method string, err error, start time.Time) {
+	grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	grpcRequests.WithLabelValues(method, status.Code(err).String()).Inc()
+}
+
+func unaryTracing(serviceName string) grpc.UnaryServerInterceptor {
+	rootTracer := otel.Tracer(serviceName)
+	propagator := otel.GetTextMapPropagator()
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := startSpan(ctx, rootTracer, propagator, info.FullMethod)
+		defer span.End()
+
+		header := metadata.MD{}
+		propagator.Inject(ctx, metadataCarrier(header))
+		_ = grpc.SetHeader(ctx, header)
+
+		resp, err := handler(ctx, req)
+		endSpan(span, err)
+		return resp, err
+	}
+}
+
+func streamTracing(serviceName string) grpc.StreamServerInterceptor {
+	rootTracer := otel.Tracer(serviceName)
+	propagator := otel.GetTextMapPropagator()
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := startSpan(ss.Context(), rootTracer, propagator, info.FullMethod)
+		defer span.End()
+
+		header := metadata.MD{}
+		propagator.Inject(ctx, metadataCarrier(header))
+		_ = ss.SetHeader(header)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		endSpan(span, err)
+		return err
+	}
+}
+
+// startSpan continues the trace found in the incoming metadata. fullMethod
+// has the form /package.Service/Method.
+func startSpan(ctx context.Context, tracer trace.Tracer, propagator propagation.TextMapPropagator, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = propagator.Extract(ctx, metadataCarrier(md))
+
+	name := strings.TrimPrefix(fullMethod, "/")
+	service, method, _ := strings.Cut(name, "/")
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			semconv.RPCSystemGRPC,
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(method),
+		),
+	)
+}
+
+func endSpan(span trace.Span, err error) {
+	code := status.Code(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(code)))
+	if code != codes.OK {
+		span.SetAttributes(attribute.Bool("error", true))
+		span.SetStatus(otelcodes.Error, status.Convert(err).Message())
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
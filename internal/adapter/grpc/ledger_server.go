@@ -0,0 +1,182 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pismov1 "github.com/nicolasmmb/pismo-challenge/api/pismo/v1"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+// LedgerServer implements pismov1.LedgerServiceServer on the use cases behind
+// the HTTP account and transaction handlers.
+type LedgerServer struct {
+	pismov1.UnimplementedLedgerServiceServer
+
+	createAccountUC *usecase.CreateAccount
+	getAccountUC    *usecase.GetAccount
+	createTxUC      *usecase.CreateTransaction
+	listTxUC        *usecase.ListTransactions
+	watchTxUC       *usecase.WatchTransactions
+}
+
+func NewLedgerServer(
+	createAccountUC *usecase.CreateAccount,
+	getAccountUC *usecase.GetAccount,
+	createTxUC *usecase.CreateTransaction,
+	listTxUC *usecase.ListTransactions,
+	watchTxUC *usecase.WatchTransactions,
+) *LedgerServer {
+	return &LedgerServer{
+		createAccountUC: createAccountUC,
+		getAccountUC:    getAccountUC,
+		createTxUC:      createTxUC,
+		listTxUC:        listTxUC,
+		watchTxUC:       watchTxUC,
+	}
+}
+
+func (s *LedgerServer) CreateAccount(ctx context.Context, req *pismov1.CreateAccountRequest) (*pismov1.Account, error) {
+	var limit *domain.Decimal
+	if v := req.GetAvailableCreditLimit(); v != "" {
+		d, err := domain.ParseDecimal(v)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		limit = &d
+	}
+
+	output, err := s.createAccountUC.Execute(ctx, usecase.CreateAccountInput{
+		DocumentNumber:       req.GetDocumentNumber(),
+		DocumentCountry:      req.GetDocumentCountry(),
+		AvailableCreditLimit: limit,
+		ClosingDay:           int(req.GetClosingDay()),
+		Currency:             req.GetCurrency(),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return newAccount(output), nil
+}
+
+func (s *LedgerServer) GetAccount(ctx context.Context, req *pismov1.GetAccountRequest) (*pismov1.Account, error) {
+	output, err := s.getAccountUC.Execute(ctx, req.GetAccountId())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return newAccount(output), nil
+}
+
+func (s *LedgerServer) CreateTransaction(ctx context.Context, req *pismov1.CreateTransactionRequest) (*pismov1.Transaction, error) {
+	amount, err := parseDecimal(req.GetAmount())
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	output, err := s.createTxUC.Execute(ctx, usecase.CreateTransactionInput{
+		AccountID:       req.GetAccountId(),
+		OperationTypeID: int(req.GetOperationTypeId()),
+		Amount:          amount,
+		Currency:        req.GetCurrency(),
+		Installments:    int(req.GetInstallments()),
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	return newTransaction(output), nil
+}
+
+func (s *LedgerServer) ListTransactions(ctx context.Context, req *pismov1.ListTransactionsRequest) (*pismov1.ListTransactionsResponse, error) {
+	filter := domain.TransactionFilter{
+		AccountID:       req.GetAccountId(),
+		OperationTypeID: int(req.GetOperationTypeId()),
+		Limit:           int(req.GetLimit()),
+	}
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+	if v := req.GetCursor(); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			return nil, toStatus(ctx, err)
+		}
+		filter.After = &cursor
+	}
+
+	page, err := s.listTxUC.Execute(ctx, filter)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+
+	resp := &pismov1.ListTransactionsResponse{Transactions: make([]*pismov1.Transaction, 0, len(page.Transactions))}
+	for _, tx := range page.Transactions {
+		resp.Transactions = append(resp.Transactions, newTransaction(tx))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.Encode()
+	}
+
+	return resp, nil
+}
+
+// WatchTransactions runs until the client cancels the call or the server
+// stops.
+func (s *LedgerServer) WatchTransactions(req *pismov1.WatchTransactionsRequest, stream grpc.ServerStreamingServer[pismov1.WatchTransactionsResponse]) error {
+	ctx := stream.Context()
+	err := s.watchTxUC.Execute(ctx, req.GetAccountId(), req.GetAfterEventId(), func(posted usecase.PostedTransaction) error {
+		return stream.Send(&pismov1.WatchTransactionsResponse{
+			EventId:     posted.EventID,
+			Transaction: newTransaction(posted.Transaction),
+		})
+	})
+	return toStatus(ctx, err)
+}
+
+// parseDecimal reads a decimal string, taking an empty one as zero like the
+// HTTP API does for omitted amounts.
+func parseDecimal(v string) (domain.Decimal, error) {
+	if v == "" {
+		return domain.Decimal{}, nil
+	}
+	return domain.ParseDecimal(v)
+}
+
+func newAccount(acc domain.Account) *pismov1.Account {
+	return &pismov1.Account{
+		AccountId:                 acc.ID,
+		DocumentNumber:            acc.DocumentNumber,
+		AvailableCreditLimitCents: acc.AvailableCreditLimitCents,
+		ClosingDay:                int32(acc.ClosingDay),
+		Currency:                  acc.Currency,
+		Status:                    string(acc.Status),
+		CreatedAt:                 timestamppb.New(acc.CreatedAt),
+	}
+}
+
+func newTransaction(tx domain.Transaction) *pismov1.Transaction {
+	return &pismov1.Transaction{
+		TransactionId:       tx.ID,
+		AccountId:           tx.AccountID,
+		OperationTypeId:     int32(tx.OperationTypeID),
+		AmountCents:         tx.AmountCents,
+		BalanceCents:        tx.BalanceCents,
+		Currency:            tx.Currency,
+		OriginalAmountCents: tx.OriginalAmountCents,
+		OriginalCurrency:    tx.OriginalCurrency,
+		ReversedOf:          tx.ReversedOf,
+		Status:              string(tx.Status),
+		ParentTransactionId: tx.ParentID,
+		InstallmentNumber:   int32(tx.InstallmentNumber),
+		Installments:        int32(tx.Installments),
+		EventDate:           timestamppb.New(tx.EventDate),
+	}
+}
@@ -0,0 +1,40 @@
+package grpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	pismov1 "github.com/nicolasmmb/pismo-challenge/api/pismo/v1"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// NewServer returns a gRPC server serving the ledger service and the standard
+// health service backed by healthSrv, which the caller flips to NOT_SERVING
+// when draining. Calls go through the same timeout, tracing, logging, metrics
+// and recovery as the HTTP router, in the same order; requestTimeout only
+// bounds unary calls.
+func NewServer(log port.Logger, ledger *LedgerServer, healthSrv *health.Server, requestTimeout time.Duration, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(
+			unaryTimeout(requestTimeout),
+			unaryTracing("pismo-api"),
+			unaryLogging(log),
+			unaryMetrics(),
+			unaryRecovery(log),
+		),
+		grpc.ChainStreamInterceptor(
+			streamTracing("pismo-api"),
+			streamLogging(log),
+			streamMetrics(),
+			streamRecovery(log),
+		),
+	)
+
+	srv := grpc.NewServer(opts...)
+	pismov1.RegisterLedgerServiceServer(srv, ledger)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+	return srv
+}
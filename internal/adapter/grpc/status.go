@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+// ErrorDomain is the domain of the google.rpc.ErrorInfo attached to every
+// error the service returns. Its reason is the error's usecase.ErrorKind code,
+// the same one the HTTP API sends in problem responses.
+const ErrorDomain = "pismo"
+
+// categoryCodes maps error categories to gRPC codes.
+var categoryCodes = map[usecase.ErrorCategory]codes.Code{
+	usecase.CategoryNotFound:      codes.NotFound,
+	usecase.CategoryInvalid:       codes.InvalidArgument,
+	usecase.CategoryRefused:       codes.FailedPrecondition,
+	usecase.CategoryExists:        codes.AlreadyExists,
+	usecase.CategoryConflict:      codes.FailedPrecondition,
+	usecase.CategoryConcurrent:    codes.Aborted,
+	usecase.CategoryClosed:        codes.FailedPrecondition,
+	usecase.CategoryLocked:        codes.FailedPrecondition,
+	usecase.CategoryUnprocessable: codes.FailedPrecondition,
+}
+
+// toStatus turns err into the status the call fails with. Internal errors are
+// recorded on the call span and handed to the logging interceptor instead of
+// being sent to the client.
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	kind, ok := usecase.KindOf(err)
+	if !ok {
+		switch {
+		case errors.Is(err, context.Canceled):
+			return status.Error(codes.Canceled, err.Error())
+		case errors.Is(err, context.DeadlineExceeded):
+			return status.Error(codes.DeadlineExceeded, err.Error())
+		}
+		reportError(ctx, err)
+	}
+
+	code, msg := codes.Internal, "internal error"
+	if ok {
+		code, msg = categoryCodes[kind.Category], kind.Err.Error()
+	}
+	st := status.New(code, msg)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: kind.Code, Domain: ErrorDomain}}
+	if kind.Field != "" {
+		violation := &errdetails.BadRequest_FieldViolation{Field: kind.Field, Description: msg}
+		details = append(details, &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{violation}})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// reportError keeps a masked error visible to operators.
+func reportError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, err.Error())
+
+	if slot, ok := ctx.Value(errorSlotKey{}).(*error); ok {
+		*slot = err
+	}
+}
+
+type errorSlotKey struct{}
@@ -1,87 +1,360 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
 )
 
 type AccountHandler struct {
-	createUC *usecase.CreateAccount
-	getUC    *usecase.GetAccount
+	createUC  *usecase.CreateAccount
+	getUC     *usecase.GetAccount
+	balanceUC *usecase.GetAccountBalance
+	rebuildUC *usecase.RebuildAccountBalance
+	limitUC   *usecase.UpdateCreditLimit
+	statusUC  *usecase.ChangeAccountStatus
+	historyUC *usecase.ListAccountStatusChanges
+	listUC    *usecase.ListAccounts
 }
 
-func NewAccountHandler(createUC *usecase.CreateAccount, getUC *usecase.GetAccount) *AccountHandler {
+func NewAccountHandler(
+	createUC *usecase.CreateAccount,
+	getUC *usecase.GetAccount,
+	balanceUC *usecase.GetAccountBalance,
+	rebuildUC *usecase.RebuildAccountBalance,
+	limitUC *usecase.UpdateCreditLimit,
+	statusUC *usecase.ChangeAccountStatus,
+	historyUC *usecase.ListAccountStatusChanges,
+	listUC *usecase.ListAccounts,
+) *AccountHandler {
 	return &AccountHandler{
-		createUC: createUC,
-		getUC:    getUC,
+		createUC:  createUC,
+		getUC:     getUC,
+		balanceUC: balanceUC,
+		rebuildUC: rebuildUC,
+		limitUC:   limitUC,
+		statusUC:  statusUC,
+		historyUC: historyUC,
+		listUC:    listUC,
 	}
 }
 
 type CreateAccountRequest struct {
-	DocumentNumber string `json:"document_number"`
+	DocumentNumber       string `json:"document_number"`
+	DocumentCountry      string `json:"document_country,omitempty"`
+	AvailableCreditLimit Amount `json:"available_credit_limit"`
+	ClosingDay           int    `json:"closing_day,omitempty"`
+	Currency             string `json:"currency,omitempty"`
+}
+
+type UpdateCreditLimitRequest struct {
+	AvailableCreditLimit Amount `json:"available_credit_limit"`
+}
+
+type ChangeAccountStatusRequest struct {
+	Status string `json:"status"`
+	Reason string `json:"reason"`
 }
 
 type AccountResponse struct {
-	ID             int64  `json:"account_id"`
-	DocumentNumber string `json:"document_number"`
+	ID                   int64     `json:"account_id"`
+	DocumentNumber       string    `json:"document_number"`
+	AvailableCreditLimit float64   `json:"available_credit_limit"`
+	ClosingDay           int       `json:"closing_day"`
+	Currency             string    `json:"currency"`
+	Status               string    `json:"status"`
+	CreatedAt            time.Time `json:"created_at"`
+}
+
+type AccountListResponse struct {
+	Data       []AccountResponse `json:"data"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+type AccountStatusChangeResponse struct {
+	ID        int64     `json:"id"`
+	From      string    `json:"from_status"`
+	To        string    `json:"to_status"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type AccountStatusHistoryResponse struct {
+	Data []AccountStatusChangeResponse `json:"data"`
+}
+
+type AccountBalanceResponse struct {
+	AccountID      int64  `json:"account_id"`
+	Currency       string `json:"currency"`
+	AvailableCents int64  `json:"available_cents"`
+	PostedCents    int64  `json:"posted_cents"`
+	PendingCents   int64  `json:"pending_cents"`
 }
 
 func (h *AccountHandler) CreateAccount(w http.ResponseWriter, r *http.Request) {
 	var req CreateAccountRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, errMalformedBody)
 		return
 	}
 
-	output, err := h.createUC.Execute(r.Context(), req.DocumentNumber)
+	limit, err := req.AvailableCreditLimit.Optional()
 	if err != nil {
-		if errors.Is(err, usecase.ErrInvalidDocument) {
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
-		if errors.Is(err, usecase.ErrDocumentExists) {
-			http.Error(w, err.Error(), http.StatusConflict)
-			return
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, withField("available_credit_limit", err))
+		return
+	}
+
+	output, err := h.createUC.Execute(r.Context(), usecase.CreateAccountInput{
+		DocumentNumber:       req.DocumentNumber,
+		DocumentCountry:      req.DocumentCountry,
+		AvailableCreditLimit: limit,
+		ClosingDay:           req.ClosingDay,
+		Currency:             req.Currency,
+	})
+	if err != nil {
+		writeError(w, r, limitField(err))
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(AccountResponse{
-		ID:             output.ID,
-		DocumentNumber: output.DocumentNumber,
-	})
+	_ = json.NewEncoder(w).Encode(newAccountResponse(output))
 }
 
 func (h *AccountHandler) GetAccount(w http.ResponseWriter, r *http.Request) {
-	idStr := r.PathValue("accountID")
-
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, err := pathID(r, "accountID")
 	if err != nil {
-		http.Error(w, "invalid account id", http.StatusBadRequest)
+		writeError(w, r, err)
 		return
 	}
 
 	output, err := h.getUC.Execute(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, domain.ErrAccountNotFound) {
-			http.Error(w, "account not found", http.StatusNotFound)
-			return
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newAccountResponse(output))
+}
+
+// ListAccounts serves GET /accounts. Query parameters: document_number,
+// document_country, status, from, to (RFC 3339 or YYYY-MM-DD, on the creation
+// date), sort (created_at or -created_at, the default), cursor and limit.
+func (h *AccountHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	input, err := parseAccountFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	page, err := h.listUC.Execute(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := AccountListResponse{Data: make([]AccountResponse, 0, len(page.Accounts))}
+	for _, acc := range page.Accounts {
+		resp.Data = append(resp.Data, newAccountResponse(acc))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func parseAccountFilter(r *http.Request) (usecase.ListAccountsInput, error) {
+	q := r.URL.Query()
+	input := usecase.ListAccountsInput{DocumentCountry: q.Get("document_country")}
+	filter := &input.AccountFilter
+	var err error
+
+	filter.DocumentNumber = q.Get("document_number")
+	filter.Status = domain.AccountStatus(q.Get("status"))
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = parseDate(v); err != nil {
+			return input, withField("from", errInvalidParameter)
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = parseDate(v); err != nil {
+			return input, withField("to", errInvalidParameter)
+		}
+	}
+	switch q.Get("sort") {
+	case "", "-created_at":
+	case "created_at":
+		filter.Ascending = true
+	default:
+		return input, withField("sort", errInvalidParameter)
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return input, withField("limit", errInvalidParameter)
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			return input, err
 		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		filter.After = &cursor
+	}
+
+	return input, nil
+}
+
+func (h *AccountHandler) UpdateCreditLimit(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req UpdateCreditLimitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	limit, err := req.AvailableCreditLimit.Decimal()
+	if err != nil {
+		writeError(w, r, withField("available_credit_limit", err))
+		return
+	}
+
+	output, err := h.limitUC.Execute(r.Context(), id, limit)
+	if err != nil {
+		writeError(w, r, limitField(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newAccountResponse(output))
+}
+
+// ChangeStatus serves PUT /accounts/{accountID}/status.
+func (h *AccountHandler) ChangeStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req ChangeAccountStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	output, err := h.statusUC.Execute(r.Context(), id, usecase.ChangeAccountStatusInput{
+		Status: req.Status,
+		Reason: req.Reason,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newAccountResponse(output))
+}
+
+// StatusHistory serves GET /accounts/{accountID}/status-history, oldest
+// change first.
+func (h *AccountHandler) StatusHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	changes, err := h.historyUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := AccountStatusHistoryResponse{Data: make([]AccountStatusChangeResponse, 0, len(changes))}
+	for _, c := range changes {
+		resp.Data = append(resp.Data, AccountStatusChangeResponse{
+			ID:        c.ID,
+			From:      string(c.From),
+			To:        string(c.To),
+			Reason:    c.Reason,
+			CreatedAt: c.CreatedAt,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *AccountHandler) GetAccountBalance(w http.ResponseWriter, r *http.Request) {
+	h.writeBalance(w, r, h.balanceUC.Execute)
+}
+
+func (h *AccountHandler) RebuildAccountBalance(w http.ResponseWriter, r *http.Request) {
+	h.writeBalance(w, r, h.rebuildUC.Execute)
+}
+
+func (h *AccountHandler) writeBalance(w http.ResponseWriter, r *http.Request, load func(context.Context, int64) (domain.AccountBalance, error)) {
+	id, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := load(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(AccountResponse{
-		ID:             output.ID,
-		DocumentNumber: output.DocumentNumber,
+	_ = json.NewEncoder(w).Encode(AccountBalanceResponse{
+		AccountID:      output.AccountID,
+		Currency:       output.Currency,
+		AvailableCents: output.AvailableCents(),
+		PostedCents:    output.PostedCents,
+		PendingCents:   output.PendingCents,
 	})
 }
+
+func newAccountResponse(acc domain.Account) AccountResponse {
+	return AccountResponse{
+		ID:                   acc.ID,
+		DocumentNumber:       acc.DocumentNumber,
+		AvailableCreditLimit: fromMinorUnits(acc.AvailableCreditLimitCents, acc.Currency),
+		ClosingDay:           acc.ClosingDay,
+		Currency:             acc.Currency,
+		Status:               string(acc.Status),
+		CreatedAt:            acc.CreatedAt,
+	}
+}
+
+// limitField attributes amount errors raised for the credit limit to the
+// field the client sent it in.
+func limitField(err error) error {
+	if errors.Is(err, domain.ErrAmountPrecision) || errors.Is(err, domain.ErrInvalidAmountFormat) {
+		return withField("available_credit_limit", err)
+	}
+	return err
+}
+
+func pathID(r *http.Request, name string) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, withField(name, errInvalidParameter)
+	}
+	return id, nil
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+type AuthorizationHandler struct {
+	authorizeUC *usecase.AuthorizeTransaction
+	getUC       *usecase.GetAuthorization
+	captureUC   *usecase.CaptureAuthorization
+	voidUC      *usecase.VoidAuthorization
+}
+
+func NewAuthorizationHandler(
+	authorizeUC *usecase.AuthorizeTransaction,
+	getUC *usecase.GetAuthorization,
+	captureUC *usecase.CaptureAuthorization,
+	voidUC *usecase.VoidAuthorization,
+) *AuthorizationHandler {
+	return &AuthorizationHandler{
+		authorizeUC: authorizeUC,
+		getUC:       getUC,
+		captureUC:   captureUC,
+		voidUC:      voidUC,
+	}
+}
+
+type CreateAuthorizationRequest struct {
+	AccountID       int64  `json:"account_id"`
+	OperationTypeID int    `json:"operation_type_id"`
+	Amount          Amount `json:"amount"`
+	Currency        string `json:"currency,omitempty"`
+}
+
+// CaptureAuthorizationRequest captures the whole hold when Amount is omitted.
+type CaptureAuthorizationRequest struct {
+	Amount Amount `json:"amount"`
+}
+
+type AuthorizationResponse struct {
+	ID              int64     `json:"authorization_id"`
+	AccountID       int64     `json:"account_id"`
+	OperationTypeID int       `json:"operation_type_id"`
+	Amount          float64   `json:"amount"`
+	AmountCents     int64     `json:"amount_cents"`
+	CapturedAmount  float64   `json:"captured_amount"`
+	CapturedCents   int64     `json:"captured_cents"`
+	Currency        string    `json:"currency"`
+	Status          string    `json:"status"`
+	TransactionID   int64     `json:"transaction_id,omitempty"`
+	ExpiresAt       time.Time `json:"expires_at"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type CaptureAuthorizationResponse struct {
+	Authorization AuthorizationResponse `json:"authorization"`
+	Transaction   TransactionResponse   `json:"transaction"`
+}
+
+func (h *AuthorizationHandler) CreateAuthorization(w http.ResponseWriter, r *http.Request) {
+	var req CreateAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	amount, err := req.Amount.Decimal()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.authorizeUC.Execute(r.Context(), usecase.AuthorizeTransactionInput{
+		AccountID:       req.AccountID,
+		OperationTypeID: req.OperationTypeID,
+		Amount:          amount,
+		Currency:        req.Currency,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newAuthorizationResponse(output))
+}
+
+func (h *AuthorizationHandler) GetAuthorization(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "authorizationID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newAuthorizationResponse(output))
+}
+
+func (h *AuthorizationHandler) CaptureAuthorization(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "authorizationID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req CaptureAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	amount, err := req.Amount.Decimal()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	auth, tx, err := h.captureUC.Execute(r.Context(), id, amount)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(CaptureAuthorizationResponse{
+		Authorization: newAuthorizationResponse(auth),
+		Transaction:   newTransactionResponse(tx),
+	})
+}
+
+func (h *AuthorizationHandler) VoidAuthorization(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "authorizationID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.voidUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newAuthorizationResponse(output))
+}
+
+func newAuthorizationResponse(auth domain.Authorization) AuthorizationResponse {
+	return AuthorizationResponse{
+		ID:              auth.ID,
+		AccountID:       auth.AccountID,
+		OperationTypeID: auth.OperationTypeID,
+		Amount:          fromMinorUnits(auth.AmountCents, auth.Currency),
+		AmountCents:     auth.AmountCents,
+		CapturedAmount:  fromMinorUnits(auth.CapturedCents, auth.Currency),
+		CapturedCents:   auth.CapturedCents,
+		Currency:        auth.Currency,
+		Status:          string(auth.Status),
+		TransactionID:   auth.TransactionID,
+		ExpiresAt:       auth.ExpiresAt,
+		CreatedAt:       auth.CreatedAt,
+	}
+}
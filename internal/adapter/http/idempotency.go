@@ -0,0 +1,130 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// WithIdempotency replays the stored response when a request is retried with
+// the same Idempotency-Key and body, and rejects a key reused for a different
+// body. Requests without the header pass through untouched.
+//
+// A request holds its key for lease while it runs, and its response is kept
+// for ttl once done. A key whose request never finished, because the process
+// died or its response could not be stored, is free again once the lease
+// runs out, so lease must outlast the longest request.
+func WithIdempotency(store port.IdempotencyRepository, ttl, lease time.Duration, log port.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(IdempotencyKeyHeader)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				writeError(w, r, errInvalidIdempotencyKey)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, r, errMalformedBody)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			// CreatedAt identifies the reservation in the database, which
+			// keeps microseconds.
+			now := time.Now().Truncate(time.Microsecond)
+			record := domain.IdempotencyRecord{
+				Scope:       r.Method + " " + r.URL.Path,
+				Key:         key,
+				RequestHash: fingerprint(body),
+				CreatedAt:   now,
+				ExpiresAt:   now.Add(lease),
+			}
+
+			existing, reserved, err := store.Reserve(r.Context(), record)
+			if err != nil {
+				log.Error("failed to reserve idempotency key", map[string]any{"error": err, "key": key})
+				writeError(w, r, err)
+				return
+			}
+
+			if !reserved {
+				switch {
+				case existing.RequestHash != record.RequestHash:
+					writeError(w, r, errIdempotencyKeyReused)
+				case !existing.Completed():
+					writeError(w, r, errIdempotencyKeyInFlight)
+				default:
+					if existing.ContentType != "" {
+						w.Header().Set("Content-Type", existing.ContentType)
+					}
+					w.Header().Set(IdempotentReplayedHeader, "true")
+					w.WriteHeader(existing.StatusCode)
+					_, _ = w.Write(existing.ResponseBody)
+				}
+				return
+			}
+
+			// The outcome is stored even if the client went away meanwhile.
+			storeCtx := context.WithoutCancel(r.Context())
+
+			defer func() {
+				if rec := recover(); rec != nil {
+					_ = store.Release(storeCtx, record)
+					panic(rec)
+				}
+			}()
+
+			br := &bodyRecorder{statusRecorder: statusRecorder{ResponseWriter: w, status: http.StatusOK}}
+			next.ServeHTTP(br, r)
+
+			// Server errors are not final, so the key is freed for a retry.
+			if br.status >= http.StatusInternalServerError {
+				if err := store.Release(storeCtx, record); err != nil {
+					log.Error("failed to release idempotency key", map[string]any{"error": err, "key": key})
+				}
+				return
+			}
+
+			record.StatusCode = br.status
+			record.ContentType = br.Header().Get("Content-Type")
+			record.ResponseBody = br.body.Bytes()
+			record.ExpiresAt = now.Add(ttl)
+			if err := store.Complete(storeCtx, record); err != nil {
+				log.Error("failed to store idempotent response", map[string]any{"error": err, "key": key})
+			}
+		})
+	}
+}
+
+func fingerprint(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+type bodyRecorder struct {
+	statusRecorder
+	body bytes.Buffer
+}
+
+func (br *bodyRecorder) Write(b []byte) (int, error) {
+	br.body.Write(b)
+	return br.ResponseWriter.Write(b)
+}
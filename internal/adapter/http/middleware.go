@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -32,7 +33,7 @@ func WithRecovery(log port.Logger) Middleware {
 			defer func() {
 				if rec := recover(); rec != nil {
 					log.Error("panic recovered", map[string]any{"error": rec})
-					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					writeError(w, r, fmt.Errorf("panic: %v", rec))
 				}
 			}()
 			next.ServeHTTP(w, r)
@@ -45,14 +46,31 @@ func WithLogging(log port.Logger) Middleware {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			sr := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
 			start := time.Now()
-			next.ServeHTTP(sr, r)
-			log.Info("request",
-				map[string]any{
-					"method": r.Method,
-					"path":   r.URL.Path,
-					"status": sr.status,
-					"dur_ms": time.Since(start).Milliseconds(),
-				})
+
+			// Handlers leave masked errors here, see writeError.
+			var errs errorSlot
+			ctx := context.WithValue(r.Context(), errorSlotKey{}, &errs)
+
+			next.ServeHTTP(sr, r.WithContext(ctx))
+
+			fields := map[string]any{
+				"method": r.Method,
+				"path":   r.URL.Path,
+				"status": sr.status,
+				"dur_ms": time.Since(start).Milliseconds(),
+			}
+			if errs.internal != nil {
+				fields["error"] = errs.internal.Error()
+				if id := traceID(ctx); id != "" {
+					fields["trace_id"] = id
+				}
+				log.Error("request failed", fields)
+				return
+			}
+			if errs.cause != nil {
+				fields["cause"] = errs.cause.Error()
+			}
+			log.Info("request", fields)
 		})
 	}
 }
@@ -0,0 +1,57 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// Amount is a monetary value taken verbatim from the request body, either as
+// a JSON number or as a string, so it can be converted to minor units without
+// float rounding.
+type Amount struct {
+	raw string
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(b, []byte("null")) {
+		a.raw = ""
+		return nil
+	}
+	if len(b) > 0 && b[0] == '"' {
+		return json.Unmarshal(b, &a.raw)
+	}
+	a.raw = string(b)
+	return nil
+}
+
+// Optional returns the exact amount, or nil when it was omitted.
+func (a Amount) Optional() (*domain.Decimal, error) {
+	if a.raw == "" {
+		return nil, nil
+	}
+	d, err := domain.ParseDecimal(a.raw)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Decimal returns the exact amount, or zero when it was omitted.
+func (a Amount) Decimal() (domain.Decimal, error) {
+	if a.raw == "" {
+		return domain.Decimal{}, nil
+	}
+	return domain.ParseDecimal(a.raw)
+}
+
+// fromMinorUnits renders minor units of the currency as a JSON number.
+func fromMinorUnits(units int64, currency string) float64 {
+	exponent := 2
+	if c, err := domain.LookupCurrency(currency); err == nil {
+		exponent = c.Exponent
+	}
+	return float64(units) / math.Pow10(exponent)
+}
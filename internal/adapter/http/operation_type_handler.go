@@ -0,0 +1,175 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+type OperationTypeHandler struct {
+	createUC *usecase.CreateOperationType
+	listUC   *usecase.ListOperationTypes
+	getUC    *usecase.GetOperationType
+	updateUC *usecase.UpdateOperationType
+}
+
+func NewOperationTypeHandler(
+	createUC *usecase.CreateOperationType,
+	listUC *usecase.ListOperationTypes,
+	getUC *usecase.GetOperationType,
+	updateUC *usecase.UpdateOperationType,
+) *OperationTypeHandler {
+	return &OperationTypeHandler{
+		createUC: createUC,
+		listUC:   listUC,
+		getUC:    getUC,
+		updateUC: updateUC,
+	}
+}
+
+type CreateOperationTypeRequest struct {
+	ID                 int    `json:"operation_type_id,omitempty"`
+	Description        string `json:"description"`
+	Sign               int    `json:"sign"`
+	AffectsCreditLimit *bool  `json:"affects_credit_limit,omitempty"`
+	Reversible         *bool  `json:"reversible,omitempty"`
+	Active             *bool  `json:"active,omitempty"`
+}
+
+// UpdateOperationTypeRequest is a partial update: omitted fields are kept.
+// The sign cannot be changed.
+type UpdateOperationTypeRequest struct {
+	Description        *string `json:"description,omitempty"`
+	AffectsCreditLimit *bool   `json:"affects_credit_limit,omitempty"`
+	Reversible         *bool   `json:"reversible,omitempty"`
+	Active             *bool   `json:"active,omitempty"`
+}
+
+type OperationTypeResponse struct {
+	ID                 int       `json:"operation_type_id"`
+	Description        string    `json:"description"`
+	Sign               int       `json:"sign"`
+	AffectsCreditLimit bool      `json:"affects_credit_limit"`
+	Reversible         bool      `json:"reversible"`
+	Active             bool      `json:"active"`
+	CreatedAt          time.Time `json:"created_at"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+type OperationTypeListResponse struct {
+	Data []OperationTypeResponse `json:"data"`
+}
+
+func (h *OperationTypeHandler) CreateOperationType(w http.ResponseWriter, r *http.Request) {
+	var req CreateOperationTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	output, err := h.createUC.Execute(r.Context(), usecase.CreateOperationTypeInput{
+		ID:                 req.ID,
+		Description:        req.Description,
+		Sign:               req.Sign,
+		AffectsCreditLimit: req.AffectsCreditLimit,
+		Reversible:         req.Reversible,
+		Active:             req.Active,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newOperationTypeResponse(output))
+}
+
+// ListOperationTypes serves GET /operation-types. With active=true only
+// active types are listed.
+func (h *OperationTypeHandler) ListOperationTypes(w http.ResponseWriter, r *http.Request) {
+	var activeOnly bool
+	if v := r.URL.Query().Get("active"); v != "" {
+		var err error
+		if activeOnly, err = strconv.ParseBool(v); err != nil {
+			writeError(w, r, withField("active", errInvalidParameter))
+			return
+		}
+	}
+
+	types, err := h.listUC.Execute(r.Context(), activeOnly)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := OperationTypeListResponse{Data: make([]OperationTypeResponse, 0, len(types))}
+	for _, ot := range types {
+		resp.Data = append(resp.Data, newOperationTypeResponse(ot))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *OperationTypeHandler) GetOperationType(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "operationTypeID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.getUC.Execute(r.Context(), int(id))
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newOperationTypeResponse(output))
+}
+
+func (h *OperationTypeHandler) UpdateOperationType(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "operationTypeID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	var req UpdateOperationTypeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	output, err := h.updateUC.Execute(r.Context(), int(id), usecase.UpdateOperationTypeInput{
+		Description:        req.Description,
+		AffectsCreditLimit: req.AffectsCreditLimit,
+		Reversible:         req.Reversible,
+		Active:             req.Active,
+	})
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newOperationTypeResponse(output))
+}
+
+func newOperationTypeResponse(ot domain.OperationType) OperationTypeResponse {
+	return OperationTypeResponse{
+		ID:                 ot.ID,
+		Description:        ot.Description,
+		Sign:               ot.Sign,
+		AffectsCreditLimit: ot.AffectsCreditLimit,
+		Reversible:         ot.Reversible,
+		Active:             ot.Active,
+		CreatedAt:          ot.CreatedAt,
+		UpdatedAt:          ot.UpdatedAt,
+	}
+}
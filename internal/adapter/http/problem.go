@@ -0,0 +1,187 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Errors raised by the HTTP layer itself.
+var (
+	errMalformedBody          = errors.New("invalid request body")
+	errInvalidParameter       = errors.New("invalid parameter")
+	errInvalidIdempotencyKey  = errors.New("invalid idempotency key")
+	errIdempotencyKeyReused   = errors.New("idempotency key reused with a different request")
+	errIdempotencyKeyInFlight = errors.New("request with this idempotency key is still in progress")
+)
+
+// Problem is an RFC 7807 problem details body. Code is stable and meant for
+// machines; Title and Detail are for people and may change. Detail is the
+// same for every problem of a kind: what caused it, such as a parser message,
+// only goes to the logs.
+type Problem struct {
+	Type     string       `json:"type"`
+	Title    string       `json:"title"`
+	Status   int          `json:"status"`
+	Code     string       `json:"code"`
+	Detail   string       `json:"detail,omitempty"`
+	Instance string       `json:"instance,omitempty"`
+	TraceID  string       `json:"trace_id,omitempty"`
+	Errors   []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError points a validation failure at one request field.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// problemStatuses maps error categories to response statuses.
+var problemStatuses = map[usecase.ErrorCategory]int{
+	usecase.CategoryNotFound:      http.StatusNotFound,
+	usecase.CategoryInvalid:       http.StatusBadRequest,
+	usecase.CategoryRefused:       http.StatusBadRequest,
+	usecase.CategoryExists:        http.StatusConflict,
+	usecase.CategoryConflict:      http.StatusConflict,
+	usecase.CategoryConcurrent:    http.StatusConflict,
+	usecase.CategoryClosed:        http.StatusGone,
+	usecase.CategoryLocked:        http.StatusLocked,
+	usecase.CategoryUnprocessable: http.StatusUnprocessableEntity,
+}
+
+// httpErrorKinds are the kinds of the errors raised by the HTTP layer. They
+// are matched before usecase.KindOf; errors that match neither are internal:
+// they are answered with a bare 500 and their text is kept out of the
+// response.
+var httpErrorKinds = []usecase.ErrorKind{
+	{Err: errMalformedBody, Category: usecase.CategoryInvalid, Code: "malformed_body", Title: "Malformed request body", Field: ""},
+	{Err: errInvalidParameter, Category: usecase.CategoryInvalid, Code: "invalid_parameter", Title: "Invalid parameter", Field: ""},
+	{Err: errInvalidIdempotencyKey, Category: usecase.CategoryInvalid, Code: "invalid_idempotency_key", Title: "Invalid idempotency key", Field: IdempotencyKeyHeader},
+	{Err: errIdempotencyKeyInFlight, Category: usecase.CategoryConflict, Code: "idempotency_key_in_flight", Title: "Request still in progress", Field: ""},
+	{Err: errIdempotencyKeyReused, Category: usecase.CategoryUnprocessable, Code: "idempotency_key_reused", Title: "Idempotency key reused", Field: IdempotencyKeyHeader},
+}
+
+// fieldError attributes err to a request field other than the one its kind
+// names.
+type fieldError struct {
+	field string
+	err   error
+}
+
+func (e *fieldError) Error() string { return e.err.Error() }
+func (e *fieldError) Unwrap() error { return e.err }
+
+func withField(field string, err error) error {
+	return &fieldError{field: field, err: err}
+}
+
+// writeError answers the request with the problem err maps to. Internal
+// errors are recorded on the request span and handed to WithLogging instead
+// of being sent to the client; so is the cause of any other error, when it
+// says more than the problem does.
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	writeProblem(w, newProblem(r, err))
+}
+
+func newProblem(r *http.Request, err error) Problem {
+	ctx := r.Context()
+	p, ok := classify(err)
+	switch {
+	case !ok:
+		reportError(ctx, err)
+	case err.Error() != p.Detail:
+		reportCause(ctx, err)
+	}
+	p.Instance = r.URL.Path
+	p.TraceID = traceID(ctx)
+	return p
+}
+
+// classify returns the problem err is answered with, less the parts taken
+// from the request. ok is false for internal errors, whose text is left out.
+func classify(err error) (p Problem, ok bool) {
+	kind, ok := findErrorKind(err)
+	if !ok {
+		p.Type = "about:blank"
+		p.Title = http.StatusText(http.StatusInternalServerError)
+		p.Status = http.StatusInternalServerError
+		p.Code = "internal_error"
+		return p, false
+	}
+
+	p.Type = "urn:pismo:problem:" + kind.Code
+	p.Title = kind.Title
+	p.Status = problemStatuses[kind.Category]
+	p.Code = kind.Code
+	p.Detail = kind.Err.Error()
+
+	field := kind.Field
+	var fe *fieldError
+	if errors.As(err, &fe) {
+		field = fe.field
+	}
+	if field != "" {
+		p.Errors = []FieldError{{Field: field, Message: p.Detail}}
+	}
+
+	return p, true
+}
+
+func findErrorKind(err error) (usecase.ErrorKind, bool) {
+	for _, kind := range httpErrorKinds {
+		if errors.Is(err, kind.Err) {
+			return kind, true
+		}
+	}
+	return usecase.KindOf(err)
+}
+
+func writeProblem(w http.ResponseWriter, p Problem) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+func traceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// reportError keeps a masked error visible to operators.
+func reportError(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		slot.internal = err
+	}
+}
+
+// reportCause keeps the cause of a client error visible to operators.
+func reportCause(ctx context.Context, err error) {
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		slot.cause = err
+	}
+}
+
+// errorSlot is where handlers leave, for WithLogging, the errors their
+// response leaves out.
+type errorSlot struct {
+	internal error
+	cause    error
+}
+
+type errorSlotKey struct{}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// readinessCheckTimeout bounds each dependency check, so a hung dependency
+// fails the probe instead of stalling it.
+const readinessCheckTimeout = 2 * time.Second
+
+// Check reports whether a dependency can serve requests.
+type Check func(ctx context.Context) error
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Readiness serves /readyz. It answers 503 while any registered check fails,
+// and for good once Drain has been called, so load balancers stop sending
+// traffic before the servers shut down.
+type Readiness struct {
+	log port.Logger
+
+	mu       sync.RWMutex
+	checks   []namedCheck
+	draining atomic.Bool
+}
+
+func NewReadiness(log port.Logger) *Readiness {
+	return &Readiness{log: log}
+}
+
+// AddCheck registers a dependency check under name.
+func (r *Readiness) AddCheck(name string, check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// Drain marks the service as shutting down. It cannot be undone.
+func (r *Readiness) Drain() {
+	r.draining.Store(true)
+}
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// ServeHTTP runs the checks concurrently. Failures are reported by name only;
+// their errors may describe infrastructure, so they are logged instead.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	resp := ReadinessResponse{Status: "ready"}
+	status := http.StatusOK
+
+	if r.draining.Load() {
+		resp.Status = "draining"
+		status = http.StatusServiceUnavailable
+	} else {
+		r.mu.RLock()
+		checks := r.checks
+		r.mu.RUnlock()
+
+		ctx, cancel := context.WithTimeout(req.Context(), readinessCheckTimeout)
+		defer cancel()
+
+		results := make([]error, len(checks))
+		var wg sync.WaitGroup
+		for i, c := range checks {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = c.check(ctx)
+			}()
+		}
+		wg.Wait()
+
+		resp.Checks = make(map[string]string, len(checks))
+		for i, c := range checks {
+			resp.Checks[c.name] = "ok"
+			if results[i] != nil {
+				r.log.Error("readiness check failed", map[string]any{"check": c.name, "error": results[i]})
+				resp.Checks[c.name] = "failing"
+				resp.Status = "not_ready"
+				status = http.StatusServiceUnavailable
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(resp)
+}
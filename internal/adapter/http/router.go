@@ -12,19 +12,51 @@ var startTime = time.Now()
 
 func NewRouter(
 	log port.Logger,
+	readiness *Readiness,
 	accountHandler *AccountHandler,
 	transactionHandler *TransactionHandler,
+	statementHandler *StatementHandler,
+	operationTypeHandler *OperationTypeHandler,
+	authorizationHandler *AuthorizationHandler,
+	webhookHandler *WebhookHandler,
+	idempotency Middleware,
+	requestTimeout time.Duration,
 ) http.Handler {
 	apiMux := http.NewServeMux()
 	apiMux.HandleFunc("/", healthHandler)
 	apiMux.HandleFunc("GET /healthz", healthzHandler)
-	apiMux.HandleFunc("POST /accounts", accountHandler.CreateAccount)
+	apiMux.Handle("POST /accounts", idempotency(http.HandlerFunc(accountHandler.CreateAccount)))
+	apiMux.HandleFunc("GET /accounts", accountHandler.ListAccounts)
 	apiMux.HandleFunc("GET /accounts/{accountID}", accountHandler.GetAccount)
-	apiMux.HandleFunc("POST /transactions", transactionHandler.CreateTransaction)
+	apiMux.HandleFunc("PUT /accounts/{accountID}/credit-limit", accountHandler.UpdateCreditLimit)
+	apiMux.HandleFunc("PUT /accounts/{accountID}/status", accountHandler.ChangeStatus)
+	apiMux.HandleFunc("GET /accounts/{accountID}/status-history", accountHandler.StatusHistory)
+	apiMux.HandleFunc("GET /accounts/{accountID}/transactions", transactionHandler.ListTransactions)
+	apiMux.HandleFunc("GET /accounts/{accountID}/balance", accountHandler.GetAccountBalance)
+	apiMux.HandleFunc("POST /accounts/{accountID}/balance/rebuild", accountHandler.RebuildAccountBalance)
+	apiMux.HandleFunc("GET /accounts/{accountID}/statements", statementHandler.ListStatements)
+	apiMux.Handle("POST /transactions", idempotency(http.HandlerFunc(transactionHandler.CreateTransaction)))
+	apiMux.HandleFunc("GET /transactions/{transactionID}", transactionHandler.GetTransaction)
+	apiMux.Handle("POST /transactions/{transactionID}/reversal", idempotency(http.HandlerFunc(transactionHandler.ReverseTransaction)))
+	apiMux.HandleFunc("GET /statements/{statementID}", statementHandler.GetStatement)
+	apiMux.HandleFunc("GET /operation-types", operationTypeHandler.ListOperationTypes)
+	apiMux.Handle("POST /operation-types", idempotency(http.HandlerFunc(operationTypeHandler.CreateOperationType)))
+	apiMux.HandleFunc("GET /operation-types/{operationTypeID}", operationTypeHandler.GetOperationType)
+	apiMux.HandleFunc("PATCH /operation-types/{operationTypeID}", operationTypeHandler.UpdateOperationType)
+	apiMux.Handle("POST /authorizations", idempotency(http.HandlerFunc(authorizationHandler.CreateAuthorization)))
+	apiMux.HandleFunc("GET /authorizations/{authorizationID}", authorizationHandler.GetAuthorization)
+	apiMux.Handle("POST /authorizations/{authorizationID}/capture", idempotency(http.HandlerFunc(authorizationHandler.CaptureAuthorization)))
+	apiMux.HandleFunc("POST /authorizations/{authorizationID}/void", authorizationHandler.VoidAuthorization)
+	apiMux.Handle("POST /webhooks", idempotency(http.HandlerFunc(webhookHandler.CreateWebhook)))
+	apiMux.HandleFunc("GET /webhooks", webhookHandler.ListWebhooks)
+	apiMux.HandleFunc("GET /webhooks/{webhookID}", webhookHandler.GetWebhook)
+	apiMux.HandleFunc("DELETE /webhooks/{webhookID}", webhookHandler.DeleteWebhook)
+	apiMux.HandleFunc("GET /webhooks/{webhookID}/deliveries", webhookHandler.ListDeliveries)
+	apiMux.HandleFunc("POST /webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", webhookHandler.Redeliver)
 
 	apiHandler := Chain(
 		apiMux,
-		WithTimeout(30*time.Second),
+		WithTimeout(requestTimeout),
 		WithTracing("pismo-api"),
 		WithLogging(log),
 		WithMetrics(),
@@ -33,6 +65,9 @@ func NewRouter(
 
 	rootMux := http.NewServeMux()
 	rootMux.Handle("/metrics", MetricsHandler())
+	rootMux.Handle("/livez", WithMetrics()(http.HandlerFunc(healthzHandler)))
+	rootMux.Handle("/readyz", WithMetrics()(readiness))
+	// Kept for existing probes; same as /livez.
 	rootMux.Handle("/healthz", WithMetrics()(http.HandlerFunc(healthzHandler)))
 	rootMux.Handle("/", apiHandler)
 
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+type StatementHandler struct {
+	listUC *usecase.ListStatements
+	getUC  *usecase.GetStatement
+}
+
+func NewStatementHandler(listUC *usecase.ListStatements, getUC *usecase.GetStatement) *StatementHandler {
+	return &StatementHandler{
+		listUC: listUC,
+		getUC:  getUC,
+	}
+}
+
+type StatementResponse struct {
+	ID             int64     `json:"statement_id"`
+	AccountID      int64     `json:"account_id"`
+	Currency       string    `json:"currency"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"`
+	OpeningBalance float64   `json:"opening_balance"`
+	Debits         float64   `json:"debits"`
+	Credits        float64   `json:"credits"`
+	ClosingBalance float64   `json:"closing_balance"`
+	TotalDue       float64   `json:"total_due"`
+	MinimumPayment float64   `json:"minimum_payment"`
+	DueDate        time.Time `json:"due_date"`
+	ClosedAt       time.Time `json:"closed_at"`
+}
+
+type StatementListResponse struct {
+	Data []StatementResponse `json:"data"`
+}
+
+func (h *StatementHandler) ListStatements(w http.ResponseWriter, r *http.Request) {
+	accountID, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	statements, err := h.listUC.Execute(r.Context(), accountID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := StatementListResponse{Data: make([]StatementResponse, 0, len(statements))}
+	for _, s := range statements {
+		resp.Data = append(resp.Data, newStatementResponse(s))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *StatementHandler) GetStatement(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "statementID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newStatementResponse(output))
+}
+
+func newStatementResponse(s domain.Statement) StatementResponse {
+	return StatementResponse{
+		ID:             s.ID,
+		AccountID:      s.AccountID,
+		Currency:       s.Currency,
+		PeriodStart:    s.PeriodStart,
+		PeriodEnd:      s.PeriodEnd,
+		OpeningBalance: fromMinorUnits(s.OpeningBalanceCents, s.Currency),
+		Debits:         fromMinorUnits(s.DebitsCents, s.Currency),
+		Credits:        fromMinorUnits(s.CreditsCents, s.Currency),
+		ClosingBalance: fromMinorUnits(s.ClosingBalanceCents, s.Currency),
+		TotalDue:       fromMinorUnits(s.TotalDueCents, s.Currency),
+		MinimumPayment: fromMinorUnits(s.MinimumPaymentCents, s.Currency),
+		DueDate:        s.DueDate,
+		ClosedAt:       s.ClosedAt,
+	}
+}
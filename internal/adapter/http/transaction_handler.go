@@ -3,56 +3,264 @@ package http
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
 )
 
 type TransactionHandler struct {
-	createUC *usecase.CreateTransaction
+	createUC  *usecase.CreateTransaction
+	listUC    *usecase.ListTransactions
+	getUC     *usecase.GetTransaction
+	reverseUC *usecase.ReverseTransaction
 }
 
-func NewTransactionHandler(createUC *usecase.CreateTransaction) *TransactionHandler {
+func NewTransactionHandler(
+	createUC *usecase.CreateTransaction,
+	listUC *usecase.ListTransactions,
+	getUC *usecase.GetTransaction,
+	reverseUC *usecase.ReverseTransaction,
+) *TransactionHandler {
 	return &TransactionHandler{
-		createUC: createUC,
+		createUC:  createUC,
+		listUC:    listUC,
+		getUC:     getUC,
+		reverseUC: reverseUC,
 	}
 }
 
+// CreateTransactionRequest splits a PURCHASE WITH INSTALLMENTS into monthly
+// installments when Installments is set.
 type CreateTransactionRequest struct {
-	AccountID       int64   `json:"account_id"`
-	OperationTypeID int     `json:"operation_type_id"`
-	Amount          float64 `json:"amount"`
+	AccountID       int64  `json:"account_id"`
+	OperationTypeID int    `json:"operation_type_id"`
+	Amount          Amount `json:"amount"`
+	Currency        string `json:"currency,omitempty"`
+	Installments    int    `json:"installments,omitempty"`
+}
+
+// ReverseTransactionRequest reverses the whole remaining amount when Amount
+// is omitted.
+type ReverseTransactionRequest struct {
+	Amount Amount `json:"amount"`
 }
 
 type TransactionResponse struct {
-	ID int64 `json:"transaction_id"`
+	ID                int64     `json:"transaction_id"`
+	AccountID         int64     `json:"account_id"`
+	OperationTypeID   int       `json:"operation_type_id"`
+	Amount            float64   `json:"amount"`
+	AmountCents       int64     `json:"amount_cents"`
+	Balance           float64   `json:"balance"`
+	BalanceCents      int64     `json:"balance_cents"`
+	Currency          string    `json:"currency"`
+	OriginalAmount    float64   `json:"original_amount,omitempty"`
+	OriginalCurrency  string    `json:"original_currency,omitempty"`
+	ReversedOf        int64     `json:"reversed_of,omitempty"`
+	Status            string    `json:"status"`
+	ParentID          int64     `json:"parent_transaction_id,omitempty"`
+	InstallmentNumber int       `json:"installment_number,omitempty"`
+	Installments      int       `json:"installments,omitempty"`
+	EventDate         time.Time `json:"event_date"`
+}
+
+type TransactionDetailResponse struct {
+	TransactionResponse
+	OperationTypeDescription string                `json:"operation_type_description"`
+	Account                  AccountResponse       `json:"account"`
+	Schedule                 []TransactionResponse `json:"schedule,omitempty"`
+}
+
+type TransactionListResponse struct {
+	Data       []TransactionResponse `json:"data"`
+	NextCursor string                `json:"next_cursor,omitempty"`
 }
 
 func (h *TransactionHandler) CreateTransaction(w http.ResponseWriter, r *http.Request) {
 	var req CreateTransactionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		writeError(w, r, errMalformedBody)
 		return
 	}
 
-	amountCents := int64(req.Amount * 100)
+	amount, err := req.Amount.Decimal()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-	output, err := h.createUC.Execute(r.Context(), req.AccountID, req.OperationTypeID, amountCents)
+	output, err := h.createUC.Execute(r.Context(), usecase.CreateTransactionInput{
+		AccountID:       req.AccountID,
+		OperationTypeID: req.OperationTypeID,
+		Amount:          amount,
+		Currency:        req.Currency,
+		Installments:    req.Installments,
+	})
 	if err != nil {
-		status := http.StatusInternalServerError
-		switch {
-		case errors.Is(err, domain.ErrAccountNotFound), errors.Is(err, domain.ErrOperationTypeNotFound):
-			status = http.StatusNotFound
-		case errors.Is(err, usecase.ErrInvalidAmount), errors.Is(err, usecase.ErrInvalidOperation), errors.Is(err, domain.ErrInsufficientFunds):
-			status = http.StatusBadRequest
-		}
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(newTransactionResponse(output))
+}
+
+func (h *TransactionHandler) ReverseTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "transactionID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
 
-		http.Error(w, err.Error(), status)
+	var req ReverseTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	amount, err := req.Amount.Decimal()
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.reverseUC.Execute(r.Context(), id, amount)
+	if err != nil {
+		writeError(w, r, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(TransactionResponse{ID: output.ID})
+	_ = json.NewEncoder(w).Encode(newTransactionResponse(output))
+}
+
+func (h *TransactionHandler) GetTransaction(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "transactionID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	output, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := TransactionDetailResponse{
+		TransactionResponse:      newTransactionResponse(output.Transaction),
+		OperationTypeDescription: output.OperationType.Description,
+		Account:                  newAccountResponse(output.Account),
+	}
+	for _, installment := range output.Installments {
+		resp.Schedule = append(resp.Schedule, newTransactionResponse(installment))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// ListTransactions serves GET /accounts/{accountID}/transactions. Query
+// parameters: operation_type_id, from, to (RFC 3339 or YYYY-MM-DD), cursor
+// and limit.
+func (h *TransactionHandler) ListTransactions(w http.ResponseWriter, r *http.Request) {
+	accountID, err := pathID(r, "accountID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter, err := parseTransactionFilter(r)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	filter.AccountID = accountID
+
+	page, err := h.listUC.Execute(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := TransactionListResponse{Data: make([]TransactionResponse, 0, len(page.Transactions))}
+	for _, tx := range page.Transactions {
+		resp.Data = append(resp.Data, newTransactionResponse(tx))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func parseTransactionFilter(r *http.Request) (domain.TransactionFilter, error) {
+	q := r.URL.Query()
+	var filter domain.TransactionFilter
+	var err error
+
+	if v := q.Get("operation_type_id"); v != "" {
+		if filter.OperationTypeID, err = strconv.Atoi(v); err != nil {
+			return filter, withField("operation_type_id", errInvalidParameter)
+		}
+	}
+	if v := q.Get("from"); v != "" {
+		if filter.From, err = parseDate(v); err != nil {
+			return filter, withField("from", errInvalidParameter)
+		}
+	}
+	if v := q.Get("to"); v != "" {
+		if filter.To, err = parseDate(v); err != nil {
+			return filter, withField("to", errInvalidParameter)
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			return filter, withField("limit", errInvalidParameter)
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = &cursor
+	}
+
+	return filter, nil
+}
+
+func parseDate(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, v)
+}
+
+func newTransactionResponse(tx domain.Transaction) TransactionResponse {
+	return TransactionResponse{
+		ID:                tx.ID,
+		AccountID:         tx.AccountID,
+		OperationTypeID:   tx.OperationTypeID,
+		Amount:            fromMinorUnits(tx.AmountCents, tx.Currency),
+		AmountCents:       tx.AmountCents,
+		Balance:           fromMinorUnits(tx.BalanceCents, tx.Currency),
+		BalanceCents:      tx.BalanceCents,
+		Currency:          tx.Currency,
+		OriginalAmount:    fromMinorUnits(tx.OriginalAmountCents, tx.OriginalCurrency),
+		OriginalCurrency:  tx.OriginalCurrency,
+		ReversedOf:        tx.ReversedOf,
+		Status:            string(tx.Status),
+		ParentID:          tx.ParentID,
+		InstallmentNumber: tx.InstallmentNumber,
+		Installments:      tx.Installments,
+		EventDate:         tx.EventDate,
+	}
 }
@@ -0,0 +1,266 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+type WebhookHandler struct {
+	createUC         *usecase.CreateWebhook
+	listUC           *usecase.ListWebhooks
+	getUC            *usecase.GetWebhook
+	deleteUC         *usecase.DeleteWebhook
+	listDeliveriesUC *usecase.ListWebhookDeliveries
+	redeliverUC      *usecase.RedeliverWebhook
+}
+
+func NewWebhookHandler(
+	createUC *usecase.CreateWebhook,
+	listUC *usecase.ListWebhooks,
+	getUC *usecase.GetWebhook,
+	deleteUC *usecase.DeleteWebhook,
+	listDeliveriesUC *usecase.ListWebhookDeliveries,
+	redeliverUC *usecase.RedeliverWebhook,
+) *WebhookHandler {
+	return &WebhookHandler{
+		createUC:         createUC,
+		listUC:           listUC,
+		getUC:            getUC,
+		deleteUC:         deleteUC,
+		listDeliveriesUC: listDeliveriesUC,
+		redeliverUC:      redeliverUC,
+	}
+}
+
+// CreateWebhookRequest subscribes URL to events. Omitted EventTypes means
+// every event type and an omitted AccountID every account.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types,omitempty"`
+	AccountID  int64    `json:"account_id,omitempty"`
+}
+
+// WebhookResponse describes a subscription. Secret is only set in the answer
+// to the request that created it.
+type WebhookResponse struct {
+	ID         int64     `json:"webhook_id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	AccountID  int64     `json:"account_id,omitempty"`
+	Active     bool      `json:"active"`
+	Secret     string    `json:"secret,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type WebhookListResponse struct {
+	Data []WebhookResponse `json:"data"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"delivery_id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        int64           `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	AccountID      int64           `json:"account_id"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Data       []WebhookDeliveryResponse `json:"data"`
+	NextCursor string                    `json:"next_cursor,omitempty"`
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, r, errMalformedBody)
+		return
+	}
+
+	input := usecase.CreateWebhookInput{URL: req.URL, AccountID: req.AccountID}
+	for _, t := range req.EventTypes {
+		input.EventTypes = append(input.EventTypes, domain.EventType(t))
+	}
+
+	sub, err := h.createUC.Execute(r.Context(), input)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := newWebhookResponse(sub)
+	resp.Secret = sub.Secret
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs, err := h.listUC.Execute(r.Context())
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := WebhookListResponse{Data: make([]WebhookResponse, 0, len(subs))}
+	for _, sub := range subs {
+		resp.Data = append(resp.Data, newWebhookResponse(sub))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "webhookID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	sub, err := h.getUC.Execute(r.Context(), id)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(newWebhookResponse(sub))
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "webhookID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	if err := h.deleteUC.Execute(r.Context(), id); err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r, "webhookID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	filter := domain.WebhookDeliveryFilter{SubscriptionID: id}
+	q := r.URL.Query()
+	filter.Status = domain.WebhookDeliveryStatus(q.Get("status"))
+	if v := q.Get("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil {
+			writeError(w, r, withField("limit", errInvalidParameter))
+			return
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		cursor, err := domain.DecodeCursor(v)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		filter.After = &cursor
+	}
+
+	page, err := h.listDeliveriesUC.Execute(r.Context(), filter)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	resp := WebhookDeliveryListResponse{Data: make([]WebhookDeliveryResponse, 0, len(page.Deliveries))}
+	for _, d := range page.Deliveries {
+		resp.Data = append(resp.Data, newWebhookDeliveryResponse(d))
+	}
+	if page.Next != nil {
+		resp.NextCursor = page.Next.Encode()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	webhookID, err := pathID(r, "webhookID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	deliveryID, err := pathID(r, "deliveryID")
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	d, err := h.redeliverUC.Execute(r.Context(), webhookID, deliveryID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(newWebhookDeliveryResponse(d))
+}
+
+func newWebhookResponse(sub domain.WebhookSubscription) WebhookResponse {
+	types := make([]string, 0, len(sub.EventTypes))
+	for _, t := range sub.EventTypes {
+		types = append(types, string(t))
+	}
+	return WebhookResponse{
+		ID:         sub.ID,
+		URL:        sub.URL,
+		EventTypes: types,
+		AccountID:  sub.AccountID,
+		Active:     sub.Active,
+		CreatedAt:  sub.CreatedAt,
+		UpdatedAt:  sub.UpdatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(d domain.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      string(d.EventType),
+		AccountID:      d.AccountID,
+		Payload:        d.Payload,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastError:      d.LastError,
+		LastStatusCode: d.LastStatusCode,
+		OccurredAt:     d.OccurredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	// The next attempt only means something while the delivery is pending.
+	if d.Status == domain.WebhookDeliveryPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	if !d.DeliveredAt.IsZero() {
+		resp.DeliveredAt = &d.DeliveredAt
+	}
+	return resp
+}
@@ -0,0 +1,87 @@
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is one versioned schema change. Down is empty when the change
+// cannot be undone.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Load reads migrations from the top level of fsys. Files are named
+// NNN_description.sql, with an optional NNN_description.down.sql; other
+// files are ignored. Migrations are returned in version order.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	downs := make(map[int64]string)
+
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || path.Ext(file) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(file, ".sql")
+		base, isDown := strings.CutSuffix(base, ".down")
+
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %q", file)
+		}
+
+		body, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", file, err)
+		}
+
+		if isDown {
+			downs[version] = string(body)
+			continue
+		}
+		if existing, dup := byVersion[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d: %q and %q", version, existing.Name, name)
+		}
+		sum := sha256.Sum256(body)
+		byVersion[version] = &Migration{
+			Version:  version,
+			Name:     name,
+			Up:       string(body),
+			Checksum: hex.EncodeToString(sum[:]),
+		}
+	}
+
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration %d has no up migration", version)
+		}
+		m.Down = down
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+
+	return out, nil
+}
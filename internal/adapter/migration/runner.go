@@ -0,0 +1,259 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"time"
+)
+
+// lockID is the Postgres advisory lock key held while migrating, so that
+// replicas starting together apply each migration once.
+const lockID int64 = 7_263_410_551
+
+const (
+	createTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`
+	selectAppliedSQL = `SELECT version, checksum, applied_at FROM schema_migrations`
+	insertAppliedSQL = `INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES ($1, $2, $3, $4)`
+	deleteAppliedSQL = `DELETE FROM schema_migrations WHERE version = $1`
+	tablesExistSQL   = `SELECT to_regclass('public.accounts') IS NOT NULL`
+	lockSQL          = `SELECT pg_advisory_lock($1)`
+	unlockSQL        = `SELECT pg_advisory_unlock($1)`
+)
+
+var (
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	ErrNoHistory        = errors.New("database has a schema but no migration history, run baseline first")
+	ErrIrreversible     = errors.New("migration has no down script")
+	ErrUnknownVersion   = errors.New("unknown migration version")
+)
+
+// Status is a migration and whether it has been applied. Modified reports
+// that the applied script differs from the embedded one.
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool
+}
+
+type applied struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// Runner applies migrations to a Postgres database and records them in the
+// schema_migrations table. Every operation holds a session advisory lock.
+type Runner struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func NewRunner(db *sql.DB, fsys fs.FS) (*Runner, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{db: db, migrations: migrations}, nil
+}
+
+// Up applies every pending migration in order, each in its own transaction,
+// and returns the ones it applied. It refuses to run when an applied
+// migration no longer matches its checksum.
+func (r *Runner) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := r.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		if len(history) == 0 {
+			var exists bool
+			if err := conn.QueryRowContext(ctx, tablesExistSQL).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to inspect schema: %w", err)
+			}
+			if exists {
+				return ErrNoHistory
+			}
+		}
+
+		for _, m := range r.migrations {
+			if h, ok := history[m.Version]; ok {
+				if h.checksum != m.Checksum {
+					return fmt.Errorf("%w: %03d_%s", ErrChecksumMismatch, m.Version, m.Name)
+				}
+				continue
+			}
+
+			if err := r.apply(ctx, conn, m.Up, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, insertAppliedSQL, m.Version, m.Name, m.Checksum, time.Now())
+				return err
+			}); err != nil {
+				return fmt.Errorf("failed to apply migration %03d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down reverts the latest steps applied migrations, newest first, and returns
+// the ones it reverted.
+func (r *Runner) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := r.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for i := len(r.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := history[m.Version]; !ok {
+				continue
+			}
+			if m.Down == "" {
+				return fmt.Errorf("%w: %03d_%s", ErrIrreversible, m.Version, m.Name)
+			}
+
+			if err := r.apply(ctx, conn, m.Down, func(tx *sql.Tx) error {
+				_, err := tx.ExecContext(ctx, deleteAppliedSQL, m.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("failed to revert migration %03d_%s: %w", m.Version, m.Name, err)
+			}
+			done = append(done, m)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Baseline records every migration up to version as applied without running
+// it, for databases whose schema was created before the runner existed.
+func (r *Runner) Baseline(ctx context.Context, version int64) error {
+	if !r.known(version) {
+		return fmt.Errorf("%w: %d", ErrUnknownVersion, version)
+	}
+
+	return r.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := r.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			if m.Version > version {
+				break
+			}
+			if _, ok := history[m.Version]; ok {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx, insertAppliedSQL, m.Version, m.Name, m.Checksum, time.Now()); err != nil {
+				return fmt.Errorf("failed to record migration %03d_%s: %w", m.Version, m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists every known migration with its applied state.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	var out []Status
+	err := r.withLock(ctx, func(conn *sql.Conn) error {
+		history, err := r.history(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, m := range r.migrations {
+			s := Status{Migration: m}
+			if h, ok := history[m.Version]; ok {
+				s.Applied = true
+				s.AppliedAt = h.appliedAt
+				s.Modified = h.checksum != m.Checksum
+			}
+			out = append(out, s)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (r *Runner) known(version int64) bool {
+	for _, m := range r.migrations {
+		if m.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// withLock runs fn on a single connection holding the migration lock. The
+// lock is session scoped, so it must be taken and released on the same
+// connection.
+func (r *Runner) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := r.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, lockSQL, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.WithoutCancel(ctx), unlockSQL, lockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, createTableSQL); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	return fn(conn)
+}
+
+func (r *Runner) history(ctx context.Context, conn *sql.Conn) (map[int64]applied, error) {
+	rows, err := conn.QueryContext(ctx, selectAppliedSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64]applied)
+	for rows.Next() {
+		var version int64
+		var a applied
+		if err := rows.Scan(&version, &a.checksum, &a.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		out[version] = a
+	}
+	return out, rows.Err()
+}
+
+// apply runs script and record in one transaction.
+func (r *Runner) apply(ctx context.Context, conn *sql.Conn, script string, record func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// Envelope is how an event leaves the service. ID is stable across
+// redeliveries and is what consumers deduplicate on.
+type Envelope struct {
+	ID         int64           `json:"id"`
+	Type       string          `json:"type"`
+	AccountID  int64           `json:"account_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+func NewEnvelope(event domain.Event) Envelope {
+	return Envelope{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		AccountID:  event.AccountID,
+		OccurredAt: event.CreatedAt,
+		Data:       event.Payload,
+	}
+}
@@ -0,0 +1,55 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// File writes each event as one JSON Envelope per line, so events can be
+// inspected or replayed without any other service running.
+type File struct {
+	mu sync.Mutex
+	w  io.Writer
+	c  io.Closer
+}
+
+// NewWriter publishes to w.
+func NewWriter(w io.Writer) *File {
+	return &File{w: w}
+}
+
+// OpenFile publishes to the file at path, appending to it.
+func OpenFile(path string) (*File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event file: %w", err)
+	}
+	return &File{w: f, c: f}, nil
+}
+
+func (p *File) Publish(ctx context.Context, event domain.Event) error {
+	line, err := json.Marshal(NewEnvelope(event))
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write event: %w", err)
+	}
+	return nil
+}
+
+func (p *File) Close() error {
+	if p.c == nil {
+		return nil
+	}
+	return p.c.Close()
+}
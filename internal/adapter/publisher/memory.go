@@ -0,0 +1,32 @@
+package publisher
+
+import (
+	"context"
+	"sync"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// Memory keeps published events in memory, for tests and local runs.
+type Memory struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+func NewMemory() *Memory {
+	return &Memory{}
+}
+
+func (p *Memory) Publish(ctx context.Context, event domain.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns what was published so far, in order.
+func (p *Memory) Events() []domain.Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]domain.Event(nil), p.events...)
+}
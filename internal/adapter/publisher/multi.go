@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// Multi publishes each event to several publishers in order, stopping at the
+// first that fails. The outbox retries the whole event, so every publisher
+// must tolerate seeing it again.
+type Multi struct {
+	publishers []port.EventPublisher
+}
+
+func NewMulti(publishers ...port.EventPublisher) *Multi {
+	return &Multi{publishers: publishers}
+}
+
+func (p *Multi) Publish(ctx context.Context, event domain.Event) error {
+	for _, next := range p.publishers {
+		if err := next.Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
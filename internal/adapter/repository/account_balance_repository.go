@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	accountBalanceSelectSQL = `SELECT account_id, posted_cents, pending_cents, updated_at FROM account_balances WHERE account_id = $1`
+	accountBalanceApplySQL  = `INSERT INTO account_balances (account_id, posted_cents, pending_cents, updated_at) VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (account_id) DO UPDATE SET
+			posted_cents = account_balances.posted_cents + EXCLUDED.posted_cents,
+			pending_cents = account_balances.pending_cents + EXCLUDED.pending_cents,
+			updated_at = EXCLUDED.updated_at`
+	accountBalanceRebuildSQL = `INSERT INTO account_balances (account_id, posted_cents, pending_cents, updated_at)
+		SELECT $1,
+			COALESCE(SUM(amount_cents) FILTER (WHERE status = 'POSTED' AND installments = 0), 0),
+			COALESCE(-SUM(amount_cents) FILTER (WHERE status = 'SCHEDULED'), 0)
+				+ (SELECT COALESCE(SUM(amount_cents), 0) FROM authorizations WHERE account_id = $1 AND status = 'PENDING'),
+			NOW()
+		FROM transactions WHERE account_id = $1
+		ON CONFLICT (account_id) DO UPDATE SET
+			posted_cents = EXCLUDED.posted_cents,
+			pending_cents = EXCLUDED.pending_cents,
+			updated_at = EXCLUDED.updated_at
+		RETURNING account_id, posted_cents, pending_cents, updated_at`
+)
+
+type AccountBalanceRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewAccountBalanceRepository(db *sql.DB) *AccountBalanceRepository {
+	return &AccountBalanceRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *AccountBalanceRepository) FindByAccountID(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	var b domain.AccountBalance
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, accountBalanceSelectSQL, accountID).Scan(&b.AccountID, &b.PostedCents, &b.PendingCents, &b.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.AccountBalance{AccountID: accountID}, nil
+		}
+		return domain.AccountBalance{}, fmt.Errorf("failed to find account balance: %w", err)
+	}
+	return b, nil
+}
+
+func (r *AccountBalanceRepository) Apply(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, accountBalanceApplySQL, accountID, postedDelta, pendingDelta); err != nil {
+		return translate(fmt.Errorf("failed to apply account balance: %w", err))
+	}
+	return nil
+}
+
+func (r *AccountBalanceRepository) Rebuild(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	var b domain.AccountBalance
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, accountBalanceRebuildSQL, accountID).Scan(&b.AccountID, &b.PostedCents, &b.PendingCents, &b.UpdatedAt)
+	if err != nil {
+		return domain.AccountBalance{}, translate(fmt.Errorf("failed to rebuild account balance: %w", err))
+	}
+	return b, nil
+}
@@ -5,15 +5,21 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 )
 
 const (
-	accountInsertSQL      = `INSERT INTO accounts (document_number, created_at) VALUES ($1, $2) RETURNING id`
-	accountSelectSQL      = `SELECT id, document_number, created_at FROM accounts WHERE id = $1`
-	accountSelectForUpSQL = `SELECT id, document_number, created_at FROM accounts WHERE id = $1 FOR UPDATE`
+	accountColumns         = `id, document_number, available_credit_limit_cents, closing_day, currency, status, created_at`
+	accountInsertSQL       = `INSERT INTO accounts (document_number, available_credit_limit_cents, closing_day, currency, status, created_at) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+	accountSelectSQL       = `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1`
+	accountSelectForUpSQL  = `SELECT ` + accountColumns + ` FROM accounts WHERE id = $1 FOR UPDATE`
+	accountListAfterSQL    = `SELECT ` + accountColumns + ` FROM accounts WHERE id > $1 ORDER BY id LIMIT $2`
+	accountUpdateLimitSQL  = `UPDATE accounts SET available_credit_limit_cents = $2 WHERE id = $1`
+	accountUpdateStatusSQL = `UPDATE accounts SET status = $2 WHERE id = $1`
 )
 
 type AccountRepository struct {
@@ -32,10 +38,25 @@ func (r *AccountRepository) Create(ctx context.Context, account domain.Account)
 		createdAt = time.Now()
 	}
 
+	closingDay := account.ClosingDay
+	if closingDay == 0 {
+		closingDay = domain.DefaultClosingDay
+	}
+
+	currency := account.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+
+	status := account.Status
+	if status == "" {
+		status = domain.AccountStatusActive
+	}
+
 	var id int64
-	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, accountInsertSQL, account.DocumentNumber, createdAt).Scan(&id)
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, accountInsertSQL, account.DocumentNumber, account.AvailableCreditLimitCents, closingDay, currency, status, createdAt).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create account: %w", err)
+		return 0, translate(fmt.Errorf("failed to create account: %w", err))
 	}
 	return id, nil
 }
@@ -48,9 +69,81 @@ func (r *AccountRepository) FindByIDForUpdate(ctx context.Context, id int64) (do
 	return r.findOne(ctx, accountSelectForUpSQL, id)
 }
 
+// ListAfter pages through all accounts in ID order.
+func (r *AccountRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]domain.Account, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, accountListAfterSQL, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return scanAccounts(rows)
+}
+
+// List pages through accounts by (created_at, id), in either direction, so
+// the indexes on those columns serve both the filters and the sort.
+func (r *AccountRepository) List(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error) {
+	var q strings.Builder
+	var args []any
+	q.WriteString(`SELECT ` + accountColumns + ` FROM accounts WHERE TRUE`)
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.DocumentNumber != "" {
+		q.WriteString(` AND document_number = ` + arg(filter.DocumentNumber))
+	}
+	if filter.Status != "" {
+		q.WriteString(` AND status = ` + arg(filter.Status))
+	}
+	if !filter.From.IsZero() {
+		q.WriteString(` AND created_at >= ` + arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		q.WriteString(` AND created_at < ` + arg(filter.To))
+	}
+
+	cmp, order := "<", "DESC"
+	if filter.Ascending {
+		cmp, order = ">", "ASC"
+	}
+	if filter.After != nil {
+		q.WriteString(` AND (created_at, id) ` + cmp + ` (` + arg(filter.After.Time) + `, ` + arg(filter.After.ID) + `)`)
+	}
+	q.WriteString(` ORDER BY created_at ` + order + `, id ` + order + ` LIMIT ` + arg(filter.Limit))
+
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, q.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	return scanAccounts(rows)
+}
+
+func (r *AccountRepository) UpdateAvailableCreditLimit(ctx context.Context, id int64, limitCents int64) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, accountUpdateLimitSQL, id, limitCents)
+	if err != nil {
+		return translate(fmt.Errorf("failed to update available credit limit: %w", err))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
+func (r *AccountRepository) UpdateStatus(ctx context.Context, id int64, status domain.AccountStatus) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, accountUpdateStatusSQL, id, status)
+	if err != nil {
+		return translate(fmt.Errorf("failed to update account status: %w", err))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrAccountNotFound
+	}
+	return nil
+}
+
 func (r *AccountRepository) findOne(ctx context.Context, query string, id int64) (domain.Account, error) {
 	var acc domain.Account
-	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, query, id).Scan(&acc.ID, &acc.DocumentNumber, &acc.CreatedAt)
+	err := scanAccount(r.tm.GetExecutor(ctx).QueryRowContext(ctx, query, id), &acc)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.Account{}, domain.ErrAccountNotFound
@@ -59,3 +152,25 @@ func (r *AccountRepository) findOne(ctx context.Context, query string, id int64)
 	}
 	return acc, nil
 }
+
+func scanAccounts(rows *sql.Rows) ([]domain.Account, error) {
+	defer rows.Close()
+
+	var out []domain.Account
+	for rows.Next() {
+		var acc domain.Account
+		if err := scanAccount(rows, &acc); err != nil {
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		out = append(out, acc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read accounts: %w", err)
+	}
+
+	return out, nil
+}
+
+func scanAccount(row rowScanner, acc *domain.Account) error {
+	return row.Scan(&acc.ID, &acc.DocumentNumber, &acc.AvailableCreditLimitCents, &acc.ClosingDay, &acc.Currency, &acc.Status, &acc.CreatedAt)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	accountStatusChangeInsertSQL = `INSERT INTO account_status_changes (account_id, from_status, to_status, reason, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+	accountStatusChangeListSQL   = `SELECT id, account_id, from_status, to_status, reason, created_at FROM account_status_changes WHERE account_id = $1 ORDER BY id`
+)
+
+type AccountStatusChangeRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewAccountStatusChangeRepository(db *sql.DB) *AccountStatusChangeRepository {
+	return &AccountStatusChangeRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *AccountStatusChangeRepository) Create(ctx context.Context, change domain.AccountStatusChange) (int64, error) {
+	var id int64
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, accountStatusChangeInsertSQL,
+		change.AccountID, change.From, change.To, change.Reason, change.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translate(fmt.Errorf("failed to create account status change: %w", err))
+	}
+	return id, nil
+}
+
+func (r *AccountStatusChangeRepository) ListByAccount(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, accountStatusChangeListSQL, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list account status changes: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.AccountStatusChange
+	for rows.Next() {
+		var c domain.AccountStatusChange
+		if err := rows.Scan(&c.ID, &c.AccountID, &c.From, &c.To, &c.Reason, &c.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan account status change: %w", err)
+		}
+		out = append(out, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read account status changes: %w", err)
+	}
+
+	return out, nil
+}
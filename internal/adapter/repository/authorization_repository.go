@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	authorizationColumns   = `id, account_id, operation_type_id, amount_cents, captured_cents, currency, reserves_credit_limit, status, COALESCE(transaction_id, 0), expires_at, created_at, updated_at`
+	authorizationInsertSQL = `INSERT INTO authorizations (account_id, operation_type_id, amount_cents, currency, reserves_credit_limit, status, expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $8) RETURNING id`
+	authorizationSelectSQL      = `SELECT ` + authorizationColumns + ` FROM authorizations WHERE id = $1`
+	authorizationSelectForUpSQL = `SELECT ` + authorizationColumns + ` FROM authorizations WHERE id = $1 FOR UPDATE`
+	authorizationCloseSQL       = `UPDATE authorizations SET status = $2, captured_cents = $3, transaction_id = NULLIF($4::bigint, 0), updated_at = $5
+		WHERE id = $1 AND status = 'PENDING'`
+	authorizationExpiredSQL = `SELECT ` + authorizationColumns + ` FROM authorizations WHERE status = 'PENDING' AND expires_at <= $1 ORDER BY expires_at, id LIMIT $2`
+)
+
+type AuthorizationRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewAuthorizationRepository(db *sql.DB) *AuthorizationRepository {
+	return &AuthorizationRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *AuthorizationRepository) Create(ctx context.Context, auth domain.Authorization) (int64, error) {
+	var id int64
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, authorizationInsertSQL,
+		auth.AccountID, auth.OperationTypeID, auth.AmountCents, auth.Currency, auth.ReservesCreditLimit, auth.Status, auth.ExpiresAt, auth.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translate(fmt.Errorf("failed to create authorization: %w", err))
+	}
+	return id, nil
+}
+
+func (r *AuthorizationRepository) FindByID(ctx context.Context, id int64) (domain.Authorization, error) {
+	return r.findOne(ctx, authorizationSelectSQL, id)
+}
+
+func (r *AuthorizationRepository) FindByIDForUpdate(ctx context.Context, id int64) (domain.Authorization, error) {
+	return r.findOne(ctx, authorizationSelectForUpSQL, id)
+}
+
+func (r *AuthorizationRepository) Close(ctx context.Context, auth domain.Authorization) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, authorizationCloseSQL,
+		auth.ID, auth.Status, auth.CapturedCents, auth.TransactionID, auth.UpdatedAt,
+	)
+	if err != nil {
+		return translate(fmt.Errorf("failed to close authorization: %w", err))
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to close authorization: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrAuthorizationClosed
+	}
+	return nil
+}
+
+func (r *AuthorizationRepository) FindExpired(ctx context.Context, asOf time.Time, limit int) ([]domain.Authorization, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, authorizationExpiredSQL, asOf, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find expired authorizations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Authorization
+	for rows.Next() {
+		var auth domain.Authorization
+		if err := scanAuthorization(rows, &auth); err != nil {
+			return nil, fmt.Errorf("failed to scan authorization: %w", err)
+		}
+		out = append(out, auth)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read authorizations: %w", err)
+	}
+
+	return out, nil
+}
+
+func (r *AuthorizationRepository) findOne(ctx context.Context, query string, id int64) (domain.Authorization, error) {
+	var auth domain.Authorization
+	err := scanAuthorization(r.tm.GetExecutor(ctx).QueryRowContext(ctx, query, id), &auth)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Authorization{}, domain.ErrAuthorizationNotFound
+		}
+		return domain.Authorization{}, fmt.Errorf("failed to find authorization: %w", err)
+	}
+	return auth, nil
+}
+
+func scanAuthorization(row rowScanner, auth *domain.Authorization) error {
+	return row.Scan(&auth.ID, &auth.AccountID, &auth.OperationTypeID, &auth.AmountCents, &auth.CapturedCents, &auth.Currency, &auth.ReservesCreditLimit,
+		&auth.Status, &auth.TransactionID, &auth.ExpiresAt, &auth.CreatedAt, &auth.UpdatedAt)
+}
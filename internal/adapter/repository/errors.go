@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// SQLSTATE codes translated into domain errors.
+const (
+	uniqueViolation      pq.ErrorCode = "23505"
+	foreignKeyViolation  pq.ErrorCode = "23503"
+	serializationFailure pq.ErrorCode = "40001"
+	deadlockDetected     pq.ErrorCode = "40P01"
+)
+
+// constraintErrors names the domain error behind each known constraint. The
+// names are the ones Postgres generates for the constraints declared in
+// migrations.
+var constraintErrors = map[string]error{
+	"accounts_document_number_key":           domain.ErrDocumentExists,
+	"operation_types_pkey":                   domain.ErrOperationTypeExists,
+	"transactions_account_id_fkey":           domain.ErrAccountNotFound,
+	"transactions_operation_type_id_fkey":    domain.ErrOperationTypeNotFound,
+	"transactions_reversed_of_fkey":          domain.ErrTransactionNotFound,
+	"transactions_parent_id_fkey":            domain.ErrTransactionNotFound,
+	"account_balances_account_id_fkey":       domain.ErrAccountNotFound,
+	"statements_account_id_fkey":             domain.ErrAccountNotFound,
+	"account_status_changes_account_id_fkey": domain.ErrAccountNotFound,
+	"authorizations_account_id_fkey":         domain.ErrAccountNotFound,
+	"authorizations_operation_type_id_fkey":  domain.ErrOperationTypeNotFound,
+	"authorizations_transaction_id_key":      domain.ErrAuthorizationClosed,
+	"webhook_subscriptions_account_id_fkey":  domain.ErrAccountNotFound,
+}
+
+// translate replaces a Postgres error anywhere in err's chain with the domain
+// error it stands for, so callers never see driver text. Errors it does not
+// recognise are returned unchanged.
+func translate(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case uniqueViolation:
+		if mapped, ok := constraintErrors[pqErr.Constraint]; ok {
+			return mapped
+		}
+		return domain.ErrConflict
+	case foreignKeyViolation:
+		if mapped, ok := constraintErrors[pqErr.Constraint]; ok {
+			return mapped
+		}
+		return domain.ErrReferenceNotFound
+	case serializationFailure, deadlockDetected:
+		return domain.ErrConcurrentUpdate
+	}
+	return err
+}
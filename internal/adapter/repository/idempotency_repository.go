@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	// An expired key is taken over by the new request instead of conflicting.
+	idempotencyReserveSQL = `INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (scope, key) DO UPDATE SET
+			request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response_body = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= EXCLUDED.created_at
+		RETURNING key`
+	idempotencySelectSQL = `SELECT scope, key, request_hash, COALESCE(status_code, 0), COALESCE(content_type, ''), response_body, created_at, expires_at FROM idempotency_keys WHERE scope = $1 AND key = $2`
+	// Both only touch the reservation made by the same request: created_at
+	// changes when an expired lease is taken over.
+	idempotencyCompleteSQL = `UPDATE idempotency_keys SET status_code = $4, content_type = $5, response_body = $6, expires_at = $7
+		WHERE scope = $1 AND key = $2 AND created_at = $3 AND status_code IS NULL`
+	idempotencyDeleteSQL = `DELETE FROM idempotency_keys WHERE scope = $1 AND key = $2 AND created_at = $3 AND status_code IS NULL`
+)
+
+type IdempotencyRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	// The stored row can be released between both statements, so try twice.
+	for range 2 {
+		var key string
+		err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, idempotencyReserveSQL,
+			record.Scope, record.Key, record.RequestHash, record.CreatedAt, record.ExpiresAt,
+		).Scan(&key)
+		if err == nil {
+			return record, true, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return domain.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+
+		var existing domain.IdempotencyRecord
+		err = r.tm.GetExecutor(ctx).QueryRowContext(ctx, idempotencySelectSQL, record.Scope, record.Key).Scan(
+			&existing.Scope, &existing.Key, &existing.RequestHash, &existing.StatusCode,
+			&existing.ContentType, &existing.ResponseBody, &existing.CreatedAt, &existing.ExpiresAt,
+		)
+		if err == nil {
+			return existing, false, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return domain.IdempotencyRecord{}, false, fmt.Errorf("failed to find idempotency key: %w", err)
+		}
+	}
+
+	return domain.IdempotencyRecord{}, false, fmt.Errorf("failed to reserve idempotency key %q", record.Key)
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, idempotencyCompleteSQL,
+		record.Scope, record.Key, record.CreatedAt, record.StatusCode, record.ContentType, record.ResponseBody, record.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("failed to complete idempotency key: %w", domain.ErrConcurrentUpdate)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, record domain.IdempotencyRecord) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, idempotencyDeleteSQL, record.Scope, record.Key, record.CreatedAt); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
@@ -9,7 +9,15 @@ import (
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 )
 
-const operationTypeSelectSQL = `SELECT id, description, sign FROM operation_types WHERE id = $1`
+const (
+	operationTypeColumns        = `id, description, sign, affects_credit_limit, reversible, active, created_at, updated_at`
+	operationTypeSelectSQL      = `SELECT ` + operationTypeColumns + ` FROM operation_types WHERE id = $1`
+	operationTypeSelectForUpSQL = `SELECT ` + operationTypeColumns + ` FROM operation_types WHERE id = $1 FOR UPDATE`
+	operationTypeListSQL        = `SELECT ` + operationTypeColumns + ` FROM operation_types ORDER BY id`
+	operationTypeInsertSQL      = `INSERT INTO operation_types (id, description, sign, affects_credit_limit, reversible, active, created_at, updated_at)
+		VALUES (COALESCE($1, nextval('operation_types_id_seq')), $2, $3, $4, $5, $6, $7, $7) RETURNING id`
+	operationTypeUpdateSQL = `UPDATE operation_types SET description = $2, affects_credit_limit = $3, reversible = $4, active = $5, updated_at = $6 WHERE id = $1`
+)
 
 type OperationTypeRepository struct {
 	tm *TransactionManagerDB
@@ -22,8 +30,16 @@ func NewOperationTypeRepository(db *sql.DB) *OperationTypeRepository {
 }
 
 func (r *OperationTypeRepository) FindByID(ctx context.Context, id int) (domain.OperationType, error) {
+	return r.findOne(ctx, operationTypeSelectSQL, id)
+}
+
+func (r *OperationTypeRepository) FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error) {
+	return r.findOne(ctx, operationTypeSelectForUpSQL, id)
+}
+
+func (r *OperationTypeRepository) findOne(ctx context.Context, query string, id int) (domain.OperationType, error) {
 	var ot domain.OperationType
-	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, operationTypeSelectSQL, id).Scan(&ot.ID, &ot.Description, &ot.Sign)
+	err := scanOperationType(r.tm.GetExecutor(ctx).QueryRowContext(ctx, query, id), &ot)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.OperationType{}, domain.ErrOperationTypeNotFound
@@ -33,6 +49,57 @@ func (r *OperationTypeRepository) FindByID(ctx context.Context, id int) (domain.
 	return ot, nil
 }
 
-func (r *OperationTypeRepository) SeedDefaults(ctx context.Context) error {
+func (r *OperationTypeRepository) List(ctx context.Context) ([]domain.OperationType, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, operationTypeListSQL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operation types: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.OperationType
+	for rows.Next() {
+		var ot domain.OperationType
+		if err := scanOperationType(rows, &ot); err != nil {
+			return nil, fmt.Errorf("failed to scan operation type: %w", err)
+		}
+		out = append(out, ot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read operation types: %w", err)
+	}
+
+	return out, nil
+}
+
+func (r *OperationTypeRepository) Create(ctx context.Context, ot domain.OperationType) (int, error) {
+	var id sql.NullInt64
+	if ot.ID != 0 {
+		id = sql.NullInt64{Int64: int64(ot.ID), Valid: true}
+	}
+
+	var created int
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, operationTypeInsertSQL,
+		id, ot.Description, ot.Sign, ot.AffectsCreditLimit, ot.Reversible, ot.Active, ot.CreatedAt,
+	).Scan(&created)
+	if err != nil {
+		return 0, translate(fmt.Errorf("failed to create operation type: %w", err))
+	}
+	return created, nil
+}
+
+func (r *OperationTypeRepository) Update(ctx context.Context, ot domain.OperationType) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, operationTypeUpdateSQL,
+		ot.ID, ot.Description, ot.AffectsCreditLimit, ot.Reversible, ot.Active, ot.UpdatedAt,
+	)
+	if err != nil {
+		return translate(fmt.Errorf("failed to update operation type: %w", err))
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrOperationTypeNotFound
+	}
 	return nil
 }
+
+func scanOperationType(row rowScanner, ot *domain.OperationType) error {
+	return row.Scan(&ot.ID, &ot.Description, &ot.Sign, &ot.AffectsCreditLimit, &ot.Reversible, &ot.Active, &ot.CreatedAt, &ot.UpdatedAt)
+}
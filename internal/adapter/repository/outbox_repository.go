@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	outboxInsertSQL = `INSERT INTO outbox (event_type, account_id, payload, next_attempt_at, created_at) VALUES ($1, $2, $3, $4, $5)`
+	// An event is claimable only when no older event of its account is still
+	// unpublished, dead events aside; the head of a queue leased to another relay blocks the rest
+	// of that queue, and SKIP LOCKED moves on from rows being claimed at the
+	// same time. Claiming pushes next_attempt_at to the end of the lease.
+	outboxClaimSQL = `WITH claimed AS (
+			UPDATE outbox SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT o.id FROM outbox o
+				WHERE o.published_at IS NULL AND o.dead_at IS NULL AND o.next_attempt_at <= $1
+					AND NOT EXISTS (
+						SELECT 1 FROM outbox p
+						WHERE p.account_id = o.account_id AND p.published_at IS NULL AND p.dead_at IS NULL AND p.id < o.id
+					)
+				ORDER BY o.id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, event_type, account_id, payload, attempts, next_attempt_at, last_error, created_at
+		)
+		SELECT * FROM claimed ORDER BY id`
+	outboxPublishedSQL = `UPDATE outbox SET published_at = $2 WHERE id = $1`
+	outboxFailedSQL    = `UPDATE outbox SET attempts = $2, next_attempt_at = $3, last_error = $4 WHERE id = $1`
+	outboxDeadSQL      = `UPDATE outbox SET attempts = $2, dead_at = $3, last_error = $4 WHERE id = $1`
+	outboxByAccountSQL = `SELECT id, event_type, account_id, payload, attempts, next_attempt_at, last_error, created_at
+		FROM outbox
+		WHERE account_id = $1 AND event_type = $2 AND id > $3
+		ORDER BY id
+		LIMIT $4`
+	outboxLatestIDSQL = `SELECT COALESCE(MAX(id), 0) FROM outbox WHERE account_id = $1 AND event_type = $2`
+)
+
+type OutboxRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *OutboxRepository) Add(ctx context.Context, event domain.Event) error {
+	_, err := r.tm.GetExecutor(ctx).ExecContext(ctx, outboxInsertSQL,
+		event.Type, event.AccountID, string(event.Payload), event.NextAttemptAt, event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add event to outbox: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) ClaimPending(ctx context.Context, asOf, until time.Time, limit int) ([]domain.Event, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, outboxClaimSQL, asOf, limit, until)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim events: %w", err)
+	}
+	return scanEvents(rows)
+}
+
+func (r *OutboxRepository) ListByAccount(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, outboxByAccountSQL, accountID, eventType, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return scanEvents(rows)
+}
+
+func (r *OutboxRepository) LatestID(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error) {
+	var id int64
+	if err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, outboxLatestIDSQL, accountID, eventType).Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to read latest event id: %w", err)
+	}
+	return id, nil
+}
+
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, outboxPublishedSQL, id, at); err != nil {
+		return fmt.Errorf("failed to mark event published: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, outboxFailedSQL, id, attempts, nextAttemptAt, lastError); err != nil {
+		return fmt.Errorf("failed to record event failure: %w", err)
+	}
+	return nil
+}
+
+func (r *OutboxRepository) MarkDead(ctx context.Context, id int64, attempts int, at time.Time, lastError string) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, outboxDeadSQL, id, attempts, at, lastError); err != nil {
+		return fmt.Errorf("failed to mark event dead: %w", err)
+	}
+	return nil
+}
+
+func scanEvents(rows *sql.Rows) ([]domain.Event, error) {
+	defer rows.Close()
+
+	var out []domain.Event
+	for rows.Next() {
+		var e domain.Event
+		if err := rows.Scan(&e.ID, &e.Type, &e.AccountID, &e.Payload, &e.Attempts, &e.NextAttemptAt, &e.LastError, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan event: %w", err)
+		}
+		out = append(out, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	statementColumns   = `id, account_id, currency, period_start, period_end, opening_balance_cents, debits_cents, credits_cents, closing_balance_cents, total_due_cents, minimum_payment_cents, due_date, closed_at`
+	statementInsertSQL = `INSERT INTO statements (account_id, currency, period_start, period_end, opening_balance_cents, debits_cents, credits_cents, closing_balance_cents, total_due_cents, minimum_payment_cents, due_date, closed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id`
+	statementSelectSQL = `SELECT ` + statementColumns + ` FROM statements WHERE id = $1`
+	statementLatestSQL = `SELECT ` + statementColumns + ` FROM statements WHERE account_id = $1 ORDER BY period_end DESC LIMIT 1`
+	statementListSQL   = `SELECT ` + statementColumns + ` FROM statements WHERE account_id = $1 ORDER BY period_end DESC`
+)
+
+type StatementRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewStatementRepository(db *sql.DB) *StatementRepository {
+	return &StatementRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *StatementRepository) Create(ctx context.Context, s domain.Statement) (int64, error) {
+	var id int64
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, statementInsertSQL,
+		s.AccountID, s.Currency, s.PeriodStart, s.PeriodEnd, s.OpeningBalanceCents, s.DebitsCents, s.CreditsCents,
+		s.ClosingBalanceCents, s.TotalDueCents, s.MinimumPaymentCents, s.DueDate, s.ClosedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translate(fmt.Errorf("failed to create statement: %w", err))
+	}
+	return id, nil
+}
+
+func (r *StatementRepository) FindByID(ctx context.Context, id int64) (domain.Statement, error) {
+	return r.findOne(ctx, statementSelectSQL, id)
+}
+
+func (r *StatementRepository) FindLatest(ctx context.Context, accountID int64) (domain.Statement, error) {
+	return r.findOne(ctx, statementLatestSQL, accountID)
+}
+
+func (r *StatementRepository) ListByAccount(ctx context.Context, accountID int64) ([]domain.Statement, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, statementListSQL, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statements: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.Statement
+	for rows.Next() {
+		var s domain.Statement
+		if err := scanStatement(rows, &s); err != nil {
+			return nil, fmt.Errorf("failed to scan statement: %w", err)
+		}
+		out = append(out, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read statements: %w", err)
+	}
+
+	return out, nil
+}
+
+func (r *StatementRepository) findOne(ctx context.Context, query string, arg int64) (domain.Statement, error) {
+	var s domain.Statement
+	err := scanStatement(r.tm.GetExecutor(ctx).QueryRowContext(ctx, query, arg), &s)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Statement{}, domain.ErrStatementNotFound
+		}
+		return domain.Statement{}, fmt.Errorf("failed to find statement: %w", err)
+	}
+	return s, nil
+}
+
+func scanStatement(row rowScanner, s *domain.Statement) error {
+	return row.Scan(&s.ID, &s.AccountID, &s.Currency, &s.PeriodStart, &s.PeriodEnd, &s.OpeningBalanceCents, &s.DebitsCents, &s.CreditsCents,
+		&s.ClosingBalanceCents, &s.TotalDueCents, &s.MinimumPaymentCents, &s.DueDate, &s.ClosedAt)
+}
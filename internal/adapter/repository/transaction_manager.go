@@ -18,6 +18,9 @@ func NewTransactionManager(db *sql.DB) *TransactionManagerDB {
 	return &TransactionManagerDB{db: db}
 }
 
+// RunInTransaction runs fn in a database transaction, committing when it
+// returns nil. Postgres errors raised inside fn or on commit, such as a
+// deadlock between two FOR UPDATE locks, come back as domain errors.
 func (tm *TransactionManagerDB) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
 	tx, err := tm.db.BeginTx(ctx, nil)
 	if err != nil {
@@ -30,11 +33,11 @@ func (tm *TransactionManagerDB) RunInTransaction(ctx context.Context, fn func(ct
 		if rbErr := tx.Rollback(); rbErr != nil {
 			return fmt.Errorf("fn error: %v, rollback error: %v", err, rbErr)
 		}
-		return err
+		return translate(err)
 	}
 
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return translate(fmt.Errorf("failed to commit transaction: %w", err))
 	}
 
 	return nil
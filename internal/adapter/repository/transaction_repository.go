@@ -3,13 +3,29 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 )
 
-const transactionInsertSQL = `INSERT INTO transactions (account_id, operation_type_id, amount_cents, event_date, created_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`
+const (
+	transactionColumns   = `id, account_id, operation_type_id, amount_cents, balance_cents, currency, COALESCE(original_currency, ''), COALESCE(original_amount_cents, 0), COALESCE(reversed_of, 0), affects_credit_limit, status, COALESCE(parent_id, 0), installment_number, installments, event_date, created_at`
+	transactionInsertSQL = `INSERT INTO transactions (account_id, operation_type_id, amount_cents, balance_cents, currency, original_currency, original_amount_cents, reversed_of, affects_credit_limit, status, parent_id, installment_number, installments, event_date, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7::bigint, 0), NULLIF($8::bigint, 0), $9, $10, NULLIF($11::bigint, 0), $12, $13, $14, $15) RETURNING id`
+	transactionSumReversalsSQL  = `SELECT COALESCE(SUM(ABS(amount_cents)), 0) FROM transactions WHERE reversed_of = $1`
+	transactionSelectSQL        = `SELECT ` + transactionColumns + ` FROM transactions WHERE id = $1`
+	transactionUpdateBalanceSQL = `UPDATE transactions SET balance_cents = $2 WHERE id = $1`
+	transactionOpenDebitsSQL    = `SELECT ` + transactionColumns + ` FROM transactions WHERE account_id = $1 AND balance_cents < 0 ORDER BY event_date, id FOR UPDATE`
+	transactionInstallmentsSQL  = `SELECT ` + transactionColumns + ` FROM transactions WHERE parent_id = $1 ORDER BY installment_number`
+	transactionDueSQL           = `SELECT ` + transactionColumns + ` FROM transactions WHERE status = 'SCHEDULED' AND event_date <= $1 ORDER BY event_date, id LIMIT $2`
+	transactionPostSQL          = `UPDATE transactions SET status = 'POSTED', balance_cents = $2 WHERE id = $1 AND status = 'SCHEDULED'`
+	transactionSumPeriodSQL     = `SELECT COALESCE(-SUM(amount_cents) FILTER (WHERE amount_cents < 0), 0), COALESCE(SUM(amount_cents) FILTER (WHERE amount_cents > 0), 0)
+		FROM transactions WHERE account_id = $1 AND event_date >= $2 AND event_date < $3 AND installments = 0`
+)
 
 type TransactionRepository struct {
 	tm *TransactionManagerDB
@@ -26,14 +42,158 @@ func (r *TransactionRepository) Create(ctx context.Context, tx domain.Transactio
 	if createdAt.IsZero() {
 		createdAt = time.Now()
 	}
+	currency := tx.Currency
+	if currency == "" {
+		currency = domain.DefaultCurrency
+	}
+	status := tx.Status
+	if status == "" {
+		status = domain.TransactionStatusPosted
+	}
 
 	var id int64
 	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, transactionInsertSQL,
-		tx.AccountID, tx.OperationTypeID, tx.AmountCents, tx.EventDate, createdAt,
+		tx.AccountID, tx.OperationTypeID, tx.AmountCents, tx.BalanceCents, currency, tx.OriginalCurrency, tx.OriginalAmountCents, tx.ReversedOf, tx.AffectsCreditLimit, status,
+		tx.ParentID, tx.InstallmentNumber, tx.Installments, tx.EventDate, createdAt,
 	).Scan(&id)
 	if err != nil {
-		return 0, fmt.Errorf("failed to create transaction: %w", err)
+		return 0, translate(fmt.Errorf("failed to create transaction: %w", err))
 	}
 
 	return id, nil
 }
+
+func (r *TransactionRepository) FindByID(ctx context.Context, id int64) (domain.Transaction, error) {
+	var tx domain.Transaction
+	err := scanTransaction(r.tm.GetExecutor(ctx).QueryRowContext(ctx, transactionSelectSQL, id), &tx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Transaction{}, domain.ErrTransactionNotFound
+		}
+		return domain.Transaction{}, fmt.Errorf("failed to find transaction: %w", err)
+	}
+	return tx, nil
+}
+
+func (r *TransactionRepository) FindOpenDebitsForUpdate(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, transactionOpenDebitsSQL, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find open debits: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+// ListByAccount pages through (event_date, id) in descending order so the
+// (account_id, event_date) index serves both the filter and the sort.
+func (r *TransactionRepository) ListByAccount(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	var q strings.Builder
+	args := []any{filter.AccountID}
+	q.WriteString(`SELECT ` + transactionColumns + ` FROM transactions WHERE account_id = $1`)
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.OperationTypeID != 0 {
+		q.WriteString(` AND operation_type_id = ` + arg(filter.OperationTypeID))
+	}
+	if !filter.From.IsZero() {
+		q.WriteString(` AND event_date >= ` + arg(filter.From))
+	}
+	if !filter.To.IsZero() {
+		q.WriteString(` AND event_date < ` + arg(filter.To))
+	}
+	if filter.After != nil {
+		q.WriteString(` AND (event_date, id) < (` + arg(filter.After.Time) + `, ` + arg(filter.After.ID) + `)`)
+	}
+	q.WriteString(` ORDER BY event_date DESC, id DESC LIMIT ` + arg(filter.Limit))
+
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, q.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list transactions: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+func (r *TransactionRepository) UpdateBalance(ctx context.Context, id int64, balanceCents int64) error {
+	if _, err := r.tm.GetExecutor(ctx).ExecContext(ctx, transactionUpdateBalanceSQL, id, balanceCents); err != nil {
+		return translate(fmt.Errorf("failed to update transaction balance: %w", err))
+	}
+	return nil
+}
+
+func (r *TransactionRepository) ListInstallments(ctx context.Context, parentID int64) ([]domain.Transaction, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, transactionInstallmentsSQL, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list installments: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+func (r *TransactionRepository) FindDueScheduled(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, transactionDueSQL, asOf, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find due transactions: %w", err)
+	}
+	return scanTransactions(rows)
+}
+
+func (r *TransactionRepository) MarkPosted(ctx context.Context, id int64, balanceCents int64) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, transactionPostSQL, id, balanceCents)
+	if err != nil {
+		return translate(fmt.Errorf("failed to post transaction: %w", err))
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to post transaction: %w", err)
+	}
+	if n == 0 {
+		return domain.ErrTransactionNotFound
+	}
+	return nil
+}
+
+func (r *TransactionRepository) SumByPeriod(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error) {
+	var debits, credits int64
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, transactionSumPeriodSQL, accountID, from, to).Scan(&debits, &credits)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to sum transactions: %w", err)
+	}
+	return debits, credits, nil
+}
+
+func (r *TransactionRepository) SumReversals(ctx context.Context, id int64) (int64, error) {
+	var sum int64
+	if err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, transactionSumReversalsSQL, id).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("failed to sum reversals: %w", err)
+	}
+	return sum, nil
+}
+
+func scanTransactions(rows *sql.Rows) ([]domain.Transaction, error) {
+	defer rows.Close()
+
+	var out []domain.Transaction
+	for rows.Next() {
+		var tx domain.Transaction
+		if err := scanTransaction(rows, &tx); err != nil {
+			return nil, fmt.Errorf("failed to scan transaction: %w", err)
+		}
+		out = append(out, tx)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transactions: %w", err)
+	}
+
+	return out, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTransaction(row rowScanner, tx *domain.Transaction) error {
+	return row.Scan(&tx.ID, &tx.AccountID, &tx.OperationTypeID, &tx.AmountCents, &tx.BalanceCents, &tx.Currency, &tx.OriginalCurrency, &tx.OriginalAmountCents, &tx.ReversedOf, &tx.AffectsCreditLimit,
+		&tx.Status, &tx.ParentID, &tx.InstallmentNumber, &tx.Installments, &tx.EventDate, &tx.CreatedAt)
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+const (
+	webhookColumns   = `id, url, secret, event_types, COALESCE(account_id, 0), active, created_at, updated_at`
+	webhookInsertSQL = `INSERT INTO webhook_subscriptions (url, secret, event_types, account_id, active, created_at, updated_at)
+		VALUES ($1, $2, $3, NULLIF($4::bigint, 0), $5, $6, $6) RETURNING id`
+	webhookSelectSQL     = `SELECT ` + webhookColumns + ` FROM webhook_subscriptions WHERE id = $1`
+	webhookListSQL       = `SELECT ` + webhookColumns + ` FROM webhook_subscriptions ORDER BY id`
+	webhookDeactivateSQL = `UPDATE webhook_subscriptions SET active = FALSE, updated_at = $2 WHERE id = $1`
+	webhookMatchingSQL   = `SELECT ` + webhookColumns + ` FROM webhook_subscriptions
+		WHERE active
+			AND (account_id IS NULL OR account_id = $2)
+			AND (cardinality(event_types) = 0 OR $1 = ANY(event_types))
+		ORDER BY id`
+
+	deliveryColumns   = `id, subscription_id, event_id, event_type, account_id, payload, occurred_at, status, attempts, next_attempt_at, last_error, last_status_code, delivered_at, created_at`
+	deliveryInsertSQL = `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, account_id, payload, occurred_at, status, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (subscription_id, event_id) DO NOTHING`
+	deliverySelectSQL = `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+	// Claiming pushes next_attempt_at to the end of the lease, so that other
+	// workers skip the deliveries once the claim is committed.
+	deliveryClaimSQL = `WITH claimed AS (
+			UPDATE webhook_deliveries SET next_attempt_at = $3
+			WHERE id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'PENDING' AND next_attempt_at <= $1
+				ORDER BY next_attempt_at, id
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + deliveryColumns + `
+		)
+		SELECT * FROM claimed ORDER BY id`
+	// Updates only apply to the delivery as the caller saw it: a claim moves
+	// next_attempt_at, and so does any other update.
+	deliveryUpdateSQL = `UPDATE webhook_deliveries SET status = $2, attempts = $3, next_attempt_at = $4, last_error = $5, last_status_code = $6, delivered_at = $7
+		WHERE id = $1 AND next_attempt_at = $8`
+)
+
+type WebhookSubscriptionRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewWebhookSubscriptionRepository(db *sql.DB) *WebhookSubscriptionRepository {
+	return &WebhookSubscriptionRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *WebhookSubscriptionRepository) Create(ctx context.Context, sub domain.WebhookSubscription) (int64, error) {
+	types := make([]string, len(sub.EventTypes))
+	for i, t := range sub.EventTypes {
+		types[i] = string(t)
+	}
+
+	var id int64
+	err := r.tm.GetExecutor(ctx).QueryRowContext(ctx, webhookInsertSQL,
+		sub.URL, sub.Secret, pq.Array(types), sub.AccountID, sub.Active, sub.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, translate(fmt.Errorf("failed to create webhook subscription: %w", err))
+	}
+	return id, nil
+}
+
+func (r *WebhookSubscriptionRepository) FindByID(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	if err := scanWebhook(r.tm.GetExecutor(ctx).QueryRowContext(ctx, webhookSelectSQL, id), &sub); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+		}
+		return domain.WebhookSubscription{}, fmt.Errorf("failed to find webhook subscription: %w", err)
+	}
+	return sub, nil
+}
+
+func (r *WebhookSubscriptionRepository) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, webhookListSQL)
+}
+
+func (r *WebhookSubscriptionRepository) FindMatching(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error) {
+	return r.list(ctx, webhookMatchingSQL, string(event.Type), event.AccountID)
+}
+
+func (r *WebhookSubscriptionRepository) Deactivate(ctx context.Context, id int64, at time.Time) error {
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, webhookDeactivateSQL, id, at)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate webhook subscription: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (r *WebhookSubscriptionRepository) list(ctx context.Context, query string, args ...any) ([]domain.WebhookSubscription, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookSubscription
+	for rows.Next() {
+		var sub domain.WebhookSubscription
+		if err := scanWebhook(rows, &sub); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+		}
+		out = append(out, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook subscriptions: %w", err)
+	}
+
+	return out, nil
+}
+
+func scanWebhook(row rowScanner, sub *domain.WebhookSubscription) error {
+	var types []string
+	if err := row.Scan(&sub.ID, &sub.URL, &sub.Secret, pq.Array(&types), &sub.AccountID, &sub.Active, &sub.CreatedAt, &sub.UpdatedAt); err != nil {
+		return err
+	}
+	sub.EventTypes = make([]domain.EventType, len(types))
+	for i, t := range types {
+		sub.EventTypes[i] = domain.EventType(t)
+	}
+	return nil
+}
+
+type WebhookDeliveryRepository struct {
+	tm *TransactionManagerDB
+}
+
+func NewWebhookDeliveryRepository(db *sql.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{
+		tm: NewTransactionManager(db),
+	}
+}
+
+func (r *WebhookDeliveryRepository) Enqueue(ctx context.Context, d domain.WebhookDelivery) error {
+	_, err := r.tm.GetExecutor(ctx).ExecContext(ctx, deliveryInsertSQL,
+		d.SubscriptionID, d.EventID, d.EventType, d.AccountID, string(d.Payload), d.OccurredAt, d.Status, d.NextAttemptAt, d.CreatedAt,
+	)
+	if err != nil {
+		return translate(fmt.Errorf("failed to enqueue webhook delivery: %w", err))
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) FindByID(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	if err := scanDelivery(r.tm.GetExecutor(ctx).QueryRowContext(ctx, deliverySelectSQL, id), &d); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.WebhookDelivery{}, domain.ErrDeliveryNotFound
+		}
+		return domain.WebhookDelivery{}, fmt.Errorf("failed to find webhook delivery: %w", err)
+	}
+	return d, nil
+}
+
+func (r *WebhookDeliveryRepository) ListBySubscription(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var q strings.Builder
+	var args []any
+	q.WriteString(`SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1`)
+	args = append(args, filter.SubscriptionID)
+
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if filter.Status != "" {
+		q.WriteString(` AND status = ` + arg(filter.Status))
+	}
+	if filter.After != nil {
+		q.WriteString(` AND (created_at, id) < (` + arg(filter.After.Time) + `, ` + arg(filter.After.ID) + `)`)
+	}
+	q.WriteString(` ORDER BY created_at DESC, id DESC LIMIT ` + arg(filter.Limit))
+
+	return r.list(ctx, q.String(), args...)
+}
+
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return r.list(ctx, deliveryClaimSQL, asOf, limit, until)
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, d domain.WebhookDelivery, seen time.Time) error {
+	deliveredAt := sql.NullTime{Time: d.DeliveredAt, Valid: !d.DeliveredAt.IsZero()}
+
+	res, err := r.tm.GetExecutor(ctx).ExecContext(ctx, deliveryUpdateSQL,
+		d.ID, d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.LastStatusCode, deliveredAt, seen,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		if _, err := r.FindByID(ctx, d.ID); err != nil {
+			return err
+		}
+		return fmt.Errorf("failed to update webhook delivery: %w", domain.ErrConcurrentUpdate)
+	}
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) list(ctx context.Context, query string, args ...any) ([]domain.WebhookDelivery, error) {
+	rows, err := r.tm.GetExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []domain.WebhookDelivery
+	for rows.Next() {
+		var d domain.WebhookDelivery
+		if err := scanDelivery(rows, &d); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		out = append(out, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+
+	return out, nil
+}
+
+func scanDelivery(row rowScanner, d *domain.WebhookDelivery) error {
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.AccountID, &d.Payload, &d.OccurredAt, &d.Status,
+		&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.LastStatusCode, &deliveredAt, &d.CreatedAt)
+	if err != nil {
+		return err
+	}
+	d.DeliveredAt = deliveredAt.Time
+	return nil
+}
@@ -0,0 +1,255 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Where a setting came from, as reported by Settings.
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// ConfigFileEnv names the config file when the -config flag is not given.
+const ConfigFileEnv = "CONFIG_FILE"
+
+// Load builds the configuration from the defaults, the config file, the
+// environment and the flags in args, and validates it. Parsing stops at the
+// first argument that is not a flag; that argument and the ones after it are
+// returned. An error wrapping flag.ErrHelp means -h was given.
+func Load(args []string) (Config, []string, error) {
+	cfg := Default()
+	cfg.sources = make(map[string]string)
+	fields := cfg.fields()
+
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv(ConfigFileEnv), "YAML or JSON config file (env "+ConfigFileEnv+")")
+
+	type flagValue struct {
+		field *field
+		raw   string
+	}
+	var flagValues []flagValue
+	for i := range fields {
+		f := &fields[i]
+		usage := fmt.Sprintf("%s (default %q, env %s)", f.usage, f.display(), f.env)
+		record := func(raw string) error {
+			flagValues = append(flagValues, flagValue{field: f, raw: raw})
+			return nil
+		}
+		if f.value.Kind() == reflect.Bool {
+			fs.BoolFunc(f.flag(), usage, record)
+		} else {
+			fs.Func(f.flag(), usage, record)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if *configFile != "" {
+		if err := cfg.loadFile(fields, *configFile); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	var errs []error
+	for i := range fields {
+		f := &fields[i]
+		raw, ok := os.LookupEnv(f.env)
+		// An empty variable unsets nothing but a string.
+		if !ok || (raw == "" && f.value.Kind() != reflect.String) {
+			continue
+		}
+		if err := f.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("env %s: %w", f.env, err))
+			continue
+		}
+		cfg.sources[f.key] = SourceEnv
+	}
+	for _, fv := range flagValues {
+		if err := fv.field.set(fv.raw); err != nil {
+			errs = append(errs, fmt.Errorf("flag -%s: %w", fv.field.flag(), err))
+			continue
+		}
+		cfg.sources[fv.field.key] = SourceFlag
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// loadFile applies a flat YAML or JSON object of keys to values. Unknown keys
+// are errors, so that a typo does not go unnoticed.
+func (c *Config) loadFile(fields []field, path string) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		err = dec.Decode(&raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(body, &raw)
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	byKey := make(map[string]*field, len(fields))
+	for i := range fields {
+		byKey[fields[i].key] = &fields[i]
+	}
+
+	keys := make([]string, 0, len(raw))
+	for k := range raw {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, k := range keys {
+		f, ok := byKey[k]
+		if !ok {
+			errs = append(errs, fmt.Errorf("config file %s: unknown key %q", path, k))
+			continue
+		}
+		v, ok := scalar(raw[k])
+		if !ok {
+			errs = append(errs, fmt.Errorf("config file %s: %s must be a single value", path, k))
+			continue
+		}
+		if err := f.set(v); err != nil {
+			errs = append(errs, fmt.Errorf("config file %s: %s: %w", path, k, err))
+			continue
+		}
+		c.sources[k] = SourceFile
+	}
+	return errors.Join(errs...)
+}
+
+func scalar(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return v, true
+	case bool, int, int64, uint64, float64, json.Number:
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+// field is one setting of Config, reached through reflection.
+type field struct {
+	key    string
+	env    string
+	usage  string
+	redact bool
+	value  reflect.Value
+}
+
+func (c *Config) fields() []field {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	var out []field
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		key := sf.Tag.Get("key")
+		if key == "" {
+			continue
+		}
+		out = append(out, field{
+			key:    key,
+			env:    sf.Tag.Get("env"),
+			usage:  sf.Tag.Get("usage"),
+			redact: sf.Tag.Get("redact") == "true",
+			value:  v.Field(i),
+		})
+	}
+	return out
+}
+
+func (f field) flag() string {
+	return strings.ReplaceAll(f.key, "_", "-")
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (f field) set(raw string) error {
+	if f.value.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		f.value.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.value.Kind() {
+	case reflect.String:
+		f.value.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		f.value.SetInt(int64(n))
+	case reflect.Float64:
+		x, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		f.value.SetFloat(x)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		f.value.SetBool(b)
+	default:
+		return fmt.Errorf("unsupported setting type %s", f.value.Type())
+	}
+	return nil
+}
+
+// String renders the value the way it is written in the config file.
+func (f field) String() string {
+	if f.value.Type() == durationType {
+		return time.Duration(f.value.Int()).String()
+	}
+	switch f.value.Kind() {
+	case reflect.Float64:
+		return strconv.FormatFloat(f.value.Float(), 'g', -1, 64)
+	default:
+		return fmt.Sprint(f.value.Interface())
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted replaces credentials in printed settings.
+const Redacted = "REDACTED"
+
+// Setting is one effective setting, with credentials masked.
+type Setting struct {
+	Key    string
+	Env    string
+	Value  string
+	Source string
+}
+
+// Settings lists every setting in declaration order.
+func (c Config) Settings() []Setting {
+	fields := c.fields()
+	out := make([]Setting, 0, len(fields))
+	for _, f := range fields {
+		source := c.sources[f.key]
+		if source == "" {
+			source = SourceDefault
+		}
+		out = append(out, Setting{Key: f.key, Env: f.env, Value: f.display(), Source: source})
+	}
+	return out
+}
+
+// Dump writes the effective configuration as a YAML config file, each
+// setting commented with where it came from.
+func (c Config) Dump(w io.Writer) error {
+	for _, f := range c.fields() {
+		var v any = f.display()
+		if !f.redact && f.value.Type() != durationType {
+			v = f.value.Interface()
+		}
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		source := c.sources[f.key]
+		if source == "" {
+			source = SourceDefault
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s # %s\n", f.key, strings.TrimSpace(string(out)), source); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// String renders the settings on one line, with credentials masked, so that
+// printing a Config never leaks them.
+func (c Config) String() string {
+	var b strings.Builder
+	for i, s := range c.Settings() {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(&b, "%s=%q", s.Key, s.Value)
+	}
+	return b.String()
+}
+
+// display is the value as printed, with credentials masked.
+func (f field) display() string {
+	if f.value.Type() == durationType {
+		return time.Duration(f.value.Int()).String()
+	}
+	if f.redact {
+		return redact(f.String())
+	}
+	return f.String()
+}
+
+// secretParam matches credentials given as key=value, in keyword DSNs such as
+// "host=db password=secret" or in URL query strings.
+var secretParam = regexp.MustCompile(`(?i)\b(password|passwd|secret|token|api_key|apikey)=('[^']*'|[^\s&]*)`)
+
+// redact masks the password of a URL or DSN and any credential parameter,
+// keeping the rest readable.
+func redact(v string) string {
+	if u, err := url.Parse(v); err == nil && u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), Redacted)
+			v = u.String()
+		}
+	}
+	return secretParam.ReplaceAllString(v, "${1}="+Redacted)
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+var (
+	tracesSamplers  = []string{"always_on", "always_off", "traceidratio", "parentbased_always_on", "parentbased_always_off", "parentbased_traceidratio"}
+	eventPublishers = []string{"", "webhook", "file"}
+)
+
+// Validate checks every setting and reports all the problems it finds.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	positive := func(key string, d time.Duration) {
+		check(d > 0, key, "must be positive, got %s", d)
+	}
+
+	check(validPort(c.Port), "port", "invalid port %q", c.Port)
+	check(validPort(c.GRPCPort), "grpc_port", "invalid port %q", c.GRPCPort)
+	check(c.Port != c.GRPCPort, "grpc_port", "must differ from port")
+	check(c.DBDriver != "", "db_driver", "is required")
+	check(c.DBDSN != "", "db_dsn", "is required")
+	var level slog.Level
+	check(level.UnmarshalText([]byte(c.LogLevel)) == nil, "log_level", "must be debug, info, warn or error, got %q", c.LogLevel)
+	check(slices.Contains(tracesSamplers, c.TracesSampler), "traces_sampler", "unknown sampler %q", c.TracesSampler)
+	check(c.TracesSamplerArg >= 0 && c.TracesSamplerArg <= 1, "traces_sampler_arg", "must be between 0 and 1, got %g", c.TracesSamplerArg)
+
+	check(c.DBMaxOpenConns >= 1, "db_max_open_conns", "must be at least 1, got %d", c.DBMaxOpenConns)
+	check(c.DBMaxIdleConns >= 0 && c.DBMaxIdleConns <= c.DBMaxOpenConns, "db_max_idle_conns", "must be between 0 and db_max_open_conns, got %d", c.DBMaxIdleConns)
+	check(c.DBConnMaxLifetime >= 0, "db_conn_max_lifetime", "must not be negative")
+	check(c.DBConnMaxIdleTime >= 0, "db_conn_max_idle_time", "must not be negative")
+
+	positive("request_timeout", c.RequestTimeout)
+	check(c.ShutdownDrainDelay >= 0, "shutdown_drain_delay", "must not be negative")
+	positive("shutdown_timeout", c.ShutdownTimeout)
+
+	positive("idempotency_key_ttl", c.IdempotencyTTL)
+	check(c.IdempotencyLease > c.RequestTimeout, "idempotency_key_lease", "must be longer than request_timeout, got %s", c.IdempotencyLease)
+	_, err := domain.ParseExchangeRates(c.FXRates)
+	check(err == nil, "fx_rates", "%v", err)
+	positive("operation_type_cache_ttl", c.OperationTypeCacheTTL)
+	positive("authorization_ttl", c.AuthorizationTTL)
+
+	check(slices.Contains(eventPublishers, c.EventPublisher), "event_publisher", "must be webhook, file or empty, got %q", c.EventPublisher)
+	check(c.EventPublisher != "webhook" || c.EventWebhookURL != "", "event_webhook_url", "is required by the webhook publisher")
+	positive("event_webhook_timeout", c.EventWebhookTimeout)
+	check(c.EventPublisher != "file" || c.EventFile != "", "event_file", "is required by the file publisher")
+	check(c.EventMaxAttempts >= 1, "event_max_attempts", "must be at least 1, got %d", c.EventMaxAttempts)
+
+	check(c.WebhookMaxAttempts >= 1, "webhook_max_attempts", "must be at least 1, got %d", c.WebhookMaxAttempts)
+	positive("webhook_timeout", c.WebhookTimeout)
+
+	positive("installment_posting_interval", c.InstallmentPostingInterval)
+	positive("statement_closing_interval", c.StatementClosingInterval)
+	positive("authorization_expiry_interval", c.AuthorizationExpiryInterval)
+	positive("outbox_relay_interval", c.OutboxRelayInterval)
+	positive("webhook_delivery_interval", c.WebhookDeliveryInterval)
+
+	return errors.Join(errs...)
+}
+
+func validPort(v string) bool {
+	n, err := strconv.Atoi(v)
+	return err == nil && n > 0 && n <= 65535
+}
@@ -2,8 +2,90 @@ package domain
 
 import "time"
 
+// DefaultDocumentCountry is the issuing country assumed for document numbers
+// when none is given.
+const DefaultDocumentCountry = "BR"
+
+// DefaultCreditLimit returns the available credit limit, in the account's
+// currency, of accounts opened without one.
+func DefaultCreditLimit() Decimal {
+	return Decimal{Units: 1000}
+}
+
+// AccountStatus is where an account is in its lifecycle. Blocked accounts
+// only accept credits; closed accounts accept nothing and stay closed.
+type AccountStatus string
+
+const (
+	AccountStatusActive  AccountStatus = "ACTIVE"
+	AccountStatusBlocked AccountStatus = "BLOCKED"
+	AccountStatusClosed  AccountStatus = "CLOSED"
+)
+
+// Valid reports whether s is a known status.
+func (s AccountStatus) Valid() bool {
+	switch s {
+	case AccountStatusActive, AccountStatusBlocked, AccountStatusClosed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo reports whether an account may move from s to next:
+// active and blocked swap back and forth, and either may be closed.
+func (s AccountStatus) CanTransitionTo(next AccountStatus) bool {
+	switch s {
+	case AccountStatusActive:
+		return next == AccountStatusBlocked || next == AccountStatusClosed
+	case AccountStatusBlocked:
+		return next == AccountStatusActive || next == AccountStatusClosed
+	}
+	return false
+}
+
 type Account struct {
-	ID             int64
+	ID                        int64
+	DocumentNumber            string
+	AvailableCreditLimitCents int64
+	ClosingDay                int
+	Currency                  string
+	Status                    AccountStatus
+	CreatedAt                 time.Time
+}
+
+// Accepts tells whether the account takes a new transaction with the given
+// sign, returning ErrAccountBlocked or ErrAccountClosed when it does not.
+func (a Account) Accepts(sign int) error {
+	switch a.Status {
+	case AccountStatusClosed:
+		return ErrAccountClosed
+	case AccountStatusBlocked:
+		if sign < 0 {
+			return ErrAccountBlocked
+		}
+	}
+	return nil
+}
+
+// AccountFilter selects accounts ordered by (CreatedAt, ID), newest first
+// unless Ascending is set. Zero values leave a criterion out; From is
+// inclusive and To exclusive.
+type AccountFilter struct {
 	DocumentNumber string
-	CreatedAt      time.Time
+	Status         AccountStatus
+	From           time.Time
+	To             time.Time
+	Ascending      bool
+	After          *Cursor
+	Limit          int
+}
+
+// AccountStatusChange is one entry in an account's status history.
+type AccountStatusChange struct {
+	ID        int64
+	AccountID int64
+	From      AccountStatus
+	To        AccountStatus
+	Reason    string
+	CreatedAt time.Time
 }
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// AccountBalance is the maintained projection of an account's position.
+// PostedCents is the signed sum of posted transactions and PendingCents the
+// amount reserved by movements that are not posted yet, both in the account's
+// Currency.
+type AccountBalance struct {
+	AccountID    int64
+	Currency     string
+	PostedCents  int64
+	PendingCents int64
+	UpdatedAt    time.Time
+}
+
+// AvailableCents is what the account can still use once pending movements post.
+func (b AccountBalance) AvailableCents() int64 {
+	return b.PostedCents - b.PendingCents
+}
@@ -0,0 +1,44 @@
+package domain
+
+import "time"
+
+// AuthorizationStatus tells whether an authorization still holds funds.
+type AuthorizationStatus string
+
+const (
+	AuthorizationStatusPending  AuthorizationStatus = "PENDING"
+	AuthorizationStatusCaptured AuthorizationStatus = "CAPTURED"
+	AuthorizationStatusVoided   AuthorizationStatus = "VOIDED"
+	AuthorizationStatusExpired  AuthorizationStatus = "EXPIRED"
+)
+
+// DefaultAuthorizationTTL is how long a hold lasts when no window is
+// configured.
+const DefaultAuthorizationTTL = 7 * 24 * time.Hour
+
+// Authorization is the first phase of a two-phase debit. While PENDING it
+// holds AmountCents, in the account's Currency, against the available credit
+// limit and as pending on the account balance. Capturing it posts a
+// transaction for up to that amount and releases the rest; voiding or
+// expiring it releases everything. ReservesCreditLimit records whether the
+// hold took the amount from the limit, so that it is released the same way
+// even if its operation type has changed since.
+type Authorization struct {
+	ID                  int64
+	AccountID           int64
+	OperationTypeID     int
+	AmountCents         int64
+	CapturedCents       int64
+	Currency            string
+	ReservesCreditLimit bool
+	Status              AuthorizationStatus
+	TransactionID       int64
+	ExpiresAt           time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Expired reports whether a pending authorization has outlived its window.
+func (a Authorization) Expired(now time.Time) bool {
+	return a.Status == AuthorizationStatusPending && !now.Before(a.ExpiresAt)
+}
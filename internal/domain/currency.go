@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DefaultCurrency is the currency of accounts created without one.
+const DefaultCurrency = "BRL"
+
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrRateUnavailable     = errors.New("exchange rate unavailable")
+)
+
+// Currency is an ISO 4217 currency. Exponent is the number of decimal places
+// of its minor unit, which is what every *Cents amount is expressed in.
+type Currency struct {
+	Code     string
+	Exponent int
+}
+
+var currencies = map[string]Currency{
+	"ARS": {Code: "ARS", Exponent: 2},
+	"BHD": {Code: "BHD", Exponent: 3},
+	"BRL": {Code: "BRL", Exponent: 2},
+	"CAD": {Code: "CAD", Exponent: 2},
+	"CHF": {Code: "CHF", Exponent: 2},
+	"CLP": {Code: "CLP", Exponent: 0},
+	"EUR": {Code: "EUR", Exponent: 2},
+	"GBP": {Code: "GBP", Exponent: 2},
+	"JOD": {Code: "JOD", Exponent: 3},
+	"JPY": {Code: "JPY", Exponent: 0},
+	"KRW": {Code: "KRW", Exponent: 0},
+	"KWD": {Code: "KWD", Exponent: 3},
+	"MXN": {Code: "MXN", Exponent: 2},
+	"USD": {Code: "USD", Exponent: 2},
+}
+
+// LookupCurrency returns the supported currency with the given ISO 4217 code.
+// Codes are case-insensitive.
+func LookupCurrency(code string) (Currency, error) {
+	c, ok := currencies[strings.ToUpper(code)]
+	if !ok {
+		return Currency{}, ErrUnsupportedCurrency
+	}
+	return c, nil
+}
+
+// ExchangeRate quotes one unit of From in To.
+type ExchangeRate struct {
+	From string
+	To   string
+	Rate Decimal
+}
+
+// ParseExchangeRates reads a list such as "USD/BRL=5.25,EUR/BRL=5.70". Each
+// pair quotes one unit of the first currency in the second; the reverse
+// direction is not derived.
+func ParseExchangeRates(spec string) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		pair, value, ok := strings.Cut(entry, "=")
+		from, to, okPair := strings.Cut(pair, "/")
+		if !ok || !okPair {
+			return nil, fmt.Errorf("invalid rate %q", entry)
+		}
+		fromCurrency, err := LookupCurrency(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q: %w", entry, err)
+		}
+		toCurrency, err := LookupCurrency(to)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate %q: %w", entry, err)
+		}
+
+		rate, err := ParseDecimal(value)
+		if err != nil || rate.Units <= 0 {
+			return nil, fmt.Errorf("invalid rate %q", entry)
+		}
+		rates = append(rates, ExchangeRate{From: fromCurrency.Code, To: toCurrency.Code, Rate: rate})
+	}
+
+	return rates, nil
+}
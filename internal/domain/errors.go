@@ -8,4 +8,24 @@ var (
 	ErrInsufficientFunds     = errors.New("insufficient funds")
 	ErrTransactionNotFound   = errors.New("transaction not found")
 	ErrInvalidDocumentNumber = errors.New("invalid document number")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrStatementNotFound     = errors.New("statement not found")
+	ErrDocumentExists        = errors.New("document already exists")
+	ErrOperationTypeExists   = errors.New("operation type already exists")
+	ErrOperationTypeInactive = errors.New("operation type is inactive")
+	ErrAccountBlocked        = errors.New("account is blocked")
+	ErrAccountClosed         = errors.New("account is closed")
+	ErrInvalidAccountStatus  = errors.New("invalid account status")
+	ErrStatusTransition      = errors.New("account status transition not allowed")
+	ErrAuthorizationNotFound = errors.New("authorization not found")
+	ErrAuthorizationClosed   = errors.New("authorization is no longer pending")
+	ErrAuthorizationExpired  = errors.New("authorization expired")
+	ErrWebhookNotFound       = errors.New("webhook not found")
+	ErrWebhookInactive       = errors.New("webhook is inactive")
+	ErrWebhookTargetDenied   = errors.New("webhook url must resolve to public addresses")
+	ErrDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrInvalidDeliveryStatus = errors.New("invalid webhook delivery status")
+	ErrConflict              = errors.New("conflicts with an existing record")
+	ErrReferenceNotFound     = errors.New("referenced record not found")
+	ErrConcurrentUpdate      = errors.New("concurrent update, retry the request")
 )
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventType names a domain event. It is part of the published contract.
+type EventType string
+
+const (
+	EventAccountCreated    EventType = "AccountCreated"
+	EventTransactionPosted EventType = "TransactionPosted"
+)
+
+// Event is a domain event waiting in the outbox. It is recorded in the same
+// database transaction as the change it announces and published afterwards,
+// at least once and in order for each account. Attempts, NextAttemptAt and
+// LastError track failed publications.
+type Event struct {
+	ID            int64
+	Type          EventType
+	AccountID     int64
+	Payload       json.RawMessage
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+}
+
+// AccountCreatedPayload is the payload of an AccountCreated event.
+type AccountCreatedPayload struct {
+	AccountID                 int64     `json:"account_id"`
+	DocumentNumber            string    `json:"document_number"`
+	AvailableCreditLimitCents int64     `json:"available_credit_limit_cents"`
+	ClosingDay                int       `json:"closing_day"`
+	Currency                  string    `json:"currency"`
+	Status                    string    `json:"status"`
+	CreatedAt                 time.Time `json:"created_at"`
+}
+
+// TransactionPostedPayload is the payload of a TransactionPosted event.
+// Amounts are signed and in the minor units of Currency.
+type TransactionPostedPayload struct {
+	TransactionID     int64     `json:"transaction_id"`
+	AccountID         int64     `json:"account_id"`
+	OperationTypeID   int       `json:"operation_type_id"`
+	AmountCents       int64     `json:"amount_cents"`
+	Currency          string    `json:"currency"`
+	ReversedOf        int64     `json:"reversed_of,omitempty"`
+	ParentID          int64     `json:"parent_transaction_id,omitempty"`
+	InstallmentNumber int       `json:"installment_number,omitempty"`
+	Installments      int       `json:"installments,omitempty"`
+	EventDate         time.Time `json:"event_date"`
+}
+
+func NewAccountCreated(acc Account) (Event, error) {
+	return newEvent(EventAccountCreated, acc.ID, acc.CreatedAt, AccountCreatedPayload{
+		AccountID:                 acc.ID,
+		DocumentNumber:            acc.DocumentNumber,
+		AvailableCreditLimitCents: acc.AvailableCreditLimitCents,
+		ClosingDay:                acc.ClosingDay,
+		Currency:                  acc.Currency,
+		Status:                    string(acc.Status),
+		CreatedAt:                 acc.CreatedAt,
+	})
+}
+
+func NewTransactionPosted(tx Transaction, at time.Time) (Event, error) {
+	return newEvent(EventTransactionPosted, tx.AccountID, at, TransactionPostedPayload{
+		TransactionID:     tx.ID,
+		AccountID:         tx.AccountID,
+		OperationTypeID:   tx.OperationTypeID,
+		AmountCents:       tx.AmountCents,
+		Currency:          tx.Currency,
+		ReversedOf:        tx.ReversedOf,
+		ParentID:          tx.ParentID,
+		InstallmentNumber: tx.InstallmentNumber,
+		Installments:      tx.Installments,
+		EventDate:         tx.EventDate,
+	})
+}
+
+func newEvent(typ EventType, accountID int64, at time.Time, payload any) (Event, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Type:          typ,
+		AccountID:     accountID,
+		Payload:       body,
+		NextAttemptAt: at,
+		CreatedAt:     at,
+	}, nil
+}
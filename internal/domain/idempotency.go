@@ -0,0 +1,24 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord remembers the outcome of a request sent with an
+// Idempotency-Key so retries can be answered without executing it again.
+// StatusCode stays zero while the first request is still being processed.
+// Until then ExpiresAt is the end of that request's lease, after which a
+// retry takes the key over as if the request had never run; CreatedAt tells
+// the holder of the lease from a later one.
+type IdempotencyRecord struct {
+	Scope        string
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+func (r IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
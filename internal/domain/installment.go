@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// MaxInstallments is the longest installment plan a purchase can be split into.
+const MaxInstallments = 24
+
+// SplitInstallments divides totalCents into n installments. Every installment
+// gets the same share and the cents that do not divide evenly go on the first.
+func SplitInstallments(totalCents int64, n int) []int64 {
+	share := totalCents / int64(n)
+	out := make([]int64, n)
+	for i := range out {
+		out[i] = share
+	}
+	out[0] += totalCents - share*int64(n)
+	return out
+}
+
+// InstallmentDate is the event date of the installment with the given 1-based
+// number. Installments fall on the purchase's day of month, or on the last day
+// of shorter months.
+func InstallmentDate(purchase time.Time, number int) time.Time {
+	y, m, d := purchase.Date()
+	target := time.Date(y, m+time.Month(number-1), 1, 0, 0, 0, 0, purchase.Location())
+	last := target.AddDate(0, 1, -1).Day()
+
+	hh, mm, ss := purchase.Clock()
+	return time.Date(target.Year(), target.Month(), min(d, last), hh, mm, ss, purchase.Nanosecond(), purchase.Location())
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidAmountFormat = errors.New("invalid amount format")
+	ErrAmountPrecision     = errors.New("amount has more decimal places than allowed")
+)
+
+// Decimal is an exact decimal number, Units × 10^-Scale. The zero value is 0.
+type Decimal struct {
+	Units int64
+	Scale int
+}
+
+// ParseDecimal reads a decimal string such as "-12.30" without going through
+// floating point. Exponents and a leading "+" are rejected; trailing zeros in
+// the fraction are dropped.
+func ParseDecimal(s string) (Decimal, error) {
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return Decimal{}, ErrInvalidAmountFormat
+	}
+	frac = strings.TrimRight(frac, "0")
+
+	var units int64
+	for _, c := range whole + frac {
+		d := int64(c - '0')
+		if units > (math.MaxInt64-d)/10 {
+			return Decimal{}, ErrInvalidAmountFormat
+		}
+		units = units*10 + d
+	}
+
+	if negative {
+		units = -units
+	}
+	return Decimal{Units: units, Scale: len(frac)}, nil
+}
+
+// IsZero reports whether d is 0.
+func (d Decimal) IsZero() bool {
+	return d.Units == 0
+}
+
+// MinorUnits converts d into the minor units of a currency with the given
+// exponent. It fails with ErrAmountPrecision when d has more decimal places
+// than the currency allows.
+func (d Decimal) MinorUnits(exponent int) (int64, error) {
+	if d.Scale > exponent {
+		return 0, ErrAmountPrecision
+	}
+
+	units := d.Units
+	for range exponent - d.Scale {
+		if units > math.MaxInt64/10 || units < math.MinInt64/10 {
+			return 0, ErrInvalidAmountFormat
+		}
+		units *= 10
+	}
+	return units, nil
+}
+
+// ParseMinorUnits parses s and converts it to minor units of the exponent.
+func ParseMinorUnits(s string, exponent int) (int64, error) {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		return 0, err
+	}
+	return d.MinorUnits(exponent)
+}
+
+// Convert exchanges amount minor units of from into minor units of to at rate,
+// the price of one unit of from in to. The result is rounded half away from
+// zero.
+func Convert(amount int64, from, to Currency, rate Decimal) (int64, error) {
+	if rate.Units <= 0 {
+		return 0, ErrRateUnavailable
+	}
+
+	// amount × rate × 10^(to.Exponent - from.Exponent - rate.Scale)
+	v := new(big.Rat).SetInt64(amount)
+	v.Mul(v, new(big.Rat).SetInt64(rate.Units))
+	v.Mul(v, pow10(to.Exponent-from.Exponent-rate.Scale))
+
+	q, r := new(big.Int).QuoRem(v.Num(), v.Denom(), new(big.Int))
+	if r.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(r), big.NewInt(2)).Cmp(v.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(v.Sign())))
+	}
+	if !q.IsInt64() {
+		return 0, ErrInvalidAmountFormat
+	}
+	return q.Int64(), nil
+}
+
+func pow10(n int) *big.Rat {
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(max(n, -n))), nil)
+	if n < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,26 @@
 package domain
 
-// OperationType represents transaction type and its sign.
+import "time"
+
+// OperationType represents transaction type and its sign, along with the
+// rules transactions of that type follow.
 type OperationType struct {
 	ID          int
 	Description string
 	Sign        int // -1 or +1
+
+	// AffectsCreditLimit makes debits consume the available credit limit and
+	// credits give it back. Transactions record it when posted, so changing it
+	// only applies to new ones.
+	AffectsCreditLimit bool
+	// Reversible allows transactions of this type to be reversed.
+	Reversible bool
+	// Active types accept new transactions; inactive ones are kept for the
+	// transactions already recorded with them.
+	Active bool
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 const (
@@ -12,4 +28,20 @@ const (
 	OperationTypePurchaseInstallment = 2
 	OperationTypeWithdrawal          = 3
 	OperationTypeCreditVoucher       = 4
+	OperationTypeDebitReversal       = 5
+	OperationTypeCreditReversal      = 6
 )
+
+// FirstNumberedOperationType is the first ID the database gives to operation
+// types created without one. IDs chosen by clients must be lower, so they
+// never collide with a numbered type.
+const FirstNumberedOperationType = 100
+
+// ReversalOperationType is the operation type that compensates a transaction
+// with the given signed amount.
+func ReversalOperationType(amountCents int64) int {
+	if amountCents < 0 {
+		return OperationTypeDebitReversal
+	}
+	return OperationTypeCreditReversal
+}
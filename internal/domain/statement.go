@@ -0,0 +1,80 @@
+package domain
+
+import "time"
+
+const (
+	// DefaultClosingDay is the closing day of accounts created without one.
+	DefaultClosingDay = 1
+	// MaxClosingDay keeps every closing day present in every month.
+	MaxClosingDay = 28
+	// StatementDueDays is how long after closing a statement is due.
+	StatementDueDays = 10
+	// MinimumPaymentPercent is the share of the total due that must be paid.
+	MinimumPaymentPercent = 15
+	// MinimumPaymentFloorCents is the lowest minimum payment, unless the total
+	// due is lower than that.
+	MinimumPaymentFloorCents = 2500
+)
+
+// Statement is a closed billing cycle. The cycle covers the transactions with
+// an event date in [PeriodStart, PeriodEnd). OpeningBalanceCents carries the
+// closing balance of the previous statement; balances are signed like
+// transaction amounts, so a negative closing balance is owed.
+type Statement struct {
+	ID                  int64
+	AccountID           int64
+	Currency            string
+	PeriodStart         time.Time
+	PeriodEnd           time.Time
+	OpeningBalanceCents int64
+	DebitsCents         int64
+	CreditsCents        int64
+	ClosingBalanceCents int64
+	TotalDueCents       int64
+	MinimumPaymentCents int64
+	DueDate             time.Time
+	ClosedAt            time.Time
+}
+
+// NewStatement closes the cycle [start, end) from the previous closing balance
+// and the cycle's unsigned debit and credit totals.
+func NewStatement(accountID int64, start, end time.Time, openingCents, debitsCents, creditsCents int64) Statement {
+	closing := openingCents + creditsCents - debitsCents
+	totalDue := max(-closing, 0)
+
+	return Statement{
+		AccountID:           accountID,
+		PeriodStart:         start,
+		PeriodEnd:           end,
+		OpeningBalanceCents: openingCents,
+		DebitsCents:         debitsCents,
+		CreditsCents:        creditsCents,
+		ClosingBalanceCents: closing,
+		TotalDueCents:       totalDue,
+		MinimumPaymentCents: MinimumPayment(totalDue),
+		DueDate:             end.AddDate(0, 0, StatementDueDays),
+	}
+}
+
+// MinimumPayment is MinimumPaymentPercent of the total due, rounded up, but
+// never less than MinimumPaymentFloorCents or more than the total itself.
+func MinimumPayment(totalDueCents int64) int64 {
+	share := (totalDueCents*MinimumPaymentPercent + 99) / 100
+	return min(max(share, MinimumPaymentFloorCents), totalDueCents)
+}
+
+// LastClosing is the most recent cycle end not after t. Cycles end at midnight
+// UTC at the start of the closing day.
+func LastClosing(closingDay int, t time.Time) time.Time {
+	t = t.UTC()
+	closing := time.Date(t.Year(), t.Month(), closingDay, 0, 0, 0, 0, time.UTC)
+	if closing.After(t) {
+		closing = closing.AddDate(0, -1, 0)
+	}
+	return closing
+}
+
+// NextClosing is the first cycle end after t.
+func NextClosing(closingDay int, t time.Time) time.Time {
+	return LastClosing(closingDay, t).AddDate(0, 1, 0)
+}
@@ -2,11 +2,63 @@ package domain
 
 import "time"
 
+// TransactionStatus tells whether a transaction already counts against the
+// account balance.
+type TransactionStatus string
+
+const (
+	TransactionStatusPosted    TransactionStatus = "POSTED"
+	TransactionStatusScheduled TransactionStatus = "SCHEDULED"
+)
+
+// Transaction is a signed movement on an account. BalanceCents is the part of
+// AmountCents that has not been discharged yet: negative while a debit is still
+// owed, positive while a credit has not been fully used. ReversedOf is the ID
+// of the transaction this one compensates, or zero. AffectsCreditLimit records
+// whether the transaction moved the available credit limit when it was posted,
+// so that reversing it moves the limit back the same way even if its operation
+// type has changed since.
+//
+// Amounts are in the minor units of Currency, which is always the account's
+// currency. A transaction requested in another currency keeps what was asked
+// in OriginalAmountCents and OriginalCurrency.
+//
+// A purchase in installments is stored as a parent carrying the number of
+// Installments and the whole amount, which never counts against the balance,
+// and one child per installment pointing back to it through ParentID. Children
+// stay SCHEDULED until their event date is due.
 type Transaction struct {
-	ID              int64
+	ID                  int64
+	AccountID           int64
+	OperationTypeID     int
+	AmountCents         int64
+	BalanceCents        int64
+	Currency            string
+	OriginalCurrency    string
+	OriginalAmountCents int64
+	ReversedOf          int64
+	AffectsCreditLimit  bool
+	Status              TransactionStatus
+	ParentID            int64
+	InstallmentNumber   int
+	Installments        int
+	EventDate           time.Time
+	CreatedAt           time.Time
+}
+
+// IsInstallmentPlan reports whether the transaction is the parent of an
+// installment plan.
+func (t Transaction) IsInstallmentPlan() bool {
+	return t.Installments > 0
+}
+
+// TransactionFilter selects an account's transactions, newest first. Zero
+// values leave a criterion out; From is inclusive and To exclusive.
+type TransactionFilter struct {
 	AccountID       int64
 	OperationTypeID int
-	AmountCents     int64
-	EventDate       time.Time
-	CreatedAt       time.Time
+	From            time.Time
+	To              time.Time
+	After           *Cursor
+	Limit           int
 }
@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"encoding/json"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// WebhookSubscription is a partner endpoint receiving domain events. An empty
+// EventTypes takes every type and a zero AccountID every account. Secret signs
+// the deliveries and is only shown when the subscription is created.
+type WebhookSubscription struct {
+	ID         int64
+	URL        string
+	Secret     string
+	EventTypes []EventType
+	AccountID  int64
+	Active     bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Matches reports whether the subscription wants event.
+func (s WebhookSubscription) Matches(event Event) bool {
+	if !s.Active {
+		return false
+	}
+	if s.AccountID != 0 && s.AccountID != event.AccountID {
+		return false
+	}
+	return len(s.EventTypes) == 0 || slices.Contains(s.EventTypes, event.Type)
+}
+
+// nonPublicPrefixes are ranges that are not reachable from the internet but
+// that netip does not single out: "this network", carrier-grade NAT,
+// benchmarking and reserved addresses.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+}
+
+// PublicAddress reports whether webhooks may be sent to addr. Loopback,
+// link-local (cloud metadata endpoints among them), private, multicast and
+// unspecified addresses are refused, so that a subscription cannot reach the
+// service's own network.
+func PublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, p := range nonPublicPrefixes {
+		if p.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// KnownEventType reports whether t is an event type the service emits.
+func KnownEventType(t EventType) bool {
+	return t == EventAccountCreated || t == EventTransactionPosted
+}
+
+// WebhookDeliveryStatus tells where a delivery is in its retry cycle.
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "PENDING"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryDead is a delivery that ran out of attempts. It is only
+	// sent again when redelivered by hand.
+	WebhookDeliveryDead WebhookDeliveryStatus = "DEAD"
+)
+
+func (s WebhookDeliveryStatus) Valid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery is one event sent, or to be sent, to one subscription. It
+// keeps a copy of the event so the delivery log does not depend on the
+// outbox.
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int64
+	EventID        int64
+	EventType      EventType
+	AccountID      int64
+	Payload        json.RawMessage
+	OccurredAt     time.Time
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	LastStatusCode int
+	DeliveredAt    time.Time
+	CreatedAt      time.Time
+}
+
+// NewWebhookDelivery queues event for the subscription, due at.
+func NewWebhookDelivery(subscriptionID int64, event Event, at time.Time) WebhookDelivery {
+	return WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		AccountID:      event.AccountID,
+		Payload:        event.Payload,
+		OccurredAt:     event.CreatedAt,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  at,
+		CreatedAt:      at,
+	}
+}
+
+// Event rebuilds the event the delivery carries.
+func (d WebhookDelivery) Event() Event {
+	return Event{
+		ID:        d.EventID,
+		Type:      d.EventType,
+		AccountID: d.AccountID,
+		Payload:   d.Payload,
+		CreatedAt: d.OccurredAt,
+	}
+}
+
+// WebhookDeliveryFilter selects a subscription's deliveries, newest first.
+// An empty Status takes every status.
+type WebhookDeliveryFilter struct {
+	SubscriptionID int64
+	Status         WebhookDeliveryStatus
+	After          *Cursor
+	Limit          int
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type AccountBalanceRepository interface {
+	// FindByAccountID returns a zero balance when nothing was projected yet.
+	FindByAccountID(ctx context.Context, accountID int64) (domain.AccountBalance, error)
+	Apply(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error
+	// Rebuild recomputes the projection from the transactions table and the
+	// pending authorizations.
+	Rebuild(ctx context.Context, accountID int64) (domain.AccountBalance, error)
+}
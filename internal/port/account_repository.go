@@ -10,4 +10,11 @@ type AccountRepository interface {
 	Create(ctx context.Context, account domain.Account) (int64, error)
 	FindByID(ctx context.Context, id int64) (domain.Account, error)
 	FindByIDForUpdate(ctx context.Context, id int64) (domain.Account, error)
+	// ListAfter returns up to limit accounts with an ID greater than afterID,
+	// in ID order.
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]domain.Account, error)
+	// List returns the accounts matching filter, in the order it asks for.
+	List(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error)
+	UpdateAvailableCreditLimit(ctx context.Context, id int64, limitCents int64) error
+	UpdateStatus(ctx context.Context, id int64, status domain.AccountStatus) error
 }
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type AccountStatusChangeRepository interface {
+	Create(ctx context.Context, change domain.AccountStatusChange) (int64, error)
+	// ListByAccount returns the account's status changes, oldest first.
+	ListByAccount(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error)
+}
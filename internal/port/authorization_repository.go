@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type AuthorizationRepository interface {
+	Create(ctx context.Context, auth domain.Authorization) (int64, error)
+	FindByID(ctx context.Context, id int64) (domain.Authorization, error)
+	// FindByIDForUpdate locks the authorization until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id int64) (domain.Authorization, error)
+	// Close moves a PENDING authorization to status, recording what was
+	// captured. It returns domain.ErrAuthorizationClosed if it is not pending
+	// anymore.
+	Close(ctx context.Context, auth domain.Authorization) error
+	// FindExpired returns up to limit PENDING authorizations whose window
+	// ended at or before asOf, oldest first.
+	FindExpired(ctx context.Context, asOf time.Time, limit int) ([]domain.Authorization, error)
+}
@@ -0,0 +1,8 @@
+package port
+
+import "time"
+
+// Clock tells the current time. Jobs take it so tests can pin the date.
+type Clock interface {
+	Now() time.Time
+}
@@ -0,0 +1,9 @@
+package port
+
+// DocumentValidator checks identity document numbers.
+type DocumentValidator interface {
+	// Normalize returns the canonical form of a document number issued in
+	// country, or domain.ErrInvalidDocumentNumber when it is malformed, fails
+	// its check digits or country has no registered format.
+	Normalize(country, number string) (string, error)
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// EventPublisher hands domain events to the outside world. Delivery is at
+// least once: an event may be published again after a failure, and consumers
+// deduplicate on its ID.
+type EventPublisher interface {
+	Publish(ctx context.Context, event domain.Event) error
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// ExchangeRates quotes currency pairs.
+type ExchangeRates interface {
+	// Rate returns the price of one unit of from in to, or
+	// domain.ErrRateUnavailable when the pair is not quoted.
+	Rate(ctx context.Context, from, to string) (domain.Decimal, error)
+}
@@ -0,0 +1,18 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims the key for a new request. When the key is already taken
+	// and not expired it returns the stored record and false.
+	Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error)
+	// Complete stores the response of a reserved record, returning
+	// domain.ErrConcurrentUpdate when its lease was taken over meanwhile.
+	Complete(ctx context.Context, record domain.IdempotencyRecord) error
+	// Release frees a reserved record, unless its lease was taken over.
+	Release(ctx context.Context, record domain.IdempotencyRecord) error
+}
@@ -8,5 +8,20 @@ import (
 
 type OperationTypeRepository interface {
 	FindByID(ctx context.Context, id int) (domain.OperationType, error)
-	SeedDefaults(ctx context.Context) error
+	// FindByIDForUpdate locks the operation type until the transaction ends.
+	FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error)
+	// List returns every operation type ordered by ID.
+	List(ctx context.Context) ([]domain.OperationType, error)
+	// Create stores ot and returns its ID. A zero ot.ID lets the database
+	// assign one; an ID already taken fails with domain.ErrOperationTypeExists.
+	Create(ctx context.Context, ot domain.OperationType) (int, error)
+	// Update saves the description and rules of ot. The sign is never
+	// changed.
+	Update(ctx context.Context, ot domain.OperationType) error
+}
+
+// OperationTypeCache drops cached operation types, so the next lookup reads
+// what was committed.
+type OperationTypeCache interface {
+	Invalidate(ids ...int)
 }
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// OutboxRepository stores domain events until they are published. Add must be
+// called in the database transaction that makes the change the event
+// announces.
+type OutboxRepository interface {
+	Add(ctx context.Context, event domain.Event) error
+	// ClaimPending leases to the caller, until the given time, up to limit
+	// unpublished events due at asOf, oldest first. Only the oldest
+	// unpublished event of each account is returned, and events leased to
+	// another relay are skipped along with the rest of their account, so an
+	// account's events are never published out of order. Dead events are
+	// neither returned nor hold back their account.
+	ClaimPending(ctx context.Context, asOf, until time.Time, limit int) ([]domain.Event, error)
+	MarkPublished(ctx context.Context, id int64, at time.Time) error
+	// MarkFailed records a failed attempt and when to try again.
+	MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error
+	// MarkDead records the last failed attempt of an event that will not be
+	// retried.
+	MarkDead(ctx context.Context, id int64, attempts int, at time.Time, lastError string) error
+	// ListByAccount returns up to limit events of the given type for an
+	// account with IDs above afterID, oldest first, published or not.
+	ListByAccount(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error)
+	// LatestID returns the ID of the account's newest event of the given
+	// type, or zero when there is none.
+	LatestID(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error)
+}
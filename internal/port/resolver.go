@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"net/netip"
+)
+
+// Resolver looks up the addresses of a host name. *net.Resolver satisfies it.
+type Resolver interface {
+	LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error)
+}
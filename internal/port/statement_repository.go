@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type StatementRepository interface {
+	Create(ctx context.Context, statement domain.Statement) (int64, error)
+	FindByID(ctx context.Context, id int64) (domain.Statement, error)
+	// FindLatest returns the account's most recent statement, or
+	// domain.ErrStatementNotFound if none was closed yet.
+	FindLatest(ctx context.Context, accountID int64) (domain.Statement, error)
+	// ListByAccount returns the account's statements, newest first.
+	ListByAccount(ctx context.Context, accountID int64) ([]domain.Statement, error)
+}
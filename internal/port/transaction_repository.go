@@ -2,10 +2,31 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 )
 
 type TransactionRepository interface {
 	Create(ctx context.Context, tx domain.Transaction) (int64, error)
+	FindByID(ctx context.Context, id int64) (domain.Transaction, error)
+	// FindOpenDebitsForUpdate returns the account's transactions with a negative
+	// balance, oldest event first, locking them until the transaction ends.
+	FindOpenDebitsForUpdate(ctx context.Context, accountID int64) ([]domain.Transaction, error)
+	UpdateBalance(ctx context.Context, id int64, balanceCents int64) error
+	ListByAccount(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error)
+	// ListInstallments returns the children of an installment plan in order.
+	ListInstallments(ctx context.Context, parentID int64) ([]domain.Transaction, error)
+	// FindDueScheduled returns up to limit SCHEDULED transactions whose event
+	// date is not after asOf, oldest first.
+	FindDueScheduled(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error)
+	// MarkPosted moves a SCHEDULED transaction to POSTED with the given balance.
+	// It returns domain.ErrTransactionNotFound if it is not scheduled anymore.
+	MarkPosted(ctx context.Context, id int64, balanceCents int64) error
+	// SumByPeriod returns the unsigned totals of the account's debits and
+	// credits with an event date in [from, to). Installment plan parents are
+	// left out since their installments are counted instead.
+	SumByPeriod(ctx context.Context, accountID int64, from, to time.Time) (debitsCents, creditsCents int64, err error)
+	// SumReversals returns the absolute amount already reversed from a transaction.
+	SumReversals(ctx context.Context, id int64) (int64, error)
 }
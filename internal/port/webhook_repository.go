@@ -0,0 +1,43 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+type WebhookSubscriptionRepository interface {
+	Create(ctx context.Context, sub domain.WebhookSubscription) (int64, error)
+	FindByID(ctx context.Context, id int64) (domain.WebhookSubscription, error)
+	List(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// Deactivate stops deliveries to the subscription. It returns
+	// domain.ErrWebhookNotFound if there is no such subscription.
+	Deactivate(ctx context.Context, id int64, at time.Time) error
+	// FindMatching returns the active subscriptions that want event.
+	FindMatching(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error)
+}
+
+type WebhookDeliveryRepository interface {
+	// Enqueue stores a delivery, doing nothing if the subscription already
+	// has one for the same event.
+	Enqueue(ctx context.Context, delivery domain.WebhookDelivery) error
+	FindByID(ctx context.Context, id int64) (domain.WebhookDelivery, error)
+	ListBySubscription(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+	// ClaimDue leases to the caller, until the given time, up to limit
+	// PENDING deliveries due at asOf. Deliveries leased to another worker are
+	// not due until their lease ends.
+	ClaimDue(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// Update saves the delivery's status and attempt bookkeeping, provided
+	// its next attempt is still set for seen, the time the caller read or
+	// claimed it with. Otherwise someone else changed it meanwhile and
+	// Update fails with domain.ErrConcurrentUpdate.
+	Update(ctx context.Context, delivery domain.WebhookDelivery, seen time.Time) error
+}
+
+// WebhookSender makes one delivery attempt. It returns the HTTP status the
+// endpoint answered, or zero when there was no answer, and an error for
+// anything but a 2xx.
+type WebhookSender interface {
+	Send(ctx context.Context, sub domain.WebhookSubscription, delivery domain.WebhookDelivery) (int, error)
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// AuthorizeTransaction places a hold for a future debit. The held amount is
+// taken from the available credit limit and counted as pending until the
+// authorization is captured, voided or expires after TTL.
+type AuthorizeTransaction struct {
+	Accounts           port.AccountRepository
+	OperationTypes     port.OperationTypeRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	Authorizations     port.AuthorizationRepository
+	TransactionManager port.TransactionManager
+	Clock              port.Clock
+	TTL                time.Duration
+}
+
+// AuthorizeTransactionInput describes a hold. Currency defaults to the
+// account's and, unlike CreateTransaction, may not differ from it: the held
+// amount must be the one later captured.
+type AuthorizeTransactionInput struct {
+	AccountID       int64
+	OperationTypeID int
+	Amount          domain.Decimal
+	Currency        string
+}
+
+func (uc AuthorizeTransaction) Execute(ctx context.Context, input AuthorizeTransactionInput) (domain.Authorization, error) {
+	if input.Amount.Units <= 0 {
+		return domain.Authorization{}, ErrInvalidAmount
+	}
+	if input.Currency != "" {
+		if _, err := domain.LookupCurrency(input.Currency); err != nil {
+			return domain.Authorization{}, err
+		}
+	}
+
+	ttl := uc.TTL
+	if ttl <= 0 {
+		ttl = domain.DefaultAuthorizationTTL
+	}
+
+	var auth domain.Authorization
+
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, input.AccountID)
+		if err != nil {
+			return err
+		}
+
+		op, err := uc.OperationTypes.FindByID(txCtx, input.OperationTypeID)
+		if err != nil {
+			return err
+		}
+		if !op.Active {
+			return domain.ErrOperationTypeInactive
+		}
+		if op.Sign != -1 {
+			return ErrInvalidOperation
+		}
+		if err := acc.Accepts(op.Sign); err != nil {
+			return err
+		}
+
+		currency, err := domain.LookupCurrency(acc.Currency)
+		if err != nil {
+			return err
+		}
+		if input.Currency != "" && !strings.EqualFold(input.Currency, currency.Code) {
+			return ErrCurrencyMismatch
+		}
+		amountCents, err := input.Amount.MinorUnits(currency.Exponent)
+		if err != nil {
+			return err
+		}
+
+		l := ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances}
+		if err := l.hold(txCtx, posting{account: acc, operationType: op, amountCents: amountCents}); err != nil {
+			return err
+		}
+
+		now := uc.Clock.Now()
+		auth = domain.Authorization{
+			AccountID:       acc.ID,
+			OperationTypeID: op.ID,
+			AmountCents:     amountCents,
+			Currency:        currency.Code,
+			Status:          domain.AuthorizationStatusPending,
+			ExpiresAt:       now.Add(ttl),
+			CreatedAt:       now,
+			UpdatedAt:       now,
+		}
+		auth.ReservesCreditLimit = op.AffectsCreditLimit
+		auth.ID, err = uc.Authorizations.Create(txCtx, auth)
+		return err
+	})
+
+	if err != nil {
+		return domain.Authorization{}, err
+	}
+
+	return auth, nil
+}
+
+// releaseAuthorization gives back what a pending authorization holds on acc,
+// which must be locked, and returns the account as it is afterwards along with
+// the authorization's operation type as it was when held. The limit is
+// released only if the hold reserved it, whatever the type says now, and a
+// capture posted with the returned type takes it again only in that case.
+func releaseAuthorization(ctx context.Context, l ledger, operationTypes port.OperationTypeRepository, acc domain.Account, auth domain.Authorization) (domain.Account, domain.OperationType, error) {
+	op, err := operationTypes.FindByID(ctx, auth.OperationTypeID)
+	if err != nil {
+		return domain.Account{}, domain.OperationType{}, err
+	}
+	held := op
+	held.AffectsCreditLimit = auth.ReservesCreditLimit
+	acc, err = l.release(ctx, posting{account: acc, operationType: held, amountCents: auth.AmountCents})
+	if err != nil {
+		return domain.Account{}, domain.OperationType{}, err
+	}
+	return acc, held, nil
+}
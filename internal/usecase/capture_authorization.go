@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// CaptureAuthorization turns a pending authorization into a posted
+// transaction. An authorization is captured once: whatever part of the hold
+// is not captured is released.
+type CaptureAuthorization struct {
+	Accounts           port.AccountRepository
+	OperationTypes     port.OperationTypeRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	Authorizations     port.AuthorizationRepository
+	TransactionManager port.TransactionManager
+	Outbox             port.OutboxRepository
+	Clock              port.Clock
+}
+
+// Execute captures amount, in the authorization's currency, or the whole hold
+// when amount is zero. It returns the updated authorization and the posted
+// transaction.
+func (uc CaptureAuthorization) Execute(ctx context.Context, authorizationID int64, amount domain.Decimal) (domain.Authorization, domain.Transaction, error) {
+	if amount.Units < 0 {
+		return domain.Authorization{}, domain.Transaction{}, ErrInvalidAmount
+	}
+
+	auth, err := uc.Authorizations.FindByID(ctx, authorizationID)
+	if err != nil {
+		return domain.Authorization{}, domain.Transaction{}, err
+	}
+
+	var tx domain.Transaction
+
+	err = uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, auth.AccountID)
+		if err != nil {
+			return err
+		}
+
+		auth, err = uc.Authorizations.FindByIDForUpdate(txCtx, authorizationID)
+		if err != nil {
+			return err
+		}
+		now := uc.Clock.Now()
+		if auth.Status != domain.AuthorizationStatusPending {
+			return domain.ErrAuthorizationClosed
+		}
+		if auth.Expired(now) {
+			return domain.ErrAuthorizationExpired
+		}
+		// The hold was accepted while the account was open; a blocked account
+		// still honours it.
+		if acc.Status == domain.AccountStatusClosed {
+			return domain.ErrAccountClosed
+		}
+
+		currency, err := domain.LookupCurrency(auth.Currency)
+		if err != nil {
+			return err
+		}
+		amountCents, err := amount.MinorUnits(currency.Exponent)
+		if err != nil {
+			return err
+		}
+		if amountCents == 0 {
+			amountCents = auth.AmountCents
+		}
+		if amountCents > auth.AmountCents {
+			return ErrCaptureExceeded
+		}
+
+		l := ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances, outbox: uc.Outbox}
+		acc, op, err := releaseAuthorization(txCtx, l, uc.OperationTypes, acc, auth)
+		if err != nil {
+			return err
+		}
+		tx, err = l.post(txCtx, posting{
+			account:       acc,
+			operationType: op,
+			amountCents:   amountCents,
+		})
+		if err != nil {
+			return err
+		}
+
+		auth.Status = domain.AuthorizationStatusCaptured
+		auth.CapturedCents = amountCents
+		auth.TransactionID = tx.ID
+		auth.UpdatedAt = now
+		return uc.Authorizations.Close(txCtx, auth)
+	})
+
+	if err != nil {
+		return domain.Authorization{}, domain.Transaction{}, err
+	}
+
+	return auth, tx, nil
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// ChangeAccountStatus moves an account to another status and records why in
+// its status history, under the same row lock used when posting transactions.
+type ChangeAccountStatus struct {
+	Accounts           port.AccountRepository
+	StatusChanges      port.AccountStatusChangeRepository
+	TransactionManager port.TransactionManager
+}
+
+type ChangeAccountStatusInput struct {
+	Status string
+	Reason string
+}
+
+func (uc ChangeAccountStatus) Execute(ctx context.Context, accountID int64, input ChangeAccountStatusInput) (domain.Account, error) {
+	status := domain.AccountStatus(strings.ToUpper(strings.TrimSpace(input.Status)))
+	if !status.Valid() {
+		return domain.Account{}, domain.ErrInvalidAccountStatus
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return domain.Account{}, ErrInvalidReason
+	}
+
+	var acc domain.Account
+
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		acc, err = uc.Accounts.FindByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return err
+		}
+		if !acc.Status.CanTransitionTo(status) {
+			return domain.ErrStatusTransition
+		}
+
+		if err := uc.Accounts.UpdateStatus(txCtx, accountID, status); err != nil {
+			return err
+		}
+		if _, err := uc.StatusChanges.Create(txCtx, domain.AccountStatusChange{
+			AccountID: accountID,
+			From:      acc.Status,
+			To:        status,
+			Reason:    reason,
+			CreatedAt: time.Now(),
+		}); err != nil {
+			return err
+		}
+
+		acc.Status = status
+		return nil
+	})
+
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	return acc, nil
+}
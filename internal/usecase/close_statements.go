@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// DefaultClosingBatchSize is how many accounts CloseStatements loads at a time.
+const DefaultClosingBatchSize = 500
+
+// CloseStatements closes, for every account, each billing cycle that has
+// ended and has no statement yet. A cycle starts where the previous statement
+// ended, so the cycles of missed runs are caught up by the next one, one
+// statement per cycle.
+type CloseStatements struct {
+	Accounts           port.AccountRepository
+	Transactions       port.TransactionRepository
+	Statements         port.StatementRepository
+	TransactionManager port.TransactionManager
+	Clock              port.Clock
+	BatchSize          int
+}
+
+// Execute returns how many statements it closed.
+func (uc CloseStatements) Execute(ctx context.Context) (int, error) {
+	limit := uc.BatchSize
+	if limit <= 0 {
+		limit = DefaultClosingBatchSize
+	}
+	now := uc.Clock.Now()
+
+	closed := 0
+	var afterID int64
+	for {
+		accounts, err := uc.Accounts.ListAfter(ctx, afterID, limit)
+		if err != nil {
+			return closed, err
+		}
+
+		for _, acc := range accounts {
+			n, err := uc.close(ctx, acc.ID, now)
+			closed += n
+			if err != nil {
+				return closed, err
+			}
+		}
+
+		if len(accounts) < limit {
+			return closed, nil
+		}
+		afterID = accounts[len(accounts)-1].ID
+	}
+}
+
+// close closes the account's ended cycles in order and returns how many it
+// closed.
+func (uc CloseStatements) close(ctx context.Context, accountID int64, now time.Time) (int, error) {
+	closed := 0
+
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return err
+		}
+
+		last := domain.LastClosing(acc.ClosingDay, now)
+		start, opening := acc.CreatedAt, int64(0)
+
+		latest, err := uc.Statements.FindLatest(txCtx, acc.ID)
+		switch {
+		case err == nil:
+			start, opening = latest.PeriodEnd, latest.ClosingBalanceCents
+		case !errors.Is(err, domain.ErrStatementNotFound):
+			return err
+		}
+
+		for end := domain.NextClosing(acc.ClosingDay, start); !end.After(last); end = domain.NextClosing(acc.ClosingDay, end) {
+			debits, credits, err := uc.Transactions.SumByPeriod(txCtx, acc.ID, start, end)
+			if err != nil {
+				return err
+			}
+
+			statement := domain.NewStatement(acc.ID, start, end, opening, debits, credits)
+			statement.Currency = acc.Currency
+			statement.ClosedAt = now
+			if _, err := uc.Statements.Create(txCtx, statement); err != nil {
+				return err
+			}
+
+			start, opening = end, statement.ClosingBalanceCents
+			closed++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return closed, nil
+}
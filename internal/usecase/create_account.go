@@ -8,21 +8,102 @@ import (
 	"github.com/nicolasmmb/pismo-challenge/internal/port"
 )
 
+// CreateAccount opens an account. When Outbox is set, an AccountCreated event
+// is recorded with the account, in one transaction run by TransactionManager.
 type CreateAccount struct {
-	Accounts port.AccountRepository
+	Accounts           port.AccountRepository
+	Documents          port.DocumentValidator
+	Outbox             port.OutboxRepository
+	TransactionManager port.TransactionManager
 }
 
-func (uc CreateAccount) Execute(ctx context.Context, documentNumber string) (domain.Account, error) {
-	if documentNumber == "" {
+// CreateAccountInput describes a new account. A zero ClosingDay falls back to
+// domain.DefaultClosingDay, an empty Currency to domain.DefaultCurrency and an
+// empty DocumentCountry to domain.DefaultDocumentCountry and a nil
+// AvailableCreditLimit to domain.DefaultCreditLimit(). AvailableCreditLimit is
+// in the account's currency; it may be zero for accounts that only take
+// credits.
+type CreateAccountInput struct {
+	DocumentNumber       string
+	DocumentCountry      string
+	AvailableCreditLimit *domain.Decimal
+	ClosingDay           int
+	Currency             string
+}
+
+func (uc CreateAccount) Execute(ctx context.Context, input CreateAccountInput) (domain.Account, error) {
+	if input.DocumentNumber == "" {
 		return domain.Account{}, ErrInvalidDocument
 	}
 
-	acc := domain.Account{DocumentNumber: documentNumber, CreatedAt: time.Now()}
-	id, err := uc.Accounts.Create(ctx, acc)
+	country := input.DocumentCountry
+	if country == "" {
+		country = domain.DefaultDocumentCountry
+	}
+	document, err := uc.Documents.Normalize(country, input.DocumentNumber)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	limit := domain.DefaultCreditLimit()
+	if input.AvailableCreditLimit != nil {
+		limit = *input.AvailableCreditLimit
+	}
+	if limit.Units < 0 {
+		return domain.Account{}, ErrInvalidCreditLimit
+	}
+
+	code := input.Currency
+	if code == "" {
+		code = domain.DefaultCurrency
+	}
+	currency, err := domain.LookupCurrency(code)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	limitCents, err := limit.MinorUnits(currency.Exponent)
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	closingDay := input.ClosingDay
+	if closingDay == 0 {
+		closingDay = domain.DefaultClosingDay
+	}
+	if closingDay < 1 || closingDay > domain.MaxClosingDay {
+		return domain.Account{}, ErrInvalidClosingDay
+	}
+
+	acc := domain.Account{
+		DocumentNumber:            document,
+		AvailableCreditLimitCents: limitCents,
+		ClosingDay:                closingDay,
+		Currency:                  currency.Code,
+		Status:                    domain.AccountStatusActive,
+		CreatedAt:                 time.Now(),
+	}
+	if uc.Outbox == nil {
+		if acc.ID, err = uc.Accounts.Create(ctx, acc); err != nil {
+			return domain.Account{}, err
+		}
+		return acc, nil
+	}
+
+	err = uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if acc.ID, err = uc.Accounts.Create(txCtx, acc); err != nil {
+			return err
+		}
+		event, err := domain.NewAccountCreated(acc)
+		if err != nil {
+			return err
+		}
+		return uc.Outbox.Add(txCtx, event)
+	})
 	if err != nil {
 		return domain.Account{}, err
 	}
 
-	acc.ID = id
 	return acc, nil
 }
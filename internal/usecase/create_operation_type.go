@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type CreateOperationType struct {
+	OperationTypes port.OperationTypeRepository
+}
+
+// CreateOperationTypeInput describes a new operation type. A zero ID lets the
+// database number it; a chosen one must be below
+// domain.FirstNumberedOperationType. Rules left nil default to true.
+type CreateOperationTypeInput struct {
+	ID                 int
+	Description        string
+	Sign               int
+	AffectsCreditLimit *bool
+	Reversible         *bool
+	Active             *bool
+}
+
+func (uc CreateOperationType) Execute(ctx context.Context, input CreateOperationTypeInput) (domain.OperationType, error) {
+	if input.ID < 0 || input.ID >= domain.FirstNumberedOperationType {
+		return domain.OperationType{}, ErrInvalidOperation
+	}
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		return domain.OperationType{}, ErrInvalidDescription
+	}
+	if input.Sign != -1 && input.Sign != 1 {
+		return domain.OperationType{}, ErrInvalidSign
+	}
+
+	now := time.Now()
+	ot := domain.OperationType{
+		ID:                 input.ID,
+		Description:        description,
+		Sign:               input.Sign,
+		AffectsCreditLimit: valueOr(input.AffectsCreditLimit, true),
+		Reversible:         valueOr(input.Reversible, true),
+		Active:             valueOr(input.Active, true),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+
+	id, err := uc.OperationTypes.Create(ctx, ot)
+	if err != nil {
+		return domain.OperationType{}, err
+	}
+
+	ot.ID = id
+	return ot, nil
+}
+
+func valueOr[T any](v *T, fallback T) T {
+	if v == nil {
+		return fallback
+	}
+	return *v
+}
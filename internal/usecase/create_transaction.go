@@ -2,61 +2,90 @@ package usecase
 
 import (
 	"context"
-	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 	"github.com/nicolasmmb/pismo-challenge/internal/port"
 )
 
+// CreateTransaction posts a transaction on an account. Rates is optional:
+// without it, transactions in a currency other than the account's are
+// rejected with ErrCurrencyMismatch. So is Outbox: without it, no
+// TransactionPosted event is recorded.
 type CreateTransaction struct {
 	Accounts           port.AccountRepository
 	OperationTypes     port.OperationTypeRepository
 	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
 	TransactionManager port.TransactionManager
+	Outbox             port.OutboxRepository
+	Rates              port.ExchangeRates
 }
 
-func (uc CreateTransaction) Execute(ctx context.Context, accountID int64, operationTypeID int, amountCents int64) (domain.Transaction, error) {
-	if amountCents <= 0 {
+// CreateTransactionInput describes a new transaction. Currency defaults to
+// the account's. Installments splits a PURCHASE WITH INSTALLMENTS into monthly
+// installments; zero posts the whole amount at once.
+type CreateTransactionInput struct {
+	AccountID       int64
+	OperationTypeID int
+	Amount          domain.Decimal
+	Currency        string
+	Installments    int
+}
+
+// Execute records the transaction. For an installment plan the returned
+// transaction is the parent of the plan.
+func (uc CreateTransaction) Execute(ctx context.Context, input CreateTransactionInput) (domain.Transaction, error) {
+	if input.Amount.Units <= 0 {
 		return domain.Transaction{}, ErrInvalidAmount
 	}
+	if input.Installments != 0 {
+		if input.OperationTypeID != domain.OperationTypePurchaseInstallment ||
+			input.Installments < 1 || input.Installments > domain.MaxInstallments {
+			return domain.Transaction{}, ErrInvalidInstallments
+		}
+	}
+	if input.Currency != "" {
+		if _, err := domain.LookupCurrency(input.Currency); err != nil {
+			return domain.Transaction{}, err
+		}
+	}
 
 	var tx domain.Transaction
 
 	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
-		if _, err := uc.Accounts.FindByIDForUpdate(txCtx, accountID); err != nil {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, input.AccountID)
+		if err != nil {
 			return err
 		}
 
-		op, err := uc.OperationTypes.FindByID(txCtx, operationTypeID)
+		op, err := uc.OperationTypes.FindByID(txCtx, input.OperationTypeID)
 		if err != nil {
 			return err
 		}
-
-		if op.Sign != -1 && op.Sign != 1 {
-			return ErrInvalidOperation
+		if !op.Active {
+			return domain.ErrOperationTypeInactive
 		}
-
-		normalized := amountCents
-		if op.Sign < 0 {
-			normalized = -amountCents
+		if err := acc.Accepts(op.Sign); err != nil {
+			return err
 		}
 
-		now := time.Now()
-		tx = domain.Transaction{
-			AccountID:       accountID,
-			OperationTypeID: operationTypeID,
-			AmountCents:     normalized,
-			EventDate:       now,
-			CreatedAt:       now,
+		p := posting{
+			account:       acc,
+			operationType: op,
 		}
-
-		id, err := uc.Transactions.Create(txCtx, tx)
-		if err != nil {
+		if err := uc.resolveAmount(txCtx, &p, input); err != nil {
 			return err
 		}
+		if input.Installments != 0 && p.amountCents < int64(input.Installments) {
+			return ErrInvalidInstallments
+		}
 
-		tx.ID = id
-		return nil
+		if input.Installments != 0 {
+			tx, err = uc.ledger().postInstallments(txCtx, p, input.Installments)
+		} else {
+			tx, err = uc.ledger().post(txCtx, p)
+		}
+		return err
 	})
 
 	if err != nil {
@@ -65,3 +94,50 @@ func (uc CreateTransaction) Execute(ctx context.Context, accountID int64, operat
 
 	return tx, nil
 }
+
+// resolveAmount sets the posting amount in the account's currency, converting
+// it when the input is in another currency.
+func (uc CreateTransaction) resolveAmount(ctx context.Context, p *posting, input CreateTransactionInput) error {
+	accountCurrency, err := domain.LookupCurrency(p.account.Currency)
+	if err != nil {
+		return err
+	}
+
+	requested := accountCurrency
+	if input.Currency != "" {
+		if requested, err = domain.LookupCurrency(input.Currency); err != nil {
+			return err
+		}
+	}
+
+	amount, err := input.Amount.MinorUnits(requested.Exponent)
+	if err != nil {
+		return err
+	}
+	if requested == accountCurrency {
+		p.amountCents = amount
+		return nil
+	}
+
+	if uc.Rates == nil {
+		return ErrCurrencyMismatch
+	}
+	rate, err := uc.Rates.Rate(ctx, requested.Code, accountCurrency.Code)
+	if err != nil {
+		return err
+	}
+
+	if p.amountCents, err = domain.Convert(amount, requested, accountCurrency, rate); err != nil {
+		return err
+	}
+	if p.amountCents <= 0 {
+		return ErrInvalidAmount
+	}
+	p.originalCurrency = requested.Code
+	p.originalAmountCents = amount
+	return nil
+}
+
+func (uc CreateTransaction) ledger() ledger {
+	return ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances, outbox: uc.Outbox}
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// webhookSecretPrefix marks webhook signing secrets, so they are easy to spot
+// if they leak into logs or code.
+const webhookSecretPrefix = "whsec_"
+
+// CreateWebhook registers a subscription. Its URL must resolve, through
+// Resolver or the system's resolver when nil, to public addresses only.
+type CreateWebhook struct {
+	Subscriptions port.WebhookSubscriptionRepository
+	Accounts      port.AccountRepository
+	Resolver      port.Resolver
+}
+
+// CreateWebhookInput describes a new subscription. Empty EventTypes subscribe
+// to every event type and a zero AccountID to every account.
+type CreateWebhookInput struct {
+	URL        string
+	EventTypes []domain.EventType
+	AccountID  int64
+}
+
+// Execute registers the subscription. The returned subscription carries the
+// signing secret, which is not shown again.
+func (uc CreateWebhook) Execute(ctx context.Context, input CreateWebhookInput) (domain.WebhookSubscription, error) {
+	u, err := url.Parse(input.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return domain.WebhookSubscription{}, ErrInvalidURL
+	}
+	if err := uc.checkTarget(ctx, u.Hostname()); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	types := make([]domain.EventType, 0, len(input.EventTypes))
+	for _, t := range input.EventTypes {
+		if !domain.KnownEventType(t) {
+			return domain.WebhookSubscription{}, ErrInvalidEventType
+		}
+		types = append(types, t)
+	}
+
+	if input.AccountID < 0 {
+		return domain.WebhookSubscription{}, domain.ErrAccountNotFound
+	}
+	if input.AccountID != 0 {
+		if _, err := uc.Accounts.FindByID(ctx, input.AccountID); err != nil {
+			return domain.WebhookSubscription{}, err
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	now := time.Now()
+	sub := domain.WebhookSubscription{
+		URL:        u.String(),
+		Secret:     webhookSecretPrefix + hex.EncodeToString(secret),
+		EventTypes: types,
+		AccountID:  input.AccountID,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	id, err := uc.Subscriptions.Create(ctx, sub)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	sub.ID = id
+	return sub, nil
+}
+
+// checkTarget refuses hosts that resolve to any address that is not public.
+// The sender checks again when it connects, as the name may resolve
+// differently by then.
+func (uc CreateWebhook) checkTarget(ctx context.Context, host string) error {
+	var resolver port.Resolver = net.DefaultResolver
+	if uc.Resolver != nil {
+		resolver = uc.Resolver
+	}
+
+	addrs, err := resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(addrs) == 0 {
+		return ErrInvalidURL
+	}
+	for _, addr := range addrs {
+		if !domain.PublicAddress(addr) {
+			return domain.ErrWebhookTargetDenied
+		}
+	}
+	return nil
+}
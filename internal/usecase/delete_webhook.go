@@ -0,0 +1,18 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// DeleteWebhook deactivates a subscription. It stays in the delivery log;
+// deliveries still pending for it are dead-lettered instead of sent.
+type DeleteWebhook struct {
+	Subscriptions port.WebhookSubscriptionRepository
+}
+
+func (uc DeleteWebhook) Execute(ctx context.Context, id int64) error {
+	return uc.Subscriptions.Deactivate(ctx, id, time.Now())
+}
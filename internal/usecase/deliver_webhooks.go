@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+const (
+	// DefaultDeliveryBatchSize is how many deliveries DeliverWebhooks picks up
+	// per call.
+	DefaultDeliveryBatchSize = 100
+	// DefaultWebhookMaxAttempts is how many times a delivery is tried before
+	// it is dead-lettered.
+	DefaultWebhookMaxAttempts = 10
+	// DefaultDeliveryLease is how long a worker holds the deliveries it
+	// claims.
+	DefaultDeliveryLease = 5 * time.Minute
+)
+
+// DeliverWebhooks sends the webhook deliveries that are due. A failed
+// delivery is retried with exponential backoff until it has been tried
+// MaxAttempts times, then marked DEAD. Deliveries to a deleted subscription,
+// or to an address that is not public, are marked DEAD without retrying.
+//
+// As in RelayEvents, deliveries are claimed for Lease and sent outside any
+// database transaction; each outcome is then recorded on its own, unless the
+// delivery was redelivered meanwhile: the redelivery then stands.
+type DeliverWebhooks struct {
+	Subscriptions port.WebhookSubscriptionRepository
+	Deliveries    port.WebhookDeliveryRepository
+	Sender        port.WebhookSender
+	Clock         port.Clock
+	BatchSize     int
+	MaxAttempts   int
+	MaxBackoff    time.Duration
+	Lease         time.Duration
+}
+
+// Execute sends one batch and returns how many deliveries went through.
+// Failed attempts are recorded, not returned; the error is about the
+// delivery log itself.
+func (uc DeliverWebhooks) Execute(ctx context.Context) (int, error) {
+	limit := uc.BatchSize
+	if limit <= 0 {
+		limit = DefaultDeliveryBatchSize
+	}
+	maxAttempts := uc.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultWebhookMaxAttempts
+	}
+	maxBackoff := uc.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxRetryBackoff
+	}
+	lease := uc.Lease
+	if lease <= 0 {
+		lease = DefaultDeliveryLease
+	}
+
+	now := uc.Clock.Now()
+	due, err := uc.Deliveries.ClaimDue(ctx, now, now.Add(lease), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+
+	delivered := 0
+	subs := make(map[int64]domain.WebhookSubscription)
+	for _, d := range due {
+		if sendCtx.Err() != nil {
+			break
+		}
+		claimed := d.NextAttemptAt
+
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			sub, err = uc.Subscriptions.FindByID(ctx, d.SubscriptionID)
+			if err != nil && !errors.Is(err, domain.ErrWebhookNotFound) {
+				return delivered, err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+
+		if !sub.Active {
+			d.Status = domain.WebhookDeliveryDead
+			d.LastError = domain.ErrWebhookInactive.Error()
+			if err := uc.record(ctx, d, claimed); err != nil {
+				return delivered, err
+			}
+			continue
+		}
+
+		status, sendErr := uc.Sender.Send(sendCtx, sub, d)
+		now := uc.Clock.Now()
+		d.Attempts++
+		d.LastStatusCode = status
+		switch {
+		case sendErr == nil:
+			d.Status = domain.WebhookDeliveryDelivered
+			d.LastError = ""
+			d.DeliveredAt = now
+			delivered++
+		case d.Attempts >= maxAttempts || errors.Is(sendErr, domain.ErrWebhookTargetDenied):
+			d.Status = domain.WebhookDeliveryDead
+			d.LastError = sendErr.Error()
+		default:
+			d.NextAttemptAt = now.Add(retryBackoff(d.Attempts, maxBackoff))
+			d.LastError = sendErr.Error()
+		}
+		if err := uc.record(ctx, d, claimed); err != nil {
+			return delivered, err
+		}
+	}
+
+	return delivered, nil
+}
+
+// record saves the outcome of a delivery claimed with the given lease. A
+// delivery redelivered since it was claimed is left as the redelivery set it.
+func (uc DeliverWebhooks) record(ctx context.Context, d domain.WebhookDelivery, claimed time.Time) error {
+	err := uc.Deliveries.Update(ctx, d, claimed)
+	if errors.Is(err, domain.ErrConcurrentUpdate) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"errors"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// ErrorCategory says what kind of failure an error is, independently of the
+// transport that reports it.
+type ErrorCategory int
+
+const (
+	CategoryInternal ErrorCategory = iota
+	CategoryNotFound
+	CategoryInvalid
+	// CategoryRefused is a valid request the account's state does not allow,
+	// such as a debit beyond the available credit.
+	CategoryRefused
+	CategoryExists
+	CategoryConflict
+	CategoryConcurrent
+	CategoryClosed
+	CategoryLocked
+	CategoryUnprocessable
+)
+
+// ErrorKind describes a known error. Code is stable and meant for machines;
+// Title is for people and may change. Field names the request field the
+// error is usually about.
+type ErrorKind struct {
+	Err      error
+	Category ErrorCategory
+	Code     string
+	Title    string
+	Field    string
+}
+
+// errorKinds is matched in order with errors.Is. Errors that match nothing
+// are internal.
+var errorKinds = []ErrorKind{
+	{domain.ErrAccountNotFound, CategoryNotFound, "account_not_found", "Account not found", ""},
+	{domain.ErrOperationTypeNotFound, CategoryNotFound, "operation_type_not_found", "Operation type not found", "operation_type_id"},
+	{domain.ErrTransactionNotFound, CategoryNotFound, "transaction_not_found", "Transaction not found", ""},
+	{domain.ErrStatementNotFound, CategoryNotFound, "statement_not_found", "Statement not found", ""},
+	{domain.ErrAuthorizationNotFound, CategoryNotFound, "authorization_not_found", "Authorization not found", ""},
+	{domain.ErrWebhookNotFound, CategoryNotFound, "webhook_not_found", "Webhook not found", ""},
+	{domain.ErrDeliveryNotFound, CategoryNotFound, "delivery_not_found", "Webhook delivery not found", ""},
+
+	{ErrInvalidDocument, CategoryInvalid, "invalid_document_number", "Invalid document number", "document_number"},
+	{domain.ErrInvalidDocumentNumber, CategoryInvalid, "invalid_document_number", "Invalid document number", "document_number"},
+	{ErrInvalidCreditLimit, CategoryInvalid, "invalid_credit_limit", "Invalid credit limit", "available_credit_limit"},
+	{ErrInvalidClosingDay, CategoryInvalid, "invalid_closing_day", "Invalid closing day", "closing_day"},
+	{ErrInvalidOperation, CategoryInvalid, "invalid_operation_type", "Invalid operation type", "operation_type_id"},
+	{ErrInvalidDescription, CategoryInvalid, "invalid_description", "Invalid description", "description"},
+	{ErrInvalidSign, CategoryInvalid, "invalid_sign", "Invalid sign", "sign"},
+	{domain.ErrInvalidAccountStatus, CategoryInvalid, "invalid_account_status", "Invalid account status", "status"},
+	{ErrInvalidReason, CategoryInvalid, "invalid_reason", "Invalid reason", "reason"},
+	{ErrInvalidAmount, CategoryInvalid, "invalid_amount", "Invalid amount", "amount"},
+	{domain.ErrInvalidAmountFormat, CategoryInvalid, "invalid_amount", "Invalid amount", "amount"},
+	{domain.ErrAmountPrecision, CategoryInvalid, "amount_precision", "Too many decimal places", "amount"},
+	{ErrInvalidInstallments, CategoryInvalid, "invalid_installments", "Invalid installments", "installments"},
+	{domain.ErrUnsupportedCurrency, CategoryInvalid, "unsupported_currency", "Unsupported currency", "currency"},
+	{domain.ErrInsufficientFunds, CategoryRefused, "insufficient_funds", "Insufficient funds", "amount"},
+	{ErrInvalidPageSize, CategoryInvalid, "invalid_page_size", "Invalid page size", "limit"},
+	{ErrInvalidDateRange, CategoryInvalid, "invalid_date_range", "Invalid date range", "from"},
+	{domain.ErrInvalidCursor, CategoryInvalid, "invalid_cursor", "Invalid cursor", "cursor"},
+	{ErrInvalidURL, CategoryInvalid, "invalid_url", "Invalid URL", "url"},
+	{ErrInvalidEventType, CategoryInvalid, "invalid_event_type", "Invalid event type", "event_types"},
+	{domain.ErrInvalidDeliveryStatus, CategoryInvalid, "invalid_delivery_status", "Invalid delivery status", "status"},
+
+	{domain.ErrDocumentExists, CategoryExists, "document_exists", "Document already registered", "document_number"},
+	{domain.ErrOperationTypeExists, CategoryExists, "operation_type_exists", "Operation type already exists", ""},
+	{domain.ErrStatusTransition, CategoryConflict, "status_transition_not_allowed", "Status transition not allowed", "status"},
+	{domain.ErrAuthorizationClosed, CategoryConflict, "authorization_closed", "Authorization is no longer pending", ""},
+	{domain.ErrWebhookInactive, CategoryConflict, "webhook_inactive", "Webhook is inactive", ""},
+	{domain.ErrConflict, CategoryExists, "conflict", "Conflicting record", ""},
+	{domain.ErrConcurrentUpdate, CategoryConcurrent, "concurrent_update", "Concurrent update", ""},
+
+	{domain.ErrAccountClosed, CategoryClosed, "account_closed", "Account is closed", ""},
+	{domain.ErrAccountBlocked, CategoryLocked, "account_blocked", "Account is blocked", ""},
+
+	{ErrCurrencyMismatch, CategoryUnprocessable, "currency_mismatch", "Currency mismatch", "currency"},
+	{domain.ErrRateUnavailable, CategoryUnprocessable, "rate_unavailable", "Exchange rate unavailable", "currency"},
+	{domain.ErrWebhookTargetDenied, CategoryUnprocessable, "webhook_target_denied", "Webhook target not allowed", "url"},
+	{domain.ErrOperationTypeInactive, CategoryUnprocessable, "operation_type_inactive", "Operation type is inactive", "operation_type_id"},
+	{domain.ErrReferenceNotFound, CategoryUnprocessable, "reference_not_found", "Referenced record not found", ""},
+	{ErrNotReversible, CategoryUnprocessable, "not_reversible", "Transaction cannot be reversed", ""},
+	{ErrReversalExceeded, CategoryUnprocessable, "reversal_exceeded", "Reversal exceeds the original amount", "amount"},
+	{ErrCaptureExceeded, CategoryUnprocessable, "capture_exceeded", "Capture exceeds the authorized amount", "amount"},
+	{domain.ErrAuthorizationExpired, CategoryUnprocessable, "authorization_expired", "Authorization expired", ""},
+}
+
+// KindOf returns the kind err belongs to. ok is false for internal errors,
+// whose text should not reach clients.
+func KindOf(err error) (kind ErrorKind, ok bool) {
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind.Err) {
+			return kind, true
+		}
+	}
+	return ErrorKind{Category: CategoryInternal, Code: "internal_error"}, false
+}
@@ -1,12 +1,30 @@
 package usecase
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
 
 var (
-	ErrNotFound         = errors.New("not found")
-	ErrInvalidOperation = errors.New("invalid operation type")
-	ErrInvalidAmount    = errors.New("invalid amount")
-	ErrDocumentExists   = errors.New("document already exists")
-	ErrInvalidDocument  = errors.New("invalid document")
-	ErrNotImplemented   = errors.New("not implemented")
+	ErrNotFound            = errors.New("not found")
+	ErrInvalidOperation    = errors.New("invalid operation type")
+	ErrInvalidAmount       = errors.New("invalid amount")
+	ErrDocumentExists      = domain.ErrDocumentExists
+	ErrInvalidDocument     = errors.New("invalid document")
+	ErrInvalidCreditLimit  = errors.New("invalid credit limit")
+	ErrInvalidPageSize     = errors.New("invalid page size")
+	ErrInvalidDateRange    = errors.New("invalid date range")
+	ErrNotReversible       = errors.New("transaction cannot be reversed")
+	ErrReversalExceeded    = errors.New("reversal exceeds the original amount")
+	ErrCaptureExceeded     = errors.New("capture exceeds the authorized amount")
+	ErrInvalidInstallments = errors.New("invalid installments")
+	ErrInvalidClosingDay   = errors.New("invalid closing day")
+	ErrCurrencyMismatch    = errors.New("transaction currency differs from the account currency")
+	ErrInvalidDescription  = errors.New("invalid description")
+	ErrInvalidSign         = errors.New("sign must be -1 or 1")
+	ErrInvalidReason       = errors.New("a reason is required")
+	ErrInvalidURL          = errors.New("url must be an absolute http or https url")
+	ErrInvalidEventType    = errors.New("unknown event type")
+	ErrNotImplemented      = errors.New("not implemented")
 )
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// DefaultExpiryBatchSize is how many authorizations ExpireAuthorizations picks
+// up per call.
+const DefaultExpiryBatchSize = 100
+
+// ExpireAuthorizations releases the holds of pending authorizations whose
+// window has ended.
+type ExpireAuthorizations struct {
+	Accounts           port.AccountRepository
+	OperationTypes     port.OperationTypeRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	Authorizations     port.AuthorizationRepository
+	TransactionManager port.TransactionManager
+	BatchSize          int
+}
+
+// Execute expires the authorizations due at asOf and returns how many it
+// expired.
+func (uc ExpireAuthorizations) Execute(ctx context.Context, asOf time.Time) (int, error) {
+	limit := uc.BatchSize
+	if limit <= 0 {
+		limit = DefaultExpiryBatchSize
+	}
+
+	due, err := uc.Authorizations.FindExpired(ctx, asOf, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	l := ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances}
+	expired := 0
+	for _, auth := range due {
+		err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+			acc, err := uc.Accounts.FindByIDForUpdate(txCtx, auth.AccountID)
+			if err != nil {
+				return err
+			}
+
+			// It may have been captured or voided while we waited for the lock.
+			auth, err := uc.Authorizations.FindByIDForUpdate(txCtx, auth.ID)
+			if err != nil {
+				return err
+			}
+			if !auth.Expired(asOf) {
+				return domain.ErrAuthorizationClosed
+			}
+
+			if _, _, err := releaseAuthorization(txCtx, l, uc.OperationTypes, acc, auth); err != nil {
+				return err
+			}
+
+			auth.Status = domain.AuthorizationStatusExpired
+			auth.UpdatedAt = asOf
+			return uc.Authorizations.Close(txCtx, auth)
+		})
+		if errors.Is(err, domain.ErrAuthorizationClosed) {
+			continue
+		}
+		if err != nil {
+			return expired, err
+		}
+		expired++
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetAccountBalance struct {
+	Accounts port.AccountRepository
+	Balances port.AccountBalanceRepository
+}
+
+func (uc GetAccountBalance) Execute(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	acc, err := uc.Accounts.FindByID(ctx, accountID)
+	if err != nil {
+		return domain.AccountBalance{}, err
+	}
+
+	balance, err := uc.Balances.FindByAccountID(ctx, accountID)
+	if err != nil {
+		return domain.AccountBalance{}, err
+	}
+
+	balance.Currency = acc.Currency
+	return balance, nil
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetAuthorization struct {
+	Authorizations port.AuthorizationRepository
+}
+
+func (uc GetAuthorization) Execute(ctx context.Context, id int64) (domain.Authorization, error) {
+	return uc.Authorizations.FindByID(ctx, id)
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetOperationType struct {
+	OperationTypes port.OperationTypeRepository
+}
+
+func (uc GetOperationType) Execute(ctx context.Context, id int) (domain.OperationType, error) {
+	return uc.OperationTypes.FindByID(ctx, id)
+}
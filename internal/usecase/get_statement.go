@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetStatement struct {
+	Statements port.StatementRepository
+}
+
+func (uc GetStatement) Execute(ctx context.Context, id int64) (domain.Statement, error) {
+	return uc.Statements.FindByID(ctx, id)
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetTransaction struct {
+	Accounts       port.AccountRepository
+	OperationTypes port.OperationTypeRepository
+	Transactions   port.TransactionRepository
+}
+
+// TransactionDetails is a transaction together with the records it refers to.
+// Installments is only filled for the parent of an installment plan.
+type TransactionDetails struct {
+	Transaction   domain.Transaction
+	OperationType domain.OperationType
+	Account       domain.Account
+	Installments  []domain.Transaction
+}
+
+func (uc GetTransaction) Execute(ctx context.Context, id int64) (TransactionDetails, error) {
+	tx, err := uc.Transactions.FindByID(ctx, id)
+	if err != nil {
+		return TransactionDetails{}, err
+	}
+
+	op, err := uc.OperationTypes.FindByID(ctx, tx.OperationTypeID)
+	if err != nil {
+		return TransactionDetails{}, err
+	}
+
+	acc, err := uc.Accounts.FindByID(ctx, tx.AccountID)
+	if err != nil {
+		return TransactionDetails{}, err
+	}
+
+	details := TransactionDetails{Transaction: tx, OperationType: op, Account: acc}
+	if tx.IsInstallmentPlan() {
+		if details.Installments, err = uc.Transactions.ListInstallments(ctx, tx.ID); err != nil {
+			return TransactionDetails{}, err
+		}
+	}
+
+	return details, nil
+}
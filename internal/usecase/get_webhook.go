@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type GetWebhook struct {
+	Subscriptions port.WebhookSubscriptionRepository
+}
+
+func (uc GetWebhook) Execute(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	return uc.Subscriptions.FindByID(ctx, id)
+}
@@ -0,0 +1,288 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// ledger records movements on an account. It must be used inside the
+// RunInTransaction block that already holds the account's row lock. When
+// outbox is set, every posted transaction is announced through it.
+type ledger struct {
+	accounts     port.AccountRepository
+	transactions port.TransactionRepository
+	balances     port.AccountBalanceRepository
+	outbox       port.OutboxRepository
+}
+
+// posting describes a movement to record. amountCents is unsigned and in the
+// account's currency; the operation type decides whether it is a debit or a
+// credit. originalCurrency and originalAmountCents are set when the amount was
+// converted from another currency.
+type posting struct {
+	account             domain.Account
+	operationType       domain.OperationType
+	amountCents         int64
+	originalCurrency    string
+	originalAmountCents int64
+	reversedOf          *domain.Transaction
+}
+
+// affectsCreditLimit reports whether the posting moves the available credit
+// limit. A reversal moves it back only if the transaction it compensates moved
+// it, whatever the reversal's own operation type says.
+func (p posting) affectsCreditLimit() bool {
+	if p.reversedOf != nil {
+		return p.reversedOf.AffectsCreditLimit
+	}
+	return p.operationType.AffectsCreditLimit
+}
+
+func (l ledger) post(ctx context.Context, p posting) (domain.Transaction, error) {
+	op := p.operationType
+	if op.Sign != -1 && op.Sign != 1 {
+		return domain.Transaction{}, ErrInvalidOperation
+	}
+
+	// Debits consume the available credit limit and credits give it back.
+	if err := l.moveLimit(ctx, p, int64(op.Sign)*p.amountCents); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	normalized := int64(op.Sign) * p.amountCents
+
+	// Debits stay open until a credit pays them; credits first pay down older
+	// debits and only keep what is left over.
+	var balance int64
+	var err error
+	if op.Sign > 0 {
+		balance, err = l.discharge(ctx, p.account.ID, p.amountCents, p.reversedOf)
+	} else {
+		balance, err = l.consumeReversedCredit(ctx, p.amountCents, p.reversedOf)
+	}
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	now := time.Now()
+	tx := domain.Transaction{
+		AccountID:       p.account.ID,
+		OperationTypeID: op.ID,
+		AmountCents:     normalized,
+		BalanceCents:    balance,
+		Currency:        p.account.Currency,
+		Status:          domain.TransactionStatusPosted,
+		EventDate:       now,
+		CreatedAt:       now,
+	}
+	tx.AffectsCreditLimit = p.affectsCreditLimit()
+	if p.originalCurrency != "" {
+		tx.OriginalCurrency = p.originalCurrency
+		tx.OriginalAmountCents = int64(op.Sign) * p.originalAmountCents
+	}
+	if p.reversedOf != nil {
+		tx.ReversedOf = p.reversedOf.ID
+	}
+
+	id, err := l.transactions.Create(ctx, tx)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	tx.ID = id
+
+	if err := l.balances.Apply(ctx, p.account.ID, normalized, 0); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if err := l.announce(ctx, tx); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return tx, nil
+}
+
+// postInstallments records a debit split into n monthly installments. The
+// whole amount is taken from the credit limit at once, but only the first
+// installment is posted; the others are kept as pending until they are due.
+func (l ledger) postInstallments(ctx context.Context, p posting, n int) (domain.Transaction, error) {
+	op := p.operationType
+	if op.Sign != -1 {
+		return domain.Transaction{}, ErrInvalidOperation
+	}
+
+	if err := l.moveLimit(ctx, p, -p.amountCents); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	now := time.Now()
+	parent := domain.Transaction{
+		AccountID:       p.account.ID,
+		OperationTypeID: op.ID,
+		AmountCents:     -p.amountCents,
+		Currency:        p.account.Currency,
+		Status:          domain.TransactionStatusPosted,
+		Installments:    n,
+		EventDate:       now,
+		CreatedAt:       now,
+	}
+	parent.AffectsCreditLimit = p.affectsCreditLimit()
+	if p.originalCurrency != "" {
+		parent.OriginalCurrency = p.originalCurrency
+		parent.OriginalAmountCents = -p.originalAmountCents
+	}
+
+	id, err := l.transactions.Create(ctx, parent)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	parent.ID = id
+
+	var postedCents int64
+	for i, amount := range domain.SplitInstallments(p.amountCents, n) {
+		child := domain.Transaction{
+			AccountID:         p.account.ID,
+			OperationTypeID:   op.ID,
+			AmountCents:       -amount,
+			Currency:          p.account.Currency,
+			Status:            domain.TransactionStatusScheduled,
+			ParentID:          parent.ID,
+			InstallmentNumber: i + 1,
+			EventDate:         domain.InstallmentDate(now, i+1),
+			CreatedAt:         now,
+		}
+		child.AffectsCreditLimit = parent.AffectsCreditLimit
+		if !child.EventDate.After(now) {
+			child.Status = domain.TransactionStatusPosted
+			child.BalanceCents = child.AmountCents
+			postedCents += child.AmountCents
+		}
+
+		if _, err := l.transactions.Create(ctx, child); err != nil {
+			return domain.Transaction{}, err
+		}
+	}
+
+	if err := l.balances.Apply(ctx, p.account.ID, postedCents, p.amountCents+postedCents); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	if err := l.announce(ctx, parent); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return parent, nil
+}
+
+// hold reserves a debit of p.amountCents without posting it: the amount leaves
+// the available credit limit and shows up as pending on the balance.
+func (l ledger) hold(ctx context.Context, p posting) error {
+	if p.operationType.Sign != -1 {
+		return ErrInvalidOperation
+	}
+	if err := l.moveLimit(ctx, p, -p.amountCents); err != nil {
+		return err
+	}
+	return l.balances.Apply(ctx, p.account.ID, 0, p.amountCents)
+}
+
+// release undoes a hold of p.amountCents and returns the account with its
+// credit limit updated, so that the caller can keep posting on it.
+func (l ledger) release(ctx context.Context, p posting) (domain.Account, error) {
+	if err := l.moveLimit(ctx, p, p.amountCents); err != nil {
+		return domain.Account{}, err
+	}
+	if err := l.balances.Apply(ctx, p.account.ID, 0, -p.amountCents); err != nil {
+		return domain.Account{}, err
+	}
+
+	acc := p.account
+	if p.affectsCreditLimit() {
+		acc.AvailableCreditLimitCents += p.amountCents
+	}
+	return acc, nil
+}
+
+// announce records a TransactionPosted event for tx in the outbox.
+func (l ledger) announce(ctx context.Context, tx domain.Transaction) error {
+	return announceTransaction(ctx, l.outbox, tx)
+}
+
+// announceTransaction records a TransactionPosted event for tx, unless outbox
+// is nil.
+func announceTransaction(ctx context.Context, outbox port.OutboxRepository, tx domain.Transaction) error {
+	if outbox == nil {
+		return nil
+	}
+	event, err := domain.NewTransactionPosted(tx, time.Now())
+	if err != nil {
+		return err
+	}
+	return outbox.Add(ctx, event)
+}
+
+// moveLimit adds deltaCents to the account's available credit limit, unless
+// the posting does not touch the limit.
+func (l ledger) moveLimit(ctx context.Context, p posting, deltaCents int64) error {
+	if !p.affectsCreditLimit() {
+		return nil
+	}
+
+	limit := p.account.AvailableCreditLimitCents + deltaCents
+	if limit < 0 {
+		return domain.ErrInsufficientFunds
+	}
+	return l.accounts.UpdateAvailableCreditLimit(ctx, p.account.ID, limit)
+}
+
+// discharge pays down the account's open debits with a credit of creditCents,
+// oldest event first, and returns the part of the credit left unused. When the
+// credit reverses a debit, that debit is paid before any other.
+func (l ledger) discharge(ctx context.Context, accountID int64, creditCents int64, reversedOf *domain.Transaction) (int64, error) {
+	debits, err := l.transactions.FindOpenDebitsForUpdate(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	if reversedOf != nil {
+		for i, debit := range debits {
+			if debit.ID == reversedOf.ID {
+				copy(debits[1:i+1], debits[:i])
+				debits[0] = debit
+				break
+			}
+		}
+	}
+
+	remaining := creditCents
+	for _, debit := range debits {
+		if remaining == 0 {
+			break
+		}
+
+		paid := min(remaining, -debit.BalanceCents)
+		if err := l.transactions.UpdateBalance(ctx, debit.ID, debit.BalanceCents+paid); err != nil {
+			return 0, err
+		}
+		remaining -= paid
+	}
+
+	return remaining, nil
+}
+
+// consumeReversedCredit returns the balance of a new debit. A debit reversing a
+// credit first takes back whatever the credit has not paid off yet.
+func (l ledger) consumeReversedCredit(ctx context.Context, debitCents int64, reversedOf *domain.Transaction) (int64, error) {
+	if reversedOf == nil || reversedOf.BalanceCents <= 0 {
+		return -debitCents, nil
+	}
+
+	taken := min(debitCents, reversedOf.BalanceCents)
+	if err := l.transactions.UpdateBalance(ctx, reversedOf.ID, reversedOf.BalanceCents-taken); err != nil {
+		return 0, err
+	}
+
+	return -(debitCents - taken), nil
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListAccountStatusChanges struct {
+	Accounts      port.AccountRepository
+	StatusChanges port.AccountStatusChangeRepository
+}
+
+func (uc ListAccountStatusChanges) Execute(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error) {
+	if _, err := uc.Accounts.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return uc.StatusChanges.ListByAccount(ctx, accountID)
+}
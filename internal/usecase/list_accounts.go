@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListAccounts struct {
+	Accounts  port.AccountRepository
+	Documents port.DocumentValidator
+}
+
+// ListAccountsInput selects accounts. DocumentNumber is matched exactly after
+// normalization, so formatted numbers find the account too.
+type ListAccountsInput struct {
+	domain.AccountFilter
+	DocumentCountry string
+}
+
+type AccountPage struct {
+	Accounts []domain.Account
+	// Next is nil on the last page.
+	Next *domain.Cursor
+}
+
+func (uc ListAccounts) Execute(ctx context.Context, input ListAccountsInput) (AccountPage, error) {
+	filter := input.AccountFilter
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return AccountPage{}, ErrInvalidDateRange
+	}
+	if filter.Status != "" {
+		filter.Status = domain.AccountStatus(strings.ToUpper(string(filter.Status)))
+		if !filter.Status.Valid() {
+			return AccountPage{}, domain.ErrInvalidAccountStatus
+		}
+	}
+	if filter.DocumentNumber != "" {
+		country := input.DocumentCountry
+		if country == "" {
+			country = domain.DefaultDocumentCountry
+		}
+		document, err := uc.Documents.Normalize(country, filter.DocumentNumber)
+		if err != nil {
+			return AccountPage{}, err
+		}
+		filter.DocumentNumber = document
+	}
+
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return AccountPage{}, err
+	}
+
+	// One extra row tells whether another page exists.
+	filter.Limit = limit + 1
+	accounts, err := uc.Accounts.List(ctx, filter)
+	if err != nil {
+		return AccountPage{}, err
+	}
+
+	page := AccountPage{Accounts: accounts}
+	if len(accounts) > limit {
+		page.Accounts = accounts[:limit]
+		last := page.Accounts[limit-1]
+		page.Next = &domain.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
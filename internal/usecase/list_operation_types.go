@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListOperationTypes struct {
+	OperationTypes port.OperationTypeRepository
+}
+
+// Execute lists operation types by ID, leaving out inactive ones when
+// activeOnly is set.
+func (uc ListOperationTypes) Execute(ctx context.Context, activeOnly bool) ([]domain.OperationType, error) {
+	types, err := uc.OperationTypes.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !activeOnly {
+		return types, nil
+	}
+
+	active := types[:0]
+	for _, ot := range types {
+		if ot.Active {
+			active = append(active, ot)
+		}
+	}
+	return active, nil
+}
@@ -0,0 +1,22 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListStatements struct {
+	Accounts   port.AccountRepository
+	Statements port.StatementRepository
+}
+
+// Execute returns the account's statements, newest first.
+func (uc ListStatements) Execute(ctx context.Context, accountID int64) ([]domain.Statement, error) {
+	if _, err := uc.Accounts.FindByID(ctx, accountID); err != nil {
+		return nil, err
+	}
+
+	return uc.Statements.ListByAccount(ctx, accountID)
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+)
+
+type ListTransactions struct {
+	Accounts     port.AccountRepository
+	Transactions port.TransactionRepository
+}
+
+type TransactionPage struct {
+	Transactions []domain.Transaction
+	// Next is nil on the last page.
+	Next *domain.Cursor
+}
+
+func (uc ListTransactions) Execute(ctx context.Context, filter domain.TransactionFilter) (TransactionPage, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return TransactionPage{}, ErrInvalidDateRange
+	}
+
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return TransactionPage{}, err
+	}
+
+	if _, err := uc.Accounts.FindByID(ctx, filter.AccountID); err != nil {
+		return TransactionPage{}, err
+	}
+
+	// One extra row tells whether another page exists.
+	filter.Limit = limit + 1
+	txs, err := uc.Transactions.ListByAccount(ctx, filter)
+	if err != nil {
+		return TransactionPage{}, err
+	}
+
+	page := TransactionPage{Transactions: txs}
+	if len(txs) > limit {
+		page.Transactions = txs[:limit]
+		last := page.Transactions[limit-1]
+		page.Next = &domain.Cursor{Time: last.EventDate, ID: last.ID}
+	}
+
+	return page, nil
+}
+
+func pageSize(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return DefaultPageSize, nil
+	case limit < 0 || limit > MaxPageSize:
+		return 0, ErrInvalidPageSize
+	default:
+		return limit, nil
+	}
+}
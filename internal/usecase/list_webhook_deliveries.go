@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListWebhookDeliveries struct {
+	Subscriptions port.WebhookSubscriptionRepository
+	Deliveries    port.WebhookDeliveryRepository
+}
+
+type WebhookDeliveryPage struct {
+	Deliveries []domain.WebhookDelivery
+	// Next is nil on the last page.
+	Next *domain.Cursor
+}
+
+func (uc ListWebhookDeliveries) Execute(ctx context.Context, filter domain.WebhookDeliveryFilter) (WebhookDeliveryPage, error) {
+	if filter.Status != "" {
+		filter.Status = domain.WebhookDeliveryStatus(strings.ToUpper(string(filter.Status)))
+		if !filter.Status.Valid() {
+			return WebhookDeliveryPage{}, domain.ErrInvalidDeliveryStatus
+		}
+	}
+
+	limit, err := pageSize(filter.Limit)
+	if err != nil {
+		return WebhookDeliveryPage{}, err
+	}
+
+	if _, err := uc.Subscriptions.FindByID(ctx, filter.SubscriptionID); err != nil {
+		return WebhookDeliveryPage{}, err
+	}
+
+	// One extra row tells whether another page exists.
+	filter.Limit = limit + 1
+	deliveries, err := uc.Deliveries.ListBySubscription(ctx, filter)
+	if err != nil {
+		return WebhookDeliveryPage{}, err
+	}
+
+	page := WebhookDeliveryPage{Deliveries: deliveries}
+	if len(deliveries) > limit {
+		page.Deliveries = deliveries[:limit]
+		last := page.Deliveries[limit-1]
+		page.Next = &domain.Cursor{Time: last.CreatedAt, ID: last.ID}
+	}
+
+	return page, nil
+}
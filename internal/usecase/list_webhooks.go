@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+type ListWebhooks struct {
+	Subscriptions port.WebhookSubscriptionRepository
+}
+
+func (uc ListWebhooks) Execute(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return uc.Subscriptions.List(ctx)
+}
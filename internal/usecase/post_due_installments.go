@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// DefaultPostingBatchSize is how many scheduled installments PostDueInstallments
+// picks up per call.
+const DefaultPostingBatchSize = 100
+
+// PostDueInstallments posts the scheduled installments whose event date has
+// come, moving their amount from pending to posted on the account balance.
+// When Outbox is set, each posted installment is announced through it.
+type PostDueInstallments struct {
+	Accounts           port.AccountRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	TransactionManager port.TransactionManager
+	Outbox             port.OutboxRepository
+	BatchSize          int
+}
+
+// Execute posts the installments due at asOf and returns how many it posted.
+func (uc PostDueInstallments) Execute(ctx context.Context, asOf time.Time) (int, error) {
+	limit := uc.BatchSize
+	if limit <= 0 {
+		limit = DefaultPostingBatchSize
+	}
+
+	due, err := uc.Transactions.FindDueScheduled(ctx, asOf, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	posted := 0
+	for _, tx := range due {
+		err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+			if _, err := uc.Accounts.FindByIDForUpdate(txCtx, tx.AccountID); err != nil {
+				return err
+			}
+
+			// Another worker may have posted it while we waited for the lock.
+			if err := uc.Transactions.MarkPosted(txCtx, tx.ID, tx.AmountCents); err != nil {
+				return err
+			}
+
+			if err := uc.Balances.Apply(txCtx, tx.AccountID, tx.AmountCents, tx.AmountCents); err != nil {
+				return err
+			}
+
+			tx.Status = domain.TransactionStatusPosted
+			tx.BalanceCents = tx.AmountCents
+			return announceTransaction(txCtx, uc.Outbox, tx)
+		})
+		if errors.Is(err, domain.ErrTransactionNotFound) {
+			continue
+		}
+		if err != nil {
+			return posted, err
+		}
+		posted++
+	}
+
+	return posted, nil
+}
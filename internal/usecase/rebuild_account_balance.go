@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// RebuildAccountBalance recomputes the balance projection from the ledger,
+// holding the account lock so no transaction is posted meanwhile.
+type RebuildAccountBalance struct {
+	Accounts           port.AccountRepository
+	Balances           port.AccountBalanceRepository
+	TransactionManager port.TransactionManager
+}
+
+func (uc RebuildAccountBalance) Execute(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	var balance domain.AccountBalance
+
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return err
+		}
+
+		balance, err = uc.Balances.Rebuild(txCtx, accountID)
+		balance.Currency = acc.Currency
+		return err
+	})
+
+	if err != nil {
+		return domain.AccountBalance{}, err
+	}
+
+	return balance, nil
+}
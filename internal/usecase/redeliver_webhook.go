@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// RedeliverWebhook queues a delivery to be sent again right away, whatever
+// its status, with a fresh set of attempts. It fails with
+// domain.ErrConcurrentUpdate if the delivery changes while it runs, such as
+// when a worker claims it or records an attempt.
+type RedeliverWebhook struct {
+	Subscriptions port.WebhookSubscriptionRepository
+	Deliveries    port.WebhookDeliveryRepository
+	Clock         port.Clock
+}
+
+func (uc RedeliverWebhook) Execute(ctx context.Context, subscriptionID, deliveryID int64) (domain.WebhookDelivery, error) {
+	sub, err := uc.Subscriptions.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if !sub.Active {
+		return domain.WebhookDelivery{}, domain.ErrWebhookInactive
+	}
+
+	d, err := uc.Deliveries.FindByID(ctx, deliveryID)
+	if err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+	if d.SubscriptionID != sub.ID {
+		return domain.WebhookDelivery{}, domain.ErrDeliveryNotFound
+	}
+
+	seen := d.NextAttemptAt
+	d.Status = domain.WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = uc.Clock.Now()
+	d.DeliveredAt = time.Time{}
+	if err := uc.Deliveries.Update(ctx, d, seen); err != nil {
+		return domain.WebhookDelivery{}, err
+	}
+
+	return d, nil
+}
@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+const (
+	// DefaultRelayBatchSize is how many events RelayEvents picks up per call.
+	DefaultRelayBatchSize = 100
+	// DefaultMaxRetryBackoff caps the wait between two attempts to publish an
+	// event.
+	DefaultMaxRetryBackoff = 10 * time.Minute
+	// DefaultRelayLease is how long a relay holds the events it claims.
+	DefaultRelayLease = 5 * time.Minute
+	// DefaultEventMaxAttempts is how many times an event is published before
+	// it is given up on.
+	DefaultEventMaxAttempts = 20
+)
+
+// RelayEvents publishes the events waiting in the outbox. An event that fails
+// is retried with exponential backoff and holds back the later events of its
+// account until it goes through, or until it fails MaxAttempts times: it is
+// then marked dead, reported to Logger, and the account's later events go
+// out without it.
+//
+// Events are claimed for Lease and published outside any database
+// transaction. Publishing stops when the lease runs out; events left over, or
+// claimed by a relay that died, are claimed again once their lease expires.
+type RelayEvents struct {
+	Outbox     port.OutboxRepository
+	Publisher  port.EventPublisher
+	Clock      port.Clock
+	BatchSize  int
+	MaxBackoff time.Duration
+	Lease      time.Duration
+	// MaxAttempts defaults to DefaultEventMaxAttempts.
+	MaxAttempts int
+	// Logger, if set, is told about dead events.
+	Logger port.Logger
+}
+
+// Execute publishes one batch and returns how many events went out. Failed
+// publications are recorded, not returned; the error is about the outbox
+// itself.
+func (uc RelayEvents) Execute(ctx context.Context) (int, error) {
+	limit := uc.BatchSize
+	if limit <= 0 {
+		limit = DefaultRelayBatchSize
+	}
+	maxBackoff := uc.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxRetryBackoff
+	}
+	lease := uc.Lease
+	if lease <= 0 {
+		lease = DefaultRelayLease
+	}
+	maxAttempts := uc.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultEventMaxAttempts
+	}
+
+	now := uc.Clock.Now()
+	events, err := uc.Outbox.ClaimPending(ctx, now, now.Add(lease), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	pubCtx, cancel := context.WithTimeout(ctx, lease)
+	defer cancel()
+
+	published := 0
+	for _, event := range events {
+		if pubCtx.Err() != nil {
+			break
+		}
+		if err := uc.Publisher.Publish(pubCtx, event); err != nil {
+			if err := uc.recordFailure(ctx, event, err, maxAttempts, maxBackoff); err != nil {
+				return published, err
+			}
+			continue
+		}
+		if err := uc.Outbox.MarkPublished(ctx, event.ID, uc.Clock.Now()); err != nil {
+			return published, err
+		}
+		published++
+	}
+
+	return published, nil
+}
+
+func (uc RelayEvents) recordFailure(ctx context.Context, event domain.Event, pubErr error, maxAttempts int, maxBackoff time.Duration) error {
+	attempts := event.Attempts + 1
+	now := uc.Clock.Now()
+	if attempts < maxAttempts {
+		return uc.Outbox.MarkFailed(ctx, event.ID, attempts, now.Add(retryBackoff(attempts, maxBackoff)), pubErr.Error())
+	}
+
+	if err := uc.Outbox.MarkDead(ctx, event.ID, attempts, now, pubErr.Error()); err != nil {
+		return err
+	}
+	if uc.Logger != nil {
+		uc.Logger.Error("event is dead", map[string]any{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+			"account_id": event.AccountID,
+			"attempts":   attempts,
+			"error":      pubErr.Error(),
+		})
+	}
+	return nil
+}
+
+// retryBackoff is how long to wait after the given number of failed attempts:
+// one second after the first, doubling up to ceiling.
+func retryBackoff(attempts int, ceiling time.Duration) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 31 {
+		return ceiling
+	}
+	return min(time.Second<<(attempts-1), ceiling)
+}
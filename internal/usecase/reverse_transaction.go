@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// ReverseTransaction posts a compensating transaction with the opposite sign,
+// linked to the original through ReversedOf. Several partial reversals are
+// allowed as long as together they do not exceed the original amount.
+type ReverseTransaction struct {
+	Accounts           port.AccountRepository
+	OperationTypes     port.OperationTypeRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	TransactionManager port.TransactionManager
+	Outbox             port.OutboxRepository
+}
+
+// Execute reverses amount, in the transaction's currency, or whatever is
+// still reversible when amount is zero.
+func (uc ReverseTransaction) Execute(ctx context.Context, transactionID int64, amount domain.Decimal) (domain.Transaction, error) {
+	if amount.Units < 0 {
+		return domain.Transaction{}, ErrInvalidAmount
+	}
+
+	original, err := uc.Transactions.FindByID(ctx, transactionID)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	var tx domain.Transaction
+
+	err = uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, original.AccountID)
+		if err != nil {
+			return err
+		}
+
+		// Balances may have moved before the lock was taken.
+		original, err = uc.Transactions.FindByID(txCtx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		// Installment plans are reversed one posted installment at a time.
+		if original.ReversedOf != 0 || original.IsInstallmentPlan() || original.Status != domain.TransactionStatusPosted {
+			return ErrNotReversible
+		}
+
+		originalOp, err := uc.OperationTypes.FindByID(txCtx, original.OperationTypeID)
+		if err != nil {
+			return err
+		}
+		if !originalOp.Reversible {
+			return ErrNotReversible
+		}
+
+		reversed, err := uc.Transactions.SumReversals(txCtx, original.ID)
+		if err != nil {
+			return err
+		}
+
+		currency, err := domain.LookupCurrency(original.Currency)
+		if err != nil {
+			return err
+		}
+		amountCents, err := amount.MinorUnits(currency.Exponent)
+		if err != nil {
+			return err
+		}
+
+		remaining := abs(original.AmountCents) - reversed
+		if amountCents == 0 {
+			amountCents = remaining
+		}
+		if amountCents == 0 || amountCents > remaining {
+			return ErrReversalExceeded
+		}
+
+		op, err := uc.OperationTypes.FindByID(txCtx, domain.ReversalOperationType(original.AmountCents))
+		if err != nil {
+			return err
+		}
+		if !op.Active {
+			return domain.ErrOperationTypeInactive
+		}
+		if err := acc.Accepts(op.Sign); err != nil {
+			return err
+		}
+		if int64(op.Sign)*original.AmountCents > 0 {
+			return ErrInvalidOperation
+		}
+
+		l := ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances, outbox: uc.Outbox}
+		tx, err = l.post(txCtx, posting{
+			account:       acc,
+			operationType: op,
+			amountCents:   amountCents,
+			reversedOf:    &original,
+		})
+		return err
+	})
+
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	return tx, nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// UpdateCreditLimit replaces the account's available credit limit under the
+// same row lock used when posting transactions.
+type UpdateCreditLimit struct {
+	Accounts           port.AccountRepository
+	TransactionManager port.TransactionManager
+}
+
+// Execute sets the limit, given in the account's currency.
+func (uc UpdateCreditLimit) Execute(ctx context.Context, accountID int64, limit domain.Decimal) (domain.Account, error) {
+	if limit.Units < 0 {
+		return domain.Account{}, ErrInvalidCreditLimit
+	}
+
+	var acc domain.Account
+
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		acc, err = uc.Accounts.FindByIDForUpdate(txCtx, accountID)
+		if err != nil {
+			return err
+		}
+
+		currency, err := domain.LookupCurrency(acc.Currency)
+		if err != nil {
+			return err
+		}
+		limitCents, err := limit.MinorUnits(currency.Exponent)
+		if err != nil {
+			return err
+		}
+
+		if err := uc.Accounts.UpdateAvailableCreditLimit(txCtx, accountID, limitCents); err != nil {
+			return err
+		}
+
+		acc.AvailableCreditLimitCents = limitCents
+		return nil
+	})
+
+	if err != nil {
+		return domain.Account{}, err
+	}
+
+	return acc, nil
+}
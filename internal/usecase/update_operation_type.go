@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// UpdateOperationType changes an operation type's description and rules.
+// The sign is fixed at creation: transactions already recorded were signed
+// with it. AffectsCreditLimit only applies to new transactions, as each one
+// records whether it moved the limit.
+// The type is read and written under a row lock, so concurrent updates
+// apply one after the other instead of overwriting each other. OperationTypes
+// must not be cached: a stale copy would be written back over newer changes.
+// Cache, if set, is invalidated once the update is committed.
+type UpdateOperationType struct {
+	OperationTypes     port.OperationTypeRepository
+	TransactionManager port.TransactionManager
+	Cache              port.OperationTypeCache
+}
+
+// UpdateOperationTypeInput holds the fields to change; nil ones are kept.
+type UpdateOperationTypeInput struct {
+	Description        *string
+	AffectsCreditLimit *bool
+	Reversible         *bool
+	Active             *bool
+}
+
+func (uc UpdateOperationType) Execute(ctx context.Context, id int, input UpdateOperationTypeInput) (domain.OperationType, error) {
+	var description string
+	if input.Description != nil {
+		description = strings.TrimSpace(*input.Description)
+		if description == "" {
+			return domain.OperationType{}, ErrInvalidDescription
+		}
+	}
+
+	var ot domain.OperationType
+	err := uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		ot, err = uc.OperationTypes.FindByIDForUpdate(txCtx, id)
+		if err != nil {
+			return err
+		}
+
+		if input.Description != nil {
+			ot.Description = description
+		}
+		ot.AffectsCreditLimit = valueOr(input.AffectsCreditLimit, ot.AffectsCreditLimit)
+		ot.Reversible = valueOr(input.Reversible, ot.Reversible)
+		ot.Active = valueOr(input.Active, ot.Active)
+		ot.UpdatedAt = time.Now()
+
+		return uc.OperationTypes.Update(txCtx, ot)
+	})
+	// Invalidate even on failure: the commit may have landed before the
+	// error was reported.
+	if uc.Cache != nil {
+		uc.Cache.Invalidate(id)
+	}
+	if err != nil {
+		return domain.OperationType{}, err
+	}
+
+	return ot, nil
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// VoidAuthorization cancels a pending authorization and releases its hold.
+type VoidAuthorization struct {
+	Accounts           port.AccountRepository
+	OperationTypes     port.OperationTypeRepository
+	Transactions       port.TransactionRepository
+	Balances           port.AccountBalanceRepository
+	Authorizations     port.AuthorizationRepository
+	TransactionManager port.TransactionManager
+	Clock              port.Clock
+}
+
+func (uc VoidAuthorization) Execute(ctx context.Context, authorizationID int64) (domain.Authorization, error) {
+	auth, err := uc.Authorizations.FindByID(ctx, authorizationID)
+	if err != nil {
+		return domain.Authorization{}, err
+	}
+
+	err = uc.TransactionManager.RunInTransaction(ctx, func(txCtx context.Context) error {
+		acc, err := uc.Accounts.FindByIDForUpdate(txCtx, auth.AccountID)
+		if err != nil {
+			return err
+		}
+
+		auth, err = uc.Authorizations.FindByIDForUpdate(txCtx, authorizationID)
+		if err != nil {
+			return err
+		}
+		if auth.Status != domain.AuthorizationStatusPending {
+			return domain.ErrAuthorizationClosed
+		}
+
+		l := ledger{accounts: uc.Accounts, transactions: uc.Transactions, balances: uc.Balances}
+		if _, _, err := releaseAuthorization(txCtx, l, uc.OperationTypes, acc, auth); err != nil {
+			return err
+		}
+
+		auth.Status = domain.AuthorizationStatusVoided
+		auth.UpdatedAt = uc.Clock.Now()
+		return uc.Authorizations.Close(txCtx, auth)
+	})
+
+	if err != nil {
+		return domain.Authorization{}, err
+	}
+
+	return auth, nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// DefaultWatchPollInterval is how often WatchTransactions looks for new
+// transactions once it has caught up.
+const DefaultWatchPollInterval = time.Second
+
+// WatchTransactions follows the transactions posted to an account through the
+// TransactionPosted events of the outbox. A transaction is seen as soon as it
+// commits, without waiting for the relay to publish its event.
+type WatchTransactions struct {
+	Accounts     port.AccountRepository
+	Transactions port.TransactionRepository
+	Outbox       port.OutboxRepository
+	PollInterval time.Duration
+}
+
+// PostedTransaction is a transaction together with the event that announced
+// it. EventID is where a later watch resumes.
+type PostedTransaction struct {
+	EventID     int64
+	Transaction domain.Transaction
+}
+
+// Execute passes each transaction posted to the account after the event
+// afterEventID to send, oldest first, until ctx is done or send fails. With a
+// zero afterEventID only transactions posted from now on are sent.
+func (uc WatchTransactions) Execute(ctx context.Context, accountID, afterEventID int64, send func(PostedTransaction) error) error {
+	if afterEventID < 0 {
+		return domain.ErrInvalidCursor
+	}
+	interval := uc.PollInterval
+	if interval <= 0 {
+		interval = DefaultWatchPollInterval
+	}
+
+	if _, err := uc.Accounts.FindByID(ctx, accountID); err != nil {
+		return err
+	}
+
+	// Start after the account's own newest event: events are added under the
+	// account's lock, so any of its events still uncommitted comes later.
+	// The newest event overall could be ahead of one of them.
+	cursor := afterEventID
+	if cursor == 0 {
+		latest, err := uc.Outbox.LatestID(ctx, accountID, domain.EventTransactionPosted)
+		if err != nil {
+			return err
+		}
+		cursor = latest
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		events, err := uc.Outbox.ListByAccount(ctx, accountID, domain.EventTransactionPosted, cursor, DefaultPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			var payload domain.TransactionPostedPayload
+			if err := json.Unmarshal(event.Payload, &payload); err != nil {
+				return fmt.Errorf("failed to decode event %d: %w", event.ID, err)
+			}
+			tx, err := uc.Transactions.FindByID(ctx, payload.TransactionID)
+			if err != nil {
+				return err
+			}
+			if err := send(PostedTransaction{EventID: event.ID, Transaction: tx}); err != nil {
+				return err
+			}
+			cursor = event.ID
+		}
+
+		// A full page means more may be waiting already.
+		if len(events) == DefaultPageSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+)
+
+// WebhookFanout is the port.EventPublisher that hands events to webhook
+// subscriptions: it queues one delivery for every subscription that wants the
+// event. Queuing is idempotent, so an event relayed twice is delivered once.
+type WebhookFanout struct {
+	Subscriptions port.WebhookSubscriptionRepository
+	Deliveries    port.WebhookDeliveryRepository
+	Clock         port.Clock
+}
+
+func (uc WebhookFanout) Publish(ctx context.Context, event domain.Event) error {
+	subs, err := uc.Subscriptions.FindMatching(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	now := uc.Clock.Now()
+	for _, sub := range subs {
+		if err := uc.Deliveries.Enqueue(ctx, domain.NewWebhookDelivery(sub.ID, event, now)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
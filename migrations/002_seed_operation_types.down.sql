@@ -0,0 +1 @@
+DELETE FROM operation_types WHERE id IN (1, 2, 3, 4);
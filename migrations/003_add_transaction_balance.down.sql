@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_transactions_open_debits;
+ALTER TABLE transactions DROP COLUMN IF EXISTS balance_cents;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS balance_cents BIGINT NOT NULL DEFAULT 0;
+
+UPDATE transactions SET balance_cents = amount_cents WHERE balance_cents = 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_open_debits ON transactions(account_id, event_date, id) WHERE balance_cents < 0;
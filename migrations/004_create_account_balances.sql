@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS account_balances (
+    account_id BIGINT PRIMARY KEY REFERENCES accounts(id),
+    posted_cents BIGINT NOT NULL DEFAULT 0,
+    pending_cents BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO account_balances (account_id, posted_cents, pending_cents, updated_at)
+SELECT a.id, COALESCE(SUM(t.amount_cents), 0), 0, NOW()
+FROM accounts a
+LEFT JOIN transactions t ON t.account_id = a.id
+GROUP BY a.id
+ON CONFLICT (account_id) DO NOTHING;
@@ -0,0 +1 @@
+ALTER TABLE accounts DROP COLUMN IF EXISTS available_credit_limit_cents;
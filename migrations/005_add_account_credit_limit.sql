@@ -0,0 +1 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS available_credit_limit_cents BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+DELETE FROM operation_types WHERE id IN (5, 6);
+
+DROP INDEX IF EXISTS idx_transactions_reversed_of;
+ALTER TABLE transactions DROP COLUMN IF EXISTS reversed_of;
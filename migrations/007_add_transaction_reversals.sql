@@ -0,0 +1,8 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS reversed_of BIGINT REFERENCES transactions(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_reversed_of ON transactions(reversed_of) WHERE reversed_of IS NOT NULL;
+
+INSERT INTO operation_types (id, description, sign) VALUES
+    (5, 'DEBIT REVERSAL', 1),
+    (6, 'CREDIT REVERSAL', -1)
+ON CONFLICT (id) DO NOTHING;
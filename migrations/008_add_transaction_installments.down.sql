@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_transactions_scheduled;
+DROP INDEX IF EXISTS idx_transactions_parent_id;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS installments;
+ALTER TABLE transactions DROP COLUMN IF EXISTS installment_number;
+ALTER TABLE transactions DROP COLUMN IF EXISTS parent_id;
+ALTER TABLE transactions DROP COLUMN IF EXISTS status;
@@ -0,0 +1,7 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status VARCHAR(16) NOT NULL DEFAULT 'POSTED';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS parent_id BIGINT REFERENCES transactions(id);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS installment_number INT NOT NULL DEFAULT 0;
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS installments INT NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_transactions_parent_id ON transactions(parent_id) WHERE parent_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_transactions_scheduled ON transactions(event_date, id) WHERE status = 'SCHEDULED';
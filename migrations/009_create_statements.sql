@@ -0,0 +1,17 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS closing_day SMALLINT NOT NULL DEFAULT 1 CHECK (closing_day BETWEEN 1 AND 28);
+
+CREATE TABLE IF NOT EXISTS statements (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    opening_balance_cents BIGINT NOT NULL,
+    debits_cents BIGINT NOT NULL,
+    credits_cents BIGINT NOT NULL,
+    closing_balance_cents BIGINT NOT NULL,
+    total_due_cents BIGINT NOT NULL,
+    minimum_payment_cents BIGINT NOT NULL,
+    due_date TIMESTAMPTZ NOT NULL,
+    closed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, period_end)
+);
@@ -0,0 +1,7 @@
+ALTER TABLE statements DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_amount_cents;
+ALTER TABLE transactions DROP COLUMN IF EXISTS original_currency;
+ALTER TABLE transactions DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,7 @@
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_currency CHAR(3);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS original_amount_cents BIGINT;
+
+ALTER TABLE statements ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'BRL';
@@ -0,0 +1 @@
+-- The original formatting is not kept, so there is nothing to restore.
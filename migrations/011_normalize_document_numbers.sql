@@ -0,0 +1,11 @@
+-- Strip formatting from document numbers stored before validation existed.
+-- Rows whose normalized form already belongs to another account are left
+-- untouched for manual review.
+UPDATE accounts a
+SET document_number = upper(regexp_replace(a.document_number, '[.\-/ ]', '', 'g'))
+WHERE a.document_number <> upper(regexp_replace(a.document_number, '[.\-/ ]', '', 'g'))
+  AND NOT EXISTS (
+    SELECT 1 FROM accounts b
+    WHERE b.id <> a.id
+      AND upper(regexp_replace(b.document_number, '[.\-/ ]', '', 'g')) = upper(regexp_replace(a.document_number, '[.\-/ ]', '', 'g'))
+  );
@@ -0,0 +1,8 @@
+ALTER TABLE operation_types ALTER COLUMN id DROP DEFAULT;
+DROP SEQUENCE IF EXISTS operation_types_id_seq;
+
+ALTER TABLE operation_types DROP COLUMN IF EXISTS updated_at;
+ALTER TABLE operation_types DROP COLUMN IF EXISTS created_at;
+ALTER TABLE operation_types DROP COLUMN IF EXISTS active;
+ALTER TABLE operation_types DROP COLUMN IF EXISTS reversible;
+ALTER TABLE operation_types DROP COLUMN IF EXISTS affects_credit_limit;
@@ -0,0 +1,13 @@
+ALTER TABLE operation_types ADD COLUMN IF NOT EXISTS affects_credit_limit BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE operation_types ADD COLUMN IF NOT EXISTS reversible BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE operation_types ADD COLUMN IF NOT EXISTS active BOOLEAN NOT NULL DEFAULT TRUE;
+ALTER TABLE operation_types ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE operation_types ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- A reversal is final: reversing it again would double count.
+UPDATE operation_types SET reversible = FALSE WHERE id IN (5, 6);
+
+-- Types created through the API without an explicit id are numbered from 100,
+-- leaving the low range for the ones shipped with migrations.
+CREATE SEQUENCE IF NOT EXISTS operation_types_id_seq START WITH 100 OWNED BY operation_types.id;
+ALTER TABLE operation_types ALTER COLUMN id SET DEFAULT nextval('operation_types_id_seq');
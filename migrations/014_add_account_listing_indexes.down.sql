@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_accounts_status_created;
+DROP INDEX IF EXISTS idx_accounts_created;
@@ -0,0 +1,4 @@
+-- GET /accounts sorts by (created_at, id); document lookups use the unique
+-- index on document_number.
+CREATE INDEX IF NOT EXISTS idx_accounts_created ON accounts(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_accounts_status_created ON accounts(status, created_at, id);
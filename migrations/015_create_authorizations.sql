@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS authorizations (
+    id BIGSERIAL PRIMARY KEY,
+    account_id BIGINT NOT NULL REFERENCES accounts(id),
+    operation_type_id INT NOT NULL REFERENCES operation_types(id),
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    captured_cents BIGINT NOT NULL DEFAULT 0,
+    currency CHAR(3) NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'CAPTURED', 'VOIDED', 'EXPIRED')),
+    -- A capture posts exactly one transaction.
+    transaction_id BIGINT UNIQUE REFERENCES transactions(id),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_authorizations_pending ON authorizations(expires_at, id) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_authorizations_account_pending ON authorizations(account_id) WHERE status = 'PENDING';
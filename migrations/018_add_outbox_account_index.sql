@@ -0,0 +1,2 @@
+-- Watchers read an account's events, published or not, in order.
+CREATE INDEX IF NOT EXISTS idx_outbox_account ON outbox(account_id, id);
@@ -0,0 +1 @@
+ALTER TABLE transactions DROP COLUMN IF EXISTS affects_credit_limit;
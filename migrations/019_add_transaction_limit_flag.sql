@@ -0,0 +1,13 @@
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS affects_credit_limit BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Transactions follow the operation type they were posted with, and
+-- reversals the transaction they compensate.
+UPDATE transactions t SET affects_credit_limit = o.affects_credit_limit
+FROM operation_types o
+WHERE o.id = t.operation_type_id AND t.reversed_of IS NULL;
+
+UPDATE transactions t SET affects_credit_limit = r.affects_credit_limit
+FROM transactions r
+WHERE r.id = t.reversed_of;
+
+ALTER TABLE transactions ALTER COLUMN affects_credit_limit DROP DEFAULT;
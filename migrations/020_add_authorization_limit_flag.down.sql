@@ -0,0 +1 @@
+ALTER TABLE authorizations DROP COLUMN IF EXISTS reserves_credit_limit;
@@ -0,0 +1,8 @@
+ALTER TABLE authorizations ADD COLUMN IF NOT EXISTS reserves_credit_limit BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Holds placed so far reserved the limit if their operation type affected it.
+UPDATE authorizations a SET reserves_credit_limit = o.affects_credit_limit
+FROM operation_types o
+WHERE o.id = a.operation_type_id;
+
+ALTER TABLE authorizations ALTER COLUMN reserves_credit_limit DROP DEFAULT;
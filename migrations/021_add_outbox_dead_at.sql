@@ -0,0 +1,6 @@
+-- Events that ran out of attempts are parked: they stop holding back the
+-- rest of their account's queue.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS dead_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_unpublished;
+CREATE INDEX IF NOT EXISTS idx_outbox_unpublished ON outbox(account_id, id) WHERE published_at IS NULL AND dead_at IS NULL;
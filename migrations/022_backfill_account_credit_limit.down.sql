@@ -0,0 +1,4 @@
+ALTER TABLE accounts ALTER COLUMN available_credit_limit_cents SET DEFAULT 0;
+
+UPDATE accounts SET available_credit_limit_cents = available_credit_limit_cents - 100000
+WHERE created_at < (SELECT applied_at FROM schema_migrations WHERE version = 5);
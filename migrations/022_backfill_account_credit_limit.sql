@@ -0,0 +1,9 @@
+-- Accounts opened before limits existed were never refused a debit, but 005
+-- gave them a zero limit that refuses their next one. They get the default
+-- limit of new accounts on top of whatever they have credited since, 1000.00
+-- (every account was in BRL then). New rows always carry their limit, so the
+-- column keeps no default.
+UPDATE accounts SET available_credit_limit_cents = available_credit_limit_cents + 100000
+WHERE created_at < (SELECT applied_at FROM schema_migrations WHERE version = 5);
+
+ALTER TABLE accounts ALTER COLUMN available_credit_limit_cents DROP DEFAULT;
@@ -0,0 +1,9 @@
+// Package migrations embeds the SQL schema migrations so the binary can apply
+// them itself. Files are named NNN_description.sql, with the optional
+// NNN_description.down.sql undoing them.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
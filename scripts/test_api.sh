@@ -20,7 +20,7 @@ echo "👤 POST /accounts (Create Account)"
 echo "───────────────────────────────────────────────────"
 ACCOUNT_RESPONSE=$(curl -s -X POST "$BASE_URL/accounts" \
   -H "Content-Type: application/json" \
-  -d '{"document_number": "12345678900"}')
+  -d '{"document_number": "12345678909"}')
 echo "$ACCOUNT_RESPONSE" | jq . 2>/dev/null || echo "$ACCOUNT_RESPONSE"
 ACCOUNT_ID=$(echo "$ACCOUNT_RESPONSE" | jq -r '.account_id // 1' 2>/dev/null || echo "1")
 echo ""
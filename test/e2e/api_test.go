@@ -2,6 +2,7 @@ package e2e_test
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -10,16 +11,22 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/ory/dockertest/v3"
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/clock"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/document"
 	adapterhttp "github.com/nicolasmmb/pismo-challenge/internal/adapter/http"
 	"github.com/nicolasmmb/pismo-challenge/internal/adapter/logger"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/migration"
 	"github.com/nicolasmmb/pismo-challenge/internal/adapter/repository"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+	"github.com/nicolasmmb/pismo-challenge/migrations"
 )
 
 var db *sql.DB
@@ -66,11 +73,6 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Could not run migrations: %s", err)
 	}
 
-	// Seed Operation Types
-	if _, err := db.Exec(`INSERT INTO operation_types (id, description, sign) VALUES (4, 'PAGAMENTO', 1)`); err != nil {
-		log.Fatalf("failed to seed op types: %v", err)
-	}
-
 	code := m.Run()
 
 	if err := pool.Purge(resource); err != nil {
@@ -81,32 +83,12 @@ func TestMain(m *testing.M) {
 }
 
 func runMigrations(db *sql.DB) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS accounts (
-			id BIGSERIAL PRIMARY KEY,
-			document_number TEXT UNIQUE NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		);`,
-		`CREATE TABLE IF NOT EXISTS operation_types (
-			id INT PRIMARY KEY,
-			description TEXT NOT NULL,
-			sign SMALLINT NOT NULL CHECK (sign IN (-1, 1))
-		);`,
-		`CREATE TABLE IF NOT EXISTS transactions (
-			id BIGSERIAL PRIMARY KEY,
-			account_id BIGINT NOT NULL REFERENCES accounts(id),
-			operation_type_id INT NOT NULL REFERENCES operation_types(id),
-			amount_cents BIGINT NOT NULL,
-			event_date TIMESTAMPTZ NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		);`,
-	}
-	for _, q := range queries {
-		if _, err := db.Exec(q); err != nil {
-			return err
-		}
+	runner, err := migration.NewRunner(db, migrations.FS)
+	if err != nil {
+		return err
 	}
-	return nil
+	_, err = runner.Up(context.Background())
+	return err
 }
 
 func SetupRouter() http.Handler {
@@ -114,21 +96,98 @@ func SetupRouter() http.Handler {
 	accountRepo := repository.NewAccountRepository(db)
 	opTypeRepo := repository.NewOperationTypeRepository(db)
 	txRepo := repository.NewTransactionRepository(db)
+	balanceRepo := repository.NewAccountBalanceRepository(db)
 	tm := repository.NewTransactionManager(db)
 
-	createAccountUC := &usecase.CreateAccount{Accounts: accountRepo}
+	createAccountUC := &usecase.CreateAccount{Accounts: accountRepo, Documents: document.Default()}
 	getAccountUC := &usecase.GetAccount{Accounts: accountRepo}
+	listAccountsUC := &usecase.ListAccounts{Accounts: accountRepo, Documents: document.Default()}
+	getBalanceUC := &usecase.GetAccountBalance{Accounts: accountRepo, Balances: balanceRepo}
+	rebuildBalanceUC := &usecase.RebuildAccountBalance{Accounts: accountRepo, Balances: balanceRepo, TransactionManager: tm}
+	updateLimitUC := &usecase.UpdateCreditLimit{Accounts: accountRepo, TransactionManager: tm}
+	statusChangeRepo := repository.NewAccountStatusChangeRepository(db)
+	changeStatusUC := &usecase.ChangeAccountStatus{Accounts: accountRepo, StatusChanges: statusChangeRepo, TransactionManager: tm}
+	statusHistoryUC := &usecase.ListAccountStatusChanges{Accounts: accountRepo, StatusChanges: statusChangeRepo}
 	createTxUC := &usecase.CreateTransaction{
 		Accounts:           accountRepo,
 		OperationTypes:     opTypeRepo,
 		Transactions:       txRepo,
+		Balances:           balanceRepo,
 		TransactionManager: tm,
 	}
 
-	accountHandler := adapterhttp.NewAccountHandler(createAccountUC, getAccountUC)
-	txHandler := adapterhttp.NewTransactionHandler(createTxUC)
+	listTxUC := &usecase.ListTransactions{Accounts: accountRepo, Transactions: txRepo}
+	getTxUC := &usecase.GetTransaction{Accounts: accountRepo, OperationTypes: opTypeRepo, Transactions: txRepo}
+	reverseTxUC := &usecase.ReverseTransaction{
+		Accounts:           accountRepo,
+		OperationTypes:     opTypeRepo,
+		Transactions:       txRepo,
+		Balances:           balanceRepo,
+		TransactionManager: tm,
+	}
+
+	accountHandler := adapterhttp.NewAccountHandler(createAccountUC, getAccountUC, getBalanceUC, rebuildBalanceUC, updateLimitUC, changeStatusUC, statusHistoryUC, listAccountsUC)
+	txHandler := adapterhttp.NewTransactionHandler(createTxUC, listTxUC, getTxUC, reverseTxUC)
+
+	statementRepo := repository.NewStatementRepository(db)
+	statementHandler := adapterhttp.NewStatementHandler(
+		&usecase.ListStatements{Accounts: accountRepo, Statements: statementRepo},
+		&usecase.GetStatement{Statements: statementRepo},
+	)
 
-	return adapterhttp.NewRouter(log, accountHandler, txHandler)
+	opTypeHandler := adapterhttp.NewOperationTypeHandler(
+		&usecase.CreateOperationType{OperationTypes: opTypeRepo},
+		&usecase.ListOperationTypes{OperationTypes: opTypeRepo},
+		&usecase.GetOperationType{OperationTypes: opTypeRepo},
+		&usecase.UpdateOperationType{OperationTypes: opTypeRepo, TransactionManager: tm},
+	)
+
+	authorizationRepo := repository.NewAuthorizationRepository(db)
+	authorizationHandler := adapterhttp.NewAuthorizationHandler(
+		&usecase.AuthorizeTransaction{
+			Accounts:           accountRepo,
+			OperationTypes:     opTypeRepo,
+			Transactions:       txRepo,
+			Balances:           balanceRepo,
+			Authorizations:     authorizationRepo,
+			TransactionManager: tm,
+			Clock:              clock.System{},
+		},
+		&usecase.GetAuthorization{Authorizations: authorizationRepo},
+		&usecase.CaptureAuthorization{
+			Accounts:           accountRepo,
+			OperationTypes:     opTypeRepo,
+			Transactions:       txRepo,
+			Balances:           balanceRepo,
+			Authorizations:     authorizationRepo,
+			TransactionManager: tm,
+			Clock:              clock.System{},
+		},
+		&usecase.VoidAuthorization{
+			Accounts:           accountRepo,
+			OperationTypes:     opTypeRepo,
+			Transactions:       txRepo,
+			Balances:           balanceRepo,
+			Authorizations:     authorizationRepo,
+			TransactionManager: tm,
+			Clock:              clock.System{},
+		},
+	)
+
+	webhookRepo := repository.NewWebhookSubscriptionRepository(db)
+	deliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	webhookHandler := adapterhttp.NewWebhookHandler(
+		&usecase.CreateWebhook{Subscriptions: webhookRepo, Accounts: accountRepo},
+		&usecase.ListWebhooks{Subscriptions: webhookRepo},
+		&usecase.GetWebhook{Subscriptions: webhookRepo},
+		&usecase.DeleteWebhook{Subscriptions: webhookRepo},
+		&usecase.ListWebhookDeliveries{Subscriptions: webhookRepo, Deliveries: deliveryRepo},
+		&usecase.RedeliverWebhook{Subscriptions: webhookRepo, Deliveries: deliveryRepo, Clock: clock.System{}},
+	)
+
+	idempotency := adapterhttp.WithIdempotency(repository.NewIdempotencyRepository(db), time.Hour, time.Minute, log)
+
+	return adapterhttp.NewRouter(log, adapterhttp.NewReadiness(log), accountHandler, txHandler, statementHandler, opTypeHandler, authorizationHandler, webhookHandler, idempotency, 30*time.Second)
 }
 
 func TestE2E_FullFlow(t *testing.T) {
@@ -140,7 +199,7 @@ func TestE2E_FullFlow(t *testing.T) {
 
 	// 1. Create Account
 	t.Run("Create Account", func(t *testing.T) {
-		reqBody := `{"document_number": "E2E_DOC_123"}`
+		reqBody := `{"document_number": "52998224725"}`
 		resp, err := client.Post(server.URL+"/accounts", "application/json", bytes.NewBufferString(reqBody))
 		assert.NoError(t, err)
 		defer resp.Body.Close()
@@ -150,12 +209,23 @@ func TestE2E_FullFlow(t *testing.T) {
 		var body map[string]any
 		_ = json.NewDecoder(resp.Body).Decode(&body)
 		assert.NotEmpty(t, body["account_id"])
+
+		// A formatted variant of the same CPF is the same document
+		dup, err := client.Post(server.URL+"/accounts", "application/json", bytes.NewBufferString(`{"document_number": "529.982.247-25"}`))
+		assert.NoError(t, err)
+		defer dup.Body.Close()
+		assert.Equal(t, http.StatusConflict, dup.StatusCode)
+
+		invalid, err := client.Post(server.URL+"/accounts", "application/json", bytes.NewBufferString(`{"document_number": "529.982.247-26"}`))
+		assert.NoError(t, err)
+		defer invalid.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, invalid.StatusCode)
 	})
 
 	// 2. Create Transaction
 	t.Run("Create Transaction", func(t *testing.T) {
 		var accountID int64
-		err := db.QueryRow("SELECT id FROM accounts WHERE document_number = 'E2E_DOC_123'").Scan(&accountID)
+		err := db.QueryRow("SELECT id FROM accounts WHERE document_number = '52998224725'").Scan(&accountID)
 		assert.NoError(t, err)
 
 		reqBody := fmt.Sprintf(`{"account_id": %d, "operation_type_id": 4, "amount": 50.00}`, accountID)
@@ -165,4 +235,176 @@ func TestE2E_FullFlow(t *testing.T) {
 
 		assert.Equal(t, http.StatusCreated, resp.StatusCode)
 	})
+
+	// 3. Balance reflects the posted transaction
+	t.Run("Get Balance", func(t *testing.T) {
+		var accountID int64
+		err := db.QueryRow("SELECT id FROM accounts WHERE document_number = '52998224725'").Scan(&accountID)
+		assert.NoError(t, err)
+
+		resp, err := client.Get(fmt.Sprintf("%s/accounts/%d/balance", server.URL, accountID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		assert.EqualValues(t, 5000, body["posted_cents"])
+		assert.EqualValues(t, 5000, body["available_cents"])
+	})
+
+	// 4. Debits beyond the available credit limit are rejected
+	t.Run("Insufficient Credit Limit", func(t *testing.T) {
+		var accountID int64
+		err := db.QueryRow("SELECT id FROM accounts WHERE document_number = '52998224725'").Scan(&accountID)
+		assert.NoError(t, err)
+
+		// The default limit is 1000.00, plus the 50.00 credit posted above.
+		reqBody := fmt.Sprintf(`{"account_id": %d, "operation_type_id": 1, "amount": 1050.01}`, accountID)
+		resp, err := client.Post(server.URL+"/transactions", "application/json", bytes.NewBufferString(reqBody))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	// 5. History lists the posted transaction
+	t.Run("List Transactions", func(t *testing.T) {
+		var accountID int64
+		err := db.QueryRow("SELECT id FROM accounts WHERE document_number = '52998224725'").Scan(&accountID)
+		assert.NoError(t, err)
+
+		resp, err := client.Get(fmt.Sprintf("%s/accounts/%d/transactions?operation_type_id=4&limit=10", server.URL, accountID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body struct {
+			Data       []map[string]any `json:"data"`
+			NextCursor string           `json:"next_cursor"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		assert.Len(t, body.Data, 1)
+		assert.Empty(t, body.NextCursor)
+	})
+
+	// 6. A single transaction can be fetched with its details
+	t.Run("Get Transaction", func(t *testing.T) {
+		var txID int64
+		err := db.QueryRow("SELECT t.id FROM transactions t JOIN accounts a ON a.id = t.account_id WHERE a.document_number = '52998224725'").Scan(&txID)
+		assert.NoError(t, err)
+
+		resp, err := client.Get(fmt.Sprintf("%s/transactions/%d", server.URL, txID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var body map[string]any
+		_ = json.NewDecoder(resp.Body).Decode(&body)
+		assert.Equal(t, "CREDIT VOUCHER", body["operation_type_description"])
+		assert.EqualValues(t, 50, body["amount"])
+
+		missing, err := client.Get(server.URL + "/transactions/999999")
+		assert.NoError(t, err)
+		defer missing.Body.Close()
+		assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+	})
+
+	// 7. Reversals are partial and capped at the original amount
+	t.Run("Reverse Transaction", func(t *testing.T) {
+		var txID int64
+		err := db.QueryRow("SELECT t.id FROM transactions t JOIN accounts a ON a.id = t.account_id WHERE a.document_number = '52998224725' AND t.operation_type_id = 4").Scan(&txID)
+		assert.NoError(t, err)
+
+		reverse := func(body string) int {
+			resp, err := client.Post(fmt.Sprintf("%s/transactions/%d/reversal", server.URL, txID), "application/json", bytes.NewBufferString(body))
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		assert.Equal(t, http.StatusCreated, reverse(`{"amount": 20.00}`))
+		assert.Equal(t, http.StatusUnprocessableEntity, reverse(`{"amount": 40.00}`))
+		assert.Equal(t, http.StatusCreated, reverse(``))
+		assert.Equal(t, http.StatusUnprocessableEntity, reverse(``))
+	})
+
+	// 8. Retrying with the same Idempotency-Key replays the first response
+	t.Run("Idempotent Retry", func(t *testing.T) {
+		post := func(body string) (*http.Response, map[string]any) {
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/accounts", bytes.NewBufferString(body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Idempotency-Key", "e2e-create-account")
+
+			resp, err := client.Do(req)
+			assert.NoError(t, err)
+			defer resp.Body.Close()
+
+			var decoded map[string]any
+			_ = json.NewDecoder(resp.Body).Decode(&decoded)
+			return resp, decoded
+		}
+
+		first, firstBody := post(`{"document_number": "11.222.333/0001-81"}`)
+		assert.Equal(t, http.StatusCreated, first.StatusCode)
+
+		retry, retryBody := post(`{"document_number": "11.222.333/0001-81"}`)
+		assert.Equal(t, http.StatusCreated, retry.StatusCode)
+		assert.Equal(t, "true", retry.Header.Get("Idempotent-Replayed"))
+		assert.Equal(t, firstBody["account_id"], retryBody["account_id"])
+
+		mismatch, _ := post(`{"document_number": "123.456.789-09"}`)
+		assert.Equal(t, http.StatusUnprocessableEntity, mismatch.StatusCode)
+	})
+
+	// 9. Closing a cycle produces a statement of the cycle's transactions
+	t.Run("Close Statements", func(t *testing.T) {
+		var accountID, sum int64
+		err := db.QueryRow("SELECT a.id, COALESCE(SUM(t.amount_cents), 0) FROM accounts a JOIN transactions t ON t.account_id = a.id WHERE a.document_number = '52998224725' AND t.installments = 0 GROUP BY a.id").Scan(&accountID, &sum)
+		assert.NoError(t, err)
+
+		tm := repository.NewTransactionManager(db)
+		closeUC := usecase.CloseStatements{
+			Accounts:           repository.NewAccountRepository(db),
+			Transactions:       repository.NewTransactionRepository(db),
+			Statements:         repository.NewStatementRepository(db),
+			TransactionManager: tm,
+			// Just past the end of the account's first cycle.
+			Clock: fixedClock(domain.NextClosing(domain.DefaultClosingDay, time.Now()).Add(time.Hour)),
+		}
+		closed, err := closeUC.Execute(context.Background())
+		assert.NoError(t, err)
+		assert.Positive(t, closed)
+
+		// Running it again within the same cycle closes nothing.
+		closed, err = closeUC.Execute(context.Background())
+		assert.NoError(t, err)
+		assert.Zero(t, closed)
+
+		resp, err := client.Get(fmt.Sprintf("%s/accounts/%d/statements", server.URL, accountID))
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var list adapterhttp.StatementListResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+		if assert.Len(t, list.Data, 1) {
+			assert.Equal(t, float64(sum)/100, list.Data[0].ClosingBalance)
+
+			one, err := client.Get(fmt.Sprintf("%s/statements/%d", server.URL, list.Data[0].ID))
+			assert.NoError(t, err)
+			defer one.Body.Close()
+			assert.Equal(t, http.StatusOK, one.StatusCode)
+		}
+	})
+}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
 }
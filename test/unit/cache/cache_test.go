@@ -0,0 +1,256 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/cache"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+// countingRepo serves operation types from a map and counts reads.
+type countingRepo struct {
+	mu    sync.Mutex
+	types map[int]domain.OperationType
+	finds int
+	lists int
+}
+
+func newCountingRepo() *countingRepo {
+	return &countingRepo{types: map[int]domain.OperationType{
+		1: {ID: 1, Description: "NORMAL PURCHASE", Sign: -1, Active: true},
+		4: {ID: 4, Description: "CREDIT VOUCHER", Sign: 1, Active: true},
+	}}
+}
+
+func (r *countingRepo) FindByID(ctx context.Context, id int) (domain.OperationType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finds++
+	ot, ok := r.types[id]
+	if !ok {
+		return domain.OperationType{}, domain.ErrOperationTypeNotFound
+	}
+	return ot, nil
+}
+
+func (r *countingRepo) FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *countingRepo) List(ctx context.Context) ([]domain.OperationType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lists++
+	return []domain.OperationType{r.types[1], r.types[4]}, nil
+}
+
+func (r *countingRepo) Create(ctx context.Context, ot domain.OperationType) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[ot.ID] = ot
+	return ot.ID, nil
+}
+
+func (r *countingRepo) Update(ctx context.Context, ot domain.OperationType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.types[ot.ID] = ot
+	return nil
+}
+
+// lockingTM runs one transaction at a time, standing in for the row lock
+// FindByIDForUpdate takes.
+type lockingTM struct {
+	mu sync.Mutex
+}
+
+func (tm *lockingTM) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	return fn(ctx)
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func counter(t *testing.T, name string) float64 {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range families {
+		if f.GetName() != name {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "cache" && l.GetValue() == "operation_types" {
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestOperationTypes_TTL(t *testing.T) {
+	ctx := context.Background()
+	repo := newCountingRepo()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := cache.NewOperationTypes(repo, time.Minute, clock)
+
+	hits, misses := counter(t, "cache_hits_total"), counter(t, "cache_misses_total")
+
+	for range 3 {
+		ot, err := c.FindByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ot.Description != "NORMAL PURCHASE" {
+			t.Errorf("unexpected operation type %+v", ot)
+		}
+	}
+	if repo.finds != 1 {
+		t.Errorf("expected 1 read from the source, got %d", repo.finds)
+	}
+	if got := counter(t, "cache_hits_total") - hits; got != 2 {
+		t.Errorf("expected 2 hits, got %v", got)
+	}
+	if got := counter(t, "cache_misses_total") - misses; got != 1 {
+		t.Errorf("expected 1 miss, got %v", got)
+	}
+
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := c.FindByID(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.finds != 2 {
+		t.Errorf("expected an expired entry to be read again, got %d reads", repo.finds)
+	}
+
+	for range 2 {
+		if _, err := c.FindByID(ctx, 99); !errors.Is(err, domain.ErrOperationTypeNotFound) {
+			t.Fatalf("expected ErrOperationTypeNotFound, got %v", err)
+		}
+	}
+	if repo.finds != 4 {
+		t.Errorf("expected unknown ids not to be cached, got %d reads", repo.finds)
+	}
+}
+
+func TestOperationTypes_Warm(t *testing.T) {
+	ctx := context.Background()
+	repo := newCountingRepo()
+	c := cache.NewOperationTypes(repo, time.Minute, &fakeClock{now: time.Now()})
+
+	if err := c.Warm(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []int{1, 4} {
+		if _, err := c.FindByID(ctx, id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if repo.finds != 0 {
+		t.Errorf("expected warmed types to be served from memory, got %d reads", repo.finds)
+	}
+}
+
+func TestOperationTypes_Invalidation(t *testing.T) {
+	ctx := context.Background()
+	repo := newCountingRepo()
+	c := cache.NewOperationTypes(repo, time.Hour, &fakeClock{now: time.Now()})
+	if err := c.Warm(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("update through the cache", func(t *testing.T) {
+		ot, _ := c.FindByID(ctx, 1)
+		ot.Active = false
+		if err := c.Update(ctx, ot); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.FindByID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Active {
+			t.Error("expected the update to be visible")
+		}
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		_ = repo.Update(ctx, domain.OperationType{ID: 4, Description: "PAYMENT", Sign: 1, Active: true})
+
+		if got, _ := c.FindByID(ctx, 4); got.Description != "CREDIT VOUCHER" {
+			t.Fatalf("expected the cached type before invalidation, got %+v", got)
+		}
+		c.Invalidate()
+		if got, _ := c.FindByID(ctx, 4); got.Description != "PAYMENT" {
+			t.Errorf("expected the new description after invalidation, got %+v", got)
+		}
+	})
+}
+
+func TestUpdateOperationType_Interleaved(t *testing.T) {
+	ctx := context.Background()
+	repo := newCountingRepo()
+	tm := &lockingTM{}
+
+	// Two replicas, each with its own cache warmed before either update.
+	replicas := make([]*cache.OperationTypes, 2)
+	updates := make([]usecase.UpdateOperationType, 2)
+	for i := range replicas {
+		replicas[i] = cache.NewOperationTypes(repo, time.Hour, &fakeClock{now: time.Now()})
+		if err := replicas[i].Warm(ctx); err != nil {
+			t.Fatal(err)
+		}
+		updates[i] = usecase.UpdateOperationType{OperationTypes: repo, TransactionManager: tm, Cache: replicas[i]}
+	}
+
+	no := false
+	description := "CASH PURCHASE"
+	inputs := []usecase.UpdateOperationTypeInput{{Active: &no}, {Description: &description}}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(updates))
+	for i := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = updates[i].Execute(ctx, 1, inputs[i])
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stored, _ := repo.FindByID(ctx, 1)
+	if stored.Active || stored.Description != description {
+		t.Fatalf("expected both updates to be kept, got %+v", stored)
+	}
+	for i, c := range replicas {
+		got, err := c.FindByID(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != stored {
+			t.Errorf("expected replica %d to see the committed type, got %+v", i, got)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package config_test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/config"
+)
+
+func writeFile(t *testing.T, name, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+port: "9000"
+grpc_port: 9001
+request_timeout: 10s
+db_max_open_conns: 50
+traces_sampler: traceidratio
+traces_sampler_arg: 0.25
+`)
+	t.Setenv(config.ConfigFileEnv, path)
+	t.Setenv("GRPC_PORT", "9002")
+	t.Setenv("REQUEST_TIMEOUT", "20s")
+	t.Setenv("MIGRATE_ON_START", "")
+
+	cfg, args, err := config.Load([]string{"-request-timeout", "40s", "-migrate-on-start", "migrate", "up"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != "9000" || cfg.DBMaxOpenConns != 50 || cfg.TracesSamplerArg != 0.25 {
+		t.Errorf("expected the file settings, got %+v", cfg)
+	}
+	if cfg.GRPCPort != "9002" {
+		t.Errorf("expected the env to override the file, got %q", cfg.GRPCPort)
+	}
+	if cfg.RequestTimeout != 40*time.Second || !cfg.MigrateOnStart {
+		t.Errorf("expected the flags to override the env, got %s %v", cfg.RequestTimeout, cfg.MigrateOnStart)
+	}
+	if cfg.DBMaxIdleConns != 25 || cfg.ShutdownTimeout != 15*time.Second {
+		t.Errorf("expected the defaults for unset settings, got %+v", cfg)
+	}
+	if strings.Join(args, " ") != "migrate up" {
+		t.Errorf("expected the remaining args, got %q", args)
+	}
+
+	sources := make(map[string]string)
+	for _, s := range cfg.Settings() {
+		sources[s.Key] = s.Source
+	}
+	want := map[string]string{
+		"port":              config.SourceFile,
+		"grpc_port":         config.SourceEnv,
+		"request_timeout":   config.SourceFlag,
+		"migrate_on_start":  config.SourceFlag,
+		"db_max_idle_conns": config.SourceDefault,
+	}
+	for key, source := range want {
+		if sources[key] != source {
+			t.Errorf("expected %s from %s, got %s", key, source, sources[key])
+		}
+	}
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	path := writeFile(t, "config.json", `{"db_max_open_conns": 8, "db_max_idle_conns": 4, "webhook_timeout": "3s"}`)
+	t.Setenv(config.ConfigFileEnv, "")
+
+	cfg, _, err := config.Load([]string{"-config", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DBMaxOpenConns != 8 || cfg.DBMaxIdleConns != 4 || cfg.WebhookTimeout != 3*time.Second {
+		t.Errorf("expected the file settings, got %+v", cfg)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, "")
+
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{
+			name: "unknown file key",
+			file: "request_timout: 5s\n",
+			want: []string{`unknown key "request_timout"`},
+		},
+		{
+			name: "nested file value",
+			file: "port:\n  http: 8080\n",
+			want: []string{"port must be a single value"},
+		},
+		{
+			name: "bad type in file",
+			file: "db_max_open_conns: many\n",
+			want: []string{`db_max_open_conns: invalid integer "many"`},
+		},
+		{
+			name: "bad type in env",
+			env:  map[string]string{"SHUTDOWN_TIMEOUT": "15"},
+			want: []string{`env SHUTDOWN_TIMEOUT: invalid duration "15"`},
+		},
+		{
+			name: "bad type in flag",
+			args: []string{"-traces-sampler-arg", "half"},
+			want: []string{`flag -traces-sampler-arg: invalid number "half"`},
+		},
+		{
+			name: "out of range",
+			args: []string{"-db-max-open-conns", "0", "-request-timeout", "0s", "-traces-sampler-arg", "1.5", "-port", "70000"},
+			want: []string{"db_max_open_conns", "request_timeout", "traces_sampler_arg", `invalid port "70000"`},
+		},
+		{
+			name: "unknown sampler",
+			env:  map[string]string{"OTEL_TRACES_SAMPLER": "sometimes"},
+			want: []string{`unknown sampler "sometimes"`},
+		},
+		{
+			name: "bad fx rates",
+			env:  map[string]string{"FX_RATES": "USD:BRL=5.0"},
+			want: []string{`fx_rates: invalid rate "USD:BRL=5.0"`},
+		},
+		{
+			name: "webhook publisher without url",
+			args: []string{"-event-publisher", "webhook"},
+			want: []string{"event_webhook_url: is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, args...)
+			}
+
+			_, _, err := config.Load(args)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in %q", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad_Help(t *testing.T) {
+	_, _, err := config.Load([]string{"-h"})
+	if !errors.Is(err, flag.ErrHelp) {
+		t.Errorf("expected flag.ErrHelp, got %v", err)
+	}
+}
+
+func TestConfig_Redaction(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("DB_DSN", "postgres://pismo:s3cret@db:5432/pismo?sslmode=disable")
+	t.Setenv("EVENT_WEBHOOK_URL", "https://hooks.example.com/events?token=abc123")
+
+	cfg, _, err := config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dump bytes.Buffer
+	if err := cfg.Dump(&dump); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, out := range map[string]string{"String": cfg.String(), "Dump": dump.String()} {
+		if strings.Contains(out, "s3cret") || strings.Contains(out, "abc123") {
+			t.Errorf("%s leaked a secret: %s", name, out)
+		}
+		if !strings.Contains(out, "postgres://pismo:REDACTED@db:5432/pismo") || !strings.Contains(out, "token=REDACTED") {
+			t.Errorf("%s should keep the rest of the value: %s", name, out)
+		}
+	}
+
+	if !strings.Contains(dump.String(), "db_dsn: postgres://pismo:REDACTED@db:5432/pismo?sslmode=disable # env\n") {
+		t.Errorf("expected the dsn with its source, got %s", dump.String())
+	}
+
+	t.Setenv("DB_DSN", "host=db user=pismo password='a b' dbname=pismo")
+	cfg, _, err = config.Load(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := cfg.String(); strings.Contains(out, "a b") || !strings.Contains(out, "password=REDACTED dbname=pismo") {
+		t.Errorf("expected the keyword password redacted, got %s", out)
+	}
+}
+
+func TestConfig_DumpRoundTrip(t *testing.T) {
+	t.Setenv(config.ConfigFileEnv, "")
+	t.Setenv("REQUEST_TIMEOUT", "12s")
+
+	cfg, _, err := config.Load([]string{"-traces-sampler", "parentbased_traceidratio", "-traces-sampler-arg", "0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dump bytes.Buffer
+	if err := cfg.Dump(&dump); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("REQUEST_TIMEOUT", "")
+	reloaded, _, err := config.Load([]string{"-config", writeFile(t, "dump.yaml", dump.String())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.RequestTimeout != 12*time.Second || reloaded.TracesSampler != "parentbased_traceidratio" || reloaded.TracesSamplerArg != 0.1 {
+		t.Errorf("expected the dump to load back, got %+v", reloaded)
+	}
+}
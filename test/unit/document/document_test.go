@@ -0,0 +1,86 @@
+package document_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/document"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+func TestRegistry_Brazil(t *testing.T) {
+	tests := []struct {
+		name    string
+		number  string
+		want    string
+		wantErr error
+	}{
+		{"CPF digits", "52998224725", "52998224725", nil},
+		{"CPF formatted", "529.982.247-25", "52998224725", nil},
+		{"CPF with spaces", " 529 982 247 25 ", "52998224725", nil},
+		{"CPF wrong first check digit", "52998224715", "", domain.ErrInvalidDocumentNumber},
+		{"CPF wrong second check digit", "52998224726", "", domain.ErrInvalidDocumentNumber},
+		{"CPF repeated digits", "111.111.111-11", "", domain.ErrInvalidDocumentNumber},
+		{"CPF with letters", "5299822472A", "", domain.ErrInvalidDocumentNumber},
+		{"CNPJ digits", "11222333000181", "11222333000181", nil},
+		{"CNPJ formatted", "11.222.333/0001-81", "11222333000181", nil},
+		{"CNPJ alphanumeric", "12.ABC.345/01DE-35", "12ABC34501DE35", nil},
+		{"CNPJ alphanumeric lower case", "12abc34501de35", "12ABC34501DE35", nil},
+		{"CNPJ wrong check digit", "11.222.333/0001-82", "", domain.ErrInvalidDocumentNumber},
+		{"CNPJ letter in check digits", "12ABC34501DE3A", "", domain.ErrInvalidDocumentNumber},
+		{"CNPJ repeated digits", "00000000000000", "", domain.ErrInvalidDocumentNumber},
+		{"unexpected characters", "529_982_247_25", "", domain.ErrInvalidDocumentNumber},
+		{"wrong length", "1234567890", "", domain.ErrInvalidDocumentNumber},
+		{"empty", "", "", domain.ErrInvalidDocumentNumber},
+	}
+
+	registry := document.Default()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := registry.Normalize("BR", tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// digitsOnly accepts any run of exactly n digits.
+type digitsOnly int
+
+func (n digitsOnly) Normalize(number string) (string, bool) {
+	if len(number) != int(n) {
+		return "", false
+	}
+	for _, c := range number {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	return number, true
+}
+
+func TestRegistry_Register(t *testing.T) {
+	registry := document.Default()
+
+	if _, err := registry.Normalize("AR", "20123456"); !errors.Is(err, domain.ErrInvalidDocumentNumber) {
+		t.Fatalf("expected unknown country to be rejected, got %v", err)
+	}
+
+	registry.Register("ar", digitsOnly(8))
+
+	got, err := registry.Normalize("AR", "20123456")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "20123456" {
+		t.Errorf("expected 20123456, got %s", got)
+	}
+
+	if _, err := registry.Normalize("br", "529.982.247-25"); err != nil {
+		t.Errorf("expected country codes to be case-insensitive, got %v", err)
+	}
+}
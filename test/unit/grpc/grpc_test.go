@@ -0,0 +1,402 @@
+package grpc_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	pismov1 "github.com/nicolasmmb/pismo-challenge/api/pismo/v1"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/document"
+	adaptergrpc "github.com/nicolasmmb/pismo-challenge/internal/adapter/grpc"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
+	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
+)
+
+// FakeAccountRepo keeps accounts in memory. Methods the service does not call
+// are left to the embedded interface.
+type FakeAccountRepo struct {
+	port.AccountRepository
+	mu       sync.Mutex
+	accounts map[int64]domain.Account
+	// deadline is the deadline the last FindByID was called with.
+	deadline time.Time
+}
+
+func (r *FakeAccountRepo) Create(ctx context.Context, acc domain.Account) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.accounts {
+		if existing.DocumentNumber == acc.DocumentNumber {
+			return 0, domain.ErrDocumentExists
+		}
+	}
+	acc.ID = int64(len(r.accounts) + 1)
+	r.accounts[acc.ID] = acc
+	return acc.ID, nil
+}
+
+func (r *FakeAccountRepo) FindByID(ctx context.Context, id int64) (domain.Account, error) {
+	if id == 666 {
+		panic("corrupt account")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.deadline, _ = ctx.Deadline()
+	acc, ok := r.accounts[id]
+	if !ok {
+		return domain.Account{}, domain.ErrAccountNotFound
+	}
+	return acc, nil
+}
+
+// FindByIDForUpdate refuses account 777 as if the debit went past its limit.
+func (r *FakeAccountRepo) FindByIDForUpdate(ctx context.Context, id int64) (domain.Account, error) {
+	if id == 777 {
+		return domain.Account{}, domain.ErrInsufficientFunds
+	}
+	return r.FindByID(ctx, id)
+}
+
+// FakeTransactionManager runs fn without a transaction.
+type FakeTransactionManager struct{}
+
+func (FakeTransactionManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// FakeTransactionRepo serves a fixed set of transactions.
+type FakeTransactionRepo struct {
+	port.TransactionRepository
+	txs []domain.Transaction
+}
+
+func (r *FakeTransactionRepo) FindByID(ctx context.Context, id int64) (domain.Transaction, error) {
+	for _, tx := range r.txs {
+		if tx.ID == id {
+			return tx, nil
+		}
+	}
+	return domain.Transaction{}, domain.ErrTransactionNotFound
+}
+
+func (r *FakeTransactionRepo) ListByAccount(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	var out []domain.Transaction
+	for _, tx := range r.txs {
+		if tx.AccountID == filter.AccountID {
+			out = append(out, tx)
+		}
+	}
+	return out[:min(len(out), filter.Limit)], nil
+}
+
+// FakeOutbox holds a TransactionPosted event for each transaction it is
+// given.
+type FakeOutbox struct {
+	port.OutboxRepository
+	mu     sync.Mutex
+	events []domain.Event
+	// watching is signalled when a watch reads where to start from.
+	watching chan struct{}
+}
+
+func (o *FakeOutbox) post(t *testing.T, tx domain.Transaction) {
+	t.Helper()
+	event, err := domain.NewTransactionPosted(tx, tx.EventDate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	event.ID = int64(len(o.events) + 1)
+	o.events = append(o.events, event)
+}
+
+func (o *FakeOutbox) ListByAccount(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var out []domain.Event
+	for _, e := range o.events {
+		if e.AccountID == accountID && e.Type == eventType && e.ID > afterID && len(out) < limit {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+func (o *FakeOutbox) LatestID(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	defer func() { o.watching <- struct{}{} }()
+	var latest int64
+	for _, e := range o.events {
+		if e.AccountID == accountID && e.Type == eventType {
+			latest = e.ID
+		}
+	}
+	return latest, nil
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Info(msg string, fields map[string]any)  {}
+func (nopLogger) Error(msg string, fields map[string]any) {}
+
+type fixture struct {
+	client   pismov1.LedgerServiceClient
+	accounts *FakeAccountRepo
+	txs      *FakeTransactionRepo
+	outbox   *FakeOutbox
+}
+
+// setup serves the ledger service over an in-memory listener.
+func setup(t *testing.T) fixture {
+	t.Helper()
+	f := fixture{
+		accounts: &FakeAccountRepo{accounts: make(map[int64]domain.Account)},
+		txs:      &FakeTransactionRepo{},
+		outbox:   &FakeOutbox{watching: make(chan struct{}, 1)},
+	}
+
+	ledger := adaptergrpc.NewLedgerServer(
+		&usecase.CreateAccount{Accounts: f.accounts, Documents: document.Default()},
+		&usecase.GetAccount{Accounts: f.accounts},
+		&usecase.CreateTransaction{Accounts: f.accounts, Transactions: f.txs, TransactionManager: FakeTransactionManager{}},
+		&usecase.ListTransactions{Accounts: f.accounts, Transactions: f.txs},
+		&usecase.WatchTransactions{Accounts: f.accounts, Transactions: f.txs, Outbox: f.outbox, PollInterval: time.Millisecond},
+	)
+	srv := adaptergrpc.NewServer(nopLogger{}, ledger, health.NewServer(), 5*time.Second)
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	f.client = pismov1.NewLedgerServiceClient(conn)
+	return f
+}
+
+// reason returns the code carried by the ErrorInfo detail of err.
+func reason(t *testing.T, err error) (codes.Code, string) {
+	t.Helper()
+	st := status.Convert(err)
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			if info.GetDomain() != adaptergrpc.ErrorDomain {
+				t.Errorf("unexpected error domain %q", info.GetDomain())
+			}
+			return st.Code(), info.GetReason()
+		}
+	}
+	return st.Code(), ""
+}
+
+func TestLedgerService_Accounts(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	created, err := f.client.CreateAccount(ctx, &pismov1.CreateAccountRequest{
+		DocumentNumber:       "52998224725",
+		AvailableCreditLimit: "1000.50",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.GetAccountId() == 0 || created.GetAvailableCreditLimitCents() != 100050 || created.GetCurrency() != domain.DefaultCurrency || created.GetStatus() != "ACTIVE" {
+		t.Errorf("unexpected account %+v", created)
+	}
+
+	got, err := f.client.GetAccount(ctx, &pismov1.GetAccountRequest{AccountId: created.GetAccountId()})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.GetDocumentNumber() != "52998224725" {
+		t.Errorf("unexpected account %+v", got)
+	}
+	if left := time.Until(f.accounts.deadline); left <= 0 || left > 5*time.Second {
+		t.Errorf("expected the call to be bounded by the request timeout, got %v left", left)
+	}
+
+	tests := []struct {
+		name       string
+		call       func() error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{
+			"unknown account",
+			func() error {
+				_, err := f.client.GetAccount(ctx, &pismov1.GetAccountRequest{AccountId: 99})
+				return err
+			},
+			codes.NotFound, "account_not_found",
+		},
+		{
+			"duplicate document",
+			func() error {
+				_, err := f.client.CreateAccount(ctx, &pismov1.CreateAccountRequest{DocumentNumber: "52998224725"})
+				return err
+			},
+			codes.AlreadyExists, "document_exists",
+		},
+		{
+			"bad amount",
+			func() error {
+				_, err := f.client.CreateTransaction(ctx, &pismov1.CreateTransactionRequest{AccountId: 1, OperationTypeId: 1, Amount: "12,5"})
+				return err
+			},
+			codes.InvalidArgument, "invalid_amount",
+		},
+		{
+			"insufficient funds",
+			func() error {
+				_, err := f.client.CreateTransaction(ctx, &pismov1.CreateTransactionRequest{AccountId: 777, OperationTypeId: 1, Amount: "5000"})
+				return err
+			},
+			codes.FailedPrecondition, "insufficient_funds",
+		},
+		{
+			"panic",
+			func() error {
+				_, err := f.client.GetAccount(ctx, &pismov1.GetAccountRequest{AccountId: 666})
+				return err
+			},
+			codes.Internal, "internal_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, reason := reason(t, tt.call())
+			if code != tt.wantCode || reason != tt.wantReason {
+				t.Errorf("expected %v %q, got %v %q", tt.wantCode, tt.wantReason, code, reason)
+			}
+		})
+	}
+
+	t.Run("field violation", func(t *testing.T) {
+		_, err := f.client.CreateAccount(ctx, &pismov1.CreateAccountRequest{DocumentNumber: "11144477735", ClosingDay: 40})
+		var field string
+		for _, d := range status.Convert(err).Details() {
+			if br, ok := d.(*errdetails.BadRequest); ok && len(br.GetFieldViolations()) == 1 {
+				field = br.GetFieldViolations()[0].GetField()
+			}
+		}
+		if field != "closing_day" {
+			t.Errorf("expected a closing_day violation, got %v", err)
+		}
+	})
+}
+
+func TestLedgerService_ListTransactions(t *testing.T) {
+	f := setup(t)
+	ctx := context.Background()
+
+	acc, err := f.client.CreateAccount(ctx, &pismov1.CreateAccountRequest{DocumentNumber: "52998224725"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+	f.txs.txs = []domain.Transaction{
+		{ID: 3, AccountID: acc.GetAccountId(), OperationTypeID: 4, AmountCents: 5000, Currency: "BRL", EventDate: now},
+		{ID: 2, AccountID: acc.GetAccountId(), OperationTypeID: 1, AmountCents: -1000, Currency: "BRL", EventDate: now.Add(-time.Hour)},
+		{ID: 1, AccountID: acc.GetAccountId(), OperationTypeID: 1, AmountCents: -2000, Currency: "BRL", EventDate: now.Add(-2 * time.Hour)},
+	}
+
+	page, err := f.client.ListTransactions(ctx, &pismov1.ListTransactionsRequest{AccountId: acc.GetAccountId(), Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(page.GetTransactions()) != 2 || page.GetNextCursor() == "" {
+		t.Fatalf("expected a full page and a cursor, got %+v", page)
+	}
+	first := page.GetTransactions()[0]
+	if first.GetTransactionId() != 3 || first.GetAmountCents() != 5000 || !first.GetEventDate().AsTime().Equal(now) {
+		t.Errorf("unexpected transaction %+v", first)
+	}
+
+	_, err = f.client.ListTransactions(ctx, &pismov1.ListTransactionsRequest{AccountId: acc.GetAccountId(), Cursor: "nope"})
+	if code, reason := reason(t, err); code != codes.InvalidArgument || reason != "invalid_cursor" {
+		t.Errorf("expected invalid_cursor, got %v %q", code, reason)
+	}
+}
+
+func TestLedgerService_WatchTransactions(t *testing.T) {
+	f := setup(t)
+
+	acc, err := f.client.CreateAccount(context.Background(), &pismov1.CreateAccountRequest{DocumentNumber: "52998224725"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountID := acc.GetAccountId()
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+
+	// Posted before the watch starts: skipped unless resumed from.
+	f.txs.txs = []domain.Transaction{
+		{ID: 1, AccountID: accountID, AmountCents: -1000, Currency: "BRL", EventDate: now},
+		{ID: 2, AccountID: accountID, AmountCents: -2000, Currency: "BRL", EventDate: now},
+		{ID: 3, AccountID: accountID + 1, AmountCents: -3000, Currency: "BRL", EventDate: now},
+	}
+	f.outbox.post(t, f.txs.txs[0])
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := f.client.WatchTransactions(ctx, &pismov1.WatchTransactionsRequest{AccountId: accountID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-f.outbox.watching
+	f.outbox.post(t, f.txs.txs[2])
+	f.outbox.post(t, f.txs.txs[1])
+
+	msg, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if msg.GetEventId() != 3 || msg.GetTransaction().GetTransactionId() != 2 || msg.GetTransaction().GetAmountCents() != -2000 {
+		t.Errorf("unexpected message %+v", msg)
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		stream, err := f.client.WatchTransactions(ctx, &pismov1.WatchTransactionsRequest{AccountId: accountID, AfterEventId: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if msg.GetEventId() != 3 {
+			t.Errorf("expected to resume with event 3, got %+v", msg)
+		}
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		stream, err := f.client.WatchTransactions(ctx, &pismov1.WatchTransactionsRequest{AccountId: 99})
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = stream.Recv()
+		if code, reason := reason(t, err); code != codes.NotFound || reason != "account_not_found" {
+			t.Errorf("expected account_not_found, got %v %q", code, reason)
+		}
+	})
+}
@@ -4,11 +4,22 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/clock"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/document"
 	adapterhttp "github.com/nicolasmmb/pismo-challenge/internal/adapter/http"
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
@@ -28,9 +39,21 @@ func NewFakeAccountRepo() *FakeAccountRepo {
 }
 
 func (r *FakeAccountRepo) Create(ctx context.Context, account domain.Account) (int64, error) {
+	for _, existing := range r.accounts {
+		if existing.DocumentNumber == account.DocumentNumber {
+			return 0, domain.ErrDocumentExists
+		}
+	}
+
 	id := r.nextID
 	r.nextID++
 	account.ID = id
+	if account.Currency == "" {
+		account.Currency = domain.DefaultCurrency
+	}
+	if account.Status == "" {
+		account.Status = domain.AccountStatusActive
+	}
 	r.accounts[id] = account
 	return id, nil
 }
@@ -47,20 +70,164 @@ func (r *FakeAccountRepo) FindByIDForUpdate(ctx context.Context, id int64) (doma
 	return r.FindByID(ctx, id)
 }
 
+func (r *FakeAccountRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]domain.Account, error) {
+	var out []domain.Account
+	for id := afterID + 1; id < r.nextID && len(out) < limit; id++ {
+		if acc, ok := r.accounts[id]; ok {
+			out = append(out, acc)
+		}
+	}
+	return out, nil
+}
+
+func (r *FakeAccountRepo) List(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error) {
+	var out []domain.Account
+	for _, acc := range r.accounts {
+		if filter.DocumentNumber != "" && acc.DocumentNumber != filter.DocumentNumber {
+			continue
+		}
+		if filter.Status != "" && acc.Status != filter.Status {
+			continue
+		}
+		if !filter.From.IsZero() && acc.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !acc.CreatedAt.Before(filter.To) {
+			continue
+		}
+		out = append(out, acc)
+	}
+
+	before := func(a, b domain.Account) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID < b.ID
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if filter.Ascending {
+			return before(out[i], out[j])
+		}
+		return before(out[j], out[i])
+	})
+
+	if filter.After != nil {
+		cursor := domain.Account{ID: filter.After.ID, CreatedAt: filter.After.Time}
+		for len(out) > 0 && (filter.Ascending && !before(cursor, out[0]) || !filter.Ascending && !before(out[0], cursor)) {
+			out = out[1:]
+		}
+	}
+	if len(out) > filter.Limit {
+		out = out[:filter.Limit]
+	}
+	return out, nil
+}
+
+func (r *FakeAccountRepo) UpdateAvailableCreditLimit(ctx context.Context, id int64, limitCents int64) error {
+	acc, ok := r.accounts[id]
+	if !ok {
+		return domain.ErrAccountNotFound
+	}
+	acc.AvailableCreditLimitCents = limitCents
+	r.accounts[id] = acc
+	return nil
+}
+
+func (r *FakeAccountRepo) UpdateStatus(ctx context.Context, id int64, status domain.AccountStatus) error {
+	acc, ok := r.accounts[id]
+	if !ok {
+		return domain.ErrAccountNotFound
+	}
+	acc.Status = status
+	r.accounts[id] = acc
+	return nil
+}
+
+// FakeStatusChangeRepo implements port.AccountStatusChangeRepository
+type FakeStatusChangeRepo struct {
+	changes []domain.AccountStatusChange
+}
+
+func (r *FakeStatusChangeRepo) Create(ctx context.Context, change domain.AccountStatusChange) (int64, error) {
+	change.ID = int64(len(r.changes) + 1)
+	r.changes = append(r.changes, change)
+	return change.ID, nil
+}
+
+func (r *FakeStatusChangeRepo) ListByAccount(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error) {
+	var out []domain.AccountStatusChange
+	for _, c := range r.changes {
+		if c.AccountID == accountID {
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// FakeBalanceRepo implements port.AccountBalanceRepository
+type FakeBalanceRepo struct {
+	balances map[int64]domain.AccountBalance
+}
+
+func NewFakeBalanceRepo() *FakeBalanceRepo {
+	return &FakeBalanceRepo{balances: make(map[int64]domain.AccountBalance)}
+}
+
+func (r *FakeBalanceRepo) FindByAccountID(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	b, ok := r.balances[accountID]
+	if !ok {
+		return domain.AccountBalance{AccountID: accountID}, nil
+	}
+	return b, nil
+}
+
+func (r *FakeBalanceRepo) Apply(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+	b := r.balances[accountID]
+	b.AccountID = accountID
+	b.PostedCents += postedDelta
+	b.PendingCents += pendingDelta
+	r.balances[accountID] = b
+	return nil
+}
+
+func (r *FakeBalanceRepo) Rebuild(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	return r.FindByAccountID(ctx, accountID)
+}
+
+// FakeTransactionManager runs the function without a database transaction
+type FakeTransactionManager struct{}
+
+func (FakeTransactionManager) RunInTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newAccountHandler(repo *FakeAccountRepo, balances *FakeBalanceRepo) *adapterhttp.AccountHandler {
+	changes := &FakeStatusChangeRepo{}
+	return adapterhttp.NewAccountHandler(
+		&usecase.CreateAccount{Accounts: repo, Documents: document.Default()},
+		&usecase.GetAccount{Accounts: repo},
+		&usecase.GetAccountBalance{Accounts: repo, Balances: balances},
+		&usecase.RebuildAccountBalance{Accounts: repo, Balances: balances, TransactionManager: FakeTransactionManager{}},
+		&usecase.UpdateCreditLimit{Accounts: repo, TransactionManager: FakeTransactionManager{}},
+		&usecase.ChangeAccountStatus{Accounts: repo, StatusChanges: changes, TransactionManager: FakeTransactionManager{}},
+		&usecase.ListAccountStatusChanges{Accounts: repo, StatusChanges: changes},
+		&usecase.ListAccounts{Accounts: repo, Documents: document.Default()},
+	)
+}
+
 // AccountResponse mirrors the handler response for testing
 type AccountResponse struct {
-	ID             int64  `json:"account_id"`
-	DocumentNumber string `json:"document_number"`
+	ID                   int64   `json:"account_id"`
+	DocumentNumber       string  `json:"document_number"`
+	AvailableCreditLimit float64 `json:"available_credit_limit"`
 }
 
 func TestCreateAccount(t *testing.T) {
 	repo := NewFakeAccountRepo()
-	createUC := &usecase.CreateAccount{Accounts: repo}
-	getUC := &usecase.GetAccount{Accounts: repo}
-	handler := adapterhttp.NewAccountHandler(createUC, getUC)
+	handler := newAccountHandler(repo, NewFakeBalanceRepo())
 
 	t.Run("success", func(t *testing.T) {
-		reqBody := `{"document_number": "12345678900"}`
+		reqBody := `{"document_number": "529.982.247-25", "available_credit_limit": 500.00}`
 		req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewBufferString(reqBody))
 		w := httptest.NewRecorder()
 
@@ -78,8 +245,27 @@ func TestCreateAccount(t *testing.T) {
 		if body.ID == 0 {
 			t.Error("expected non-zero account ID")
 		}
-		if body.DocumentNumber != "12345678900" {
-			t.Errorf("expected document number 12345678900, got %s", body.DocumentNumber)
+		if body.DocumentNumber != "52998224725" {
+			t.Errorf("expected normalized document number 52998224725, got %s", body.DocumentNumber)
+		}
+		if body.AvailableCreditLimit != 500 {
+			t.Errorf("expected available credit limit 500, got %v", body.AvailableCreditLimit)
+		}
+	})
+
+	t.Run("default credit limit", func(t *testing.T) {
+		reqBody := `{"document_number": "11144477735"}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		handler.CreateAccount(w, req)
+
+		var body AccountResponse
+		if err := json.NewDecoder(w.Result().Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusCreated || body.AvailableCreditLimit != 1000 {
+			t.Errorf("expected 201 with the default limit 1000, got %d %v", w.Code, body.AvailableCreditLimit)
 		}
 	})
 
@@ -95,15 +281,39 @@ func TestCreateAccount(t *testing.T) {
 			t.Errorf("expected status 400, got %d", resp.StatusCode)
 		}
 	})
+
+	t.Run("duplicate document", func(t *testing.T) {
+		reqBody := `{"document_number": "52998224725"}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		handler.CreateAccount(w, req)
+
+		resp := w.Result()
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("document fails checksum", func(t *testing.T) {
+		reqBody := `{"document_number": "529.982.247-26"}`
+		req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewBufferString(reqBody))
+		w := httptest.NewRecorder()
+
+		handler.CreateAccount(w, req)
+
+		resp := w.Result()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", resp.StatusCode)
+		}
+	})
 }
 
 func TestGetAccount(t *testing.T) {
 	repo := NewFakeAccountRepo()
-	repo.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", CreatedAt: time.Now()}
+	repo.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", CreatedAt: time.Now()}
 
-	createUC := &usecase.CreateAccount{Accounts: repo}
-	getUC := &usecase.GetAccount{Accounts: repo}
-	handler := adapterhttp.NewAccountHandler(createUC, getUC)
+	handler := newAccountHandler(repo, NewFakeBalanceRepo())
 
 	t.Run("found", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/accounts/1", nil)
@@ -133,3 +343,1468 @@ func TestGetAccount(t *testing.T) {
 		}
 	})
 }
+
+func TestUpdateCreditLimit(t *testing.T) {
+	repo := NewFakeAccountRepo()
+	repo.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", AvailableCreditLimitCents: 1000}
+
+	handler := newAccountHandler(repo, NewFakeBalanceRepo())
+
+	tests := []struct {
+		name       string
+		accountID  string
+		body       string
+		wantStatus int
+	}{
+		{"success", "1", `{"available_credit_limit": 250.50}`, http.StatusOK},
+		{"negative limit", "1", `{"available_credit_limit": -1}`, http.StatusBadRequest},
+		{"not found", "999", `{"available_credit_limit": 10}`, http.StatusNotFound},
+		{"invalid body", "1", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/accounts/"+tt.accountID+"/credit-limit", bytes.NewBufferString(tt.body))
+			req.SetPathValue("accountID", tt.accountID)
+			w := httptest.NewRecorder()
+
+			handler.UpdateCreditLimit(w, req)
+
+			if w.Result().StatusCode != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Result().StatusCode)
+			}
+		})
+	}
+
+	if repo.accounts[1].AvailableCreditLimitCents != 25050 {
+		t.Errorf("expected limit 25050 cents, got %d", repo.accounts[1].AvailableCreditLimitCents)
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	handler := newAccountHandler(accounts, NewFakeBalanceRepo())
+
+	base := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, doc := range []string{"52998224725", "12345678909", "11144477735"} {
+		_, _ = accounts.Create(context.Background(), domain.Account{DocumentNumber: doc, Currency: "BRL", CreatedAt: base.AddDate(0, 0, i)})
+	}
+	_ = accounts.UpdateStatus(context.Background(), 2, domain.AccountStatusBlocked)
+
+	list := func(query string) (int, adapterhttp.AccountListResponse) {
+		w := httptest.NewRecorder()
+		handler.ListAccounts(w, httptest.NewRequest(http.MethodGet, "/accounts?"+query, nil))
+		var resp adapterhttp.AccountListResponse
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+	ids := func(resp adapterhttp.AccountListResponse) []int64 {
+		var out []int64
+		for _, acc := range resp.Data {
+			out = append(out, acc.ID)
+		}
+		return out
+	}
+
+	t.Run("pages oldest first", func(t *testing.T) {
+		_, first := list("sort=created_at&limit=2")
+		if got := ids(first); len(got) != 2 || got[0] != 1 || got[1] != 2 || first.NextCursor == "" {
+			t.Fatalf("unexpected first page %v (cursor %q)", got, first.NextCursor)
+		}
+		_, second := list("sort=created_at&limit=2&cursor=" + first.NextCursor)
+		if got := ids(second); len(got) != 1 || got[0] != 3 || second.NextCursor != "" {
+			t.Errorf("unexpected second page %v (cursor %q)", got, second.NextCursor)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantIDs    []int64
+	}{
+		{"newest first by default", "", http.StatusOK, []int64{3, 2, 1}},
+		{"formatted document", "document_number=123.456.789-09", http.StatusOK, []int64{2}},
+		{"status", "status=blocked", http.StatusOK, []int64{2}},
+		{"creation range", "from=2026-03-02&to=2026-03-03", http.StatusOK, []int64{2}},
+		{"invalid document", "document_number=123", http.StatusBadRequest, nil},
+		{"invalid status", "status=frozen", http.StatusBadRequest, nil},
+		{"invalid sort", "sort=document_number", http.StatusBadRequest, nil},
+		{"invalid cursor", "cursor=!!", http.StatusBadRequest, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := list(tt.query)
+			if code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, code)
+			}
+			if got := ids(resp); fmt.Sprint(got) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("expected accounts %v, got %v", tt.wantIDs, got)
+			}
+		})
+	}
+}
+
+func TestAccountStatus(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	handler := newAccountHandler(accounts, NewFakeBalanceRepo())
+	id, _ := accounts.Create(context.Background(), domain.Account{DocumentNumber: "52998224725", AvailableCreditLimitCents: 100000, Currency: "BRL"})
+
+	txHandler := adapterhttp.NewTransactionHandler(&usecase.CreateTransaction{
+		Accounts:           accounts,
+		OperationTypes:     NewFakeOperationTypeRepo(),
+		Transactions:       &FakeTransactionRepo{},
+		Balances:           NewFakeBalanceRepo(),
+		TransactionManager: FakeTransactionManager{},
+	}, nil, nil, nil)
+
+	changeStatus := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/accounts/1/status", bytes.NewBufferString(body))
+		req.SetPathValue("accountID", strconv.FormatInt(id, 10))
+		w := httptest.NewRecorder()
+		handler.ChangeStatus(w, req)
+		return w
+	}
+	createTransaction := func(operationTypeID int) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"account_id": %d, "operation_type_id": %d, "amount": 10}`, id, operationTypeID)
+		w := httptest.NewRecorder()
+		txHandler.CreateTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body)))
+		return w
+	}
+
+	// Each step either changes the status or posts a transaction.
+	steps := []struct {
+		name            string
+		statusBody      string
+		operationTypeID int
+		wantStatus      int
+	}{
+		{"block", `{"status": "BLOCKED", "reason": "lost card"}`, 0, http.StatusOK},
+		{"debit on blocked", "", domain.OperationTypeNormalPurchase, http.StatusLocked},
+		{"credit on blocked", "", domain.OperationTypeCreditVoucher, http.StatusCreated},
+		{"block again", `{"status": "BLOCKED", "reason": "again"}`, 0, http.StatusConflict},
+		{"missing reason", `{"status": "ACTIVE"}`, 0, http.StatusBadRequest},
+		{"close", `{"status": "CLOSED", "reason": "customer request"}`, 0, http.StatusOK},
+		{"credit on closed", "", domain.OperationTypeCreditVoucher, http.StatusGone},
+		{"reopen", `{"status": "ACTIVE", "reason": "oops"}`, 0, http.StatusConflict},
+	}
+	for _, step := range steps {
+		var w *httptest.ResponseRecorder
+		if step.statusBody != "" {
+			w = changeStatus(step.statusBody)
+		} else {
+			w = createTransaction(step.operationTypeID)
+		}
+		if w.Code != step.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d (%s)", step.name, step.wantStatus, w.Code, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/1/status-history", nil)
+	req.SetPathValue("accountID", strconv.FormatInt(id, 10))
+	w := httptest.NewRecorder()
+	handler.StatusHistory(w, req)
+
+	var resp adapterhttp.AccountStatusHistoryResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected 2 status changes, got %+v", resp.Data)
+	}
+	if resp.Data[0].From != "ACTIVE" || resp.Data[0].To != "BLOCKED" || resp.Data[0].Reason != "lost card" {
+		t.Errorf("unexpected first change %+v", resp.Data[0])
+	}
+	if resp.Data[1].From != "BLOCKED" || resp.Data[1].To != "CLOSED" {
+		t.Errorf("unexpected second change %+v", resp.Data[1])
+	}
+}
+
+func TestGetAccountBalance(t *testing.T) {
+	repo := NewFakeAccountRepo()
+	repo.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", CreatedAt: time.Now()}
+
+	balances := NewFakeBalanceRepo()
+	balances.balances[1] = domain.AccountBalance{AccountID: 1, PostedCents: -5000, PendingCents: 1000}
+
+	handler := newAccountHandler(repo, balances)
+
+	t.Run("found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/1/balance", nil)
+		req.SetPathValue("accountID", "1")
+		w := httptest.NewRecorder()
+
+		handler.GetAccountBalance(w, req)
+
+		resp := w.Result()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", resp.StatusCode)
+		}
+
+		var body adapterhttp.AccountBalanceResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.PostedCents != -5000 || body.PendingCents != 1000 || body.AvailableCents != -6000 {
+			t.Errorf("unexpected balance %+v", body)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/999/balance", nil)
+		req.SetPathValue("accountID", "999")
+		w := httptest.NewRecorder()
+
+		handler.GetAccountBalance(w, req)
+
+		if w.Result().StatusCode != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Result().StatusCode)
+		}
+	})
+}
+
+// FakeTransactionRepo implements port.TransactionRepository
+type FakeTransactionRepo struct {
+	transactions []domain.Transaction
+}
+
+func (r *FakeTransactionRepo) Create(ctx context.Context, tx domain.Transaction) (int64, error) {
+	tx.ID = int64(len(r.transactions) + 1)
+	if tx.Status == "" {
+		tx.Status = domain.TransactionStatusPosted
+	}
+	if tx.Currency == "" {
+		tx.Currency = domain.DefaultCurrency
+	}
+	r.transactions = append(r.transactions, tx)
+	return tx.ID, nil
+}
+
+func (r *FakeTransactionRepo) FindByID(ctx context.Context, id int64) (domain.Transaction, error) {
+	if id < 1 || id > int64(len(r.transactions)) {
+		return domain.Transaction{}, domain.ErrTransactionNotFound
+	}
+	return r.transactions[id-1], nil
+}
+
+func (r *FakeTransactionRepo) FindOpenDebitsForUpdate(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+	return nil, nil
+}
+
+func (r *FakeTransactionRepo) UpdateBalance(ctx context.Context, id int64, balanceCents int64) error {
+	return nil
+}
+
+func (r *FakeTransactionRepo) ListInstallments(ctx context.Context, parentID int64) ([]domain.Transaction, error) {
+	var out []domain.Transaction
+	for _, tx := range r.transactions {
+		if tx.ParentID == parentID {
+			out = append(out, tx)
+		}
+	}
+	return out, nil
+}
+
+func (r *FakeTransactionRepo) FindDueScheduled(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error) {
+	return nil, nil
+}
+
+func (r *FakeTransactionRepo) MarkPosted(ctx context.Context, id int64, balanceCents int64) error {
+	return nil
+}
+
+func (r *FakeTransactionRepo) SumByPeriod(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error) {
+	return 0, 0, nil
+}
+
+func (r *FakeTransactionRepo) SumReversals(ctx context.Context, id int64) (int64, error) {
+	var sum int64
+	for _, tx := range r.transactions {
+		if tx.ReversedOf == id {
+			sum += max(tx.AmountCents, -tx.AmountCents)
+		}
+	}
+	return sum, nil
+}
+
+func (r *FakeTransactionRepo) ListByAccount(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	var out []domain.Transaction
+	for i := len(r.transactions) - 1; i >= 0 && len(out) < filter.Limit; i-- {
+		tx := r.transactions[i]
+		if tx.AccountID != filter.AccountID {
+			continue
+		}
+		if filter.After != nil && tx.ID >= filter.After.ID {
+			continue
+		}
+		out = append(out, tx)
+	}
+	return out, nil
+}
+
+func TestListTransactions(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL"}
+
+	txRepo := &FakeTransactionRepo{}
+	for i := 0; i < 3; i++ {
+		_, _ = txRepo.Create(context.Background(), domain.Transaction{AccountID: 1, AmountCents: -1000, EventDate: time.Now()})
+	}
+
+	handler := adapterhttp.NewTransactionHandler(nil, &usecase.ListTransactions{Accounts: accounts, Transactions: txRepo}, nil, nil)
+
+	list := func(accountID, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/"+accountID+"/transactions?"+query, nil)
+		req.SetPathValue("accountID", accountID)
+		w := httptest.NewRecorder()
+		handler.ListTransactions(w, req)
+		return w
+	}
+
+	t.Run("pages through history", func(t *testing.T) {
+		w := list("1", "limit=2")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var first adapterhttp.TransactionListResponse
+		if err := json.NewDecoder(w.Body).Decode(&first); err != nil {
+			t.Fatal(err)
+		}
+		if len(first.Data) != 2 || first.NextCursor == "" {
+			t.Fatalf("expected 2 items and a cursor, got %d items and %q", len(first.Data), first.NextCursor)
+		}
+
+		w = list("1", "limit=2&cursor="+first.NextCursor)
+		var second adapterhttp.TransactionListResponse
+		if err := json.NewDecoder(w.Body).Decode(&second); err != nil {
+			t.Fatal(err)
+		}
+		if len(second.Data) != 1 || second.NextCursor != "" {
+			t.Errorf("expected last item without cursor, got %d items and %q", len(second.Data), second.NextCursor)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		accountID  string
+		query      string
+		wantStatus int
+	}{
+		{"unknown account", "999", "", http.StatusNotFound},
+		{"invalid cursor", "1", "cursor=bm90LWEtY3Vyc29y", http.StatusBadRequest},
+		{"invalid limit", "1", "limit=abc", http.StatusBadRequest},
+		{"limit too large", "1", "limit=1000", http.StatusBadRequest},
+		{"invalid date", "1", "from=yesterday", http.StatusBadRequest},
+		{"inverted range", "1", "from=2026-02-01&to=2026-01-01", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := list(tt.accountID, tt.query); w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+// FakeOperationTypeRepo implements port.OperationTypeRepository
+type FakeOperationTypeRepo struct {
+	types  map[int]domain.OperationType
+	nextID int
+}
+
+// NewFakeOperationTypeRepo is seeded with the default operation types.
+func NewFakeOperationTypeRepo() *FakeOperationTypeRepo {
+	r := &FakeOperationTypeRepo{types: make(map[int]domain.OperationType), nextID: 100}
+	for _, ot := range []domain.OperationType{
+		{ID: domain.OperationTypeNormalPurchase, Description: "NORMAL PURCHASE", Sign: -1},
+		{ID: domain.OperationTypePurchaseInstallment, Description: "PURCHASE WITH INSTALLMENTS", Sign: -1},
+		{ID: domain.OperationTypeWithdrawal, Description: "WITHDRAWAL", Sign: -1},
+		{ID: domain.OperationTypeCreditVoucher, Description: "CREDIT VOUCHER", Sign: 1},
+		{ID: domain.OperationTypeDebitReversal, Description: "DEBIT REVERSAL", Sign: 1},
+		{ID: domain.OperationTypeCreditReversal, Description: "CREDIT REVERSAL", Sign: -1},
+	} {
+		ot.AffectsCreditLimit = true
+		ot.Active = true
+		ot.Reversible = ot.ID != domain.OperationTypeDebitReversal && ot.ID != domain.OperationTypeCreditReversal
+		r.types[ot.ID] = ot
+	}
+	return r
+}
+
+func (r *FakeOperationTypeRepo) FindByID(ctx context.Context, id int) (domain.OperationType, error) {
+	ot, ok := r.types[id]
+	if !ok {
+		return domain.OperationType{}, domain.ErrOperationTypeNotFound
+	}
+	return ot, nil
+}
+
+func (r *FakeOperationTypeRepo) FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *FakeOperationTypeRepo) List(ctx context.Context) ([]domain.OperationType, error) {
+	out := make([]domain.OperationType, 0, len(r.types))
+	for _, ot := range r.types {
+		out = append(out, ot)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *FakeOperationTypeRepo) Create(ctx context.Context, ot domain.OperationType) (int, error) {
+	if ot.ID == 0 {
+		ot.ID = r.nextID
+		r.nextID++
+	}
+	if _, ok := r.types[ot.ID]; ok {
+		return 0, domain.ErrOperationTypeExists
+	}
+	r.types[ot.ID] = ot
+	return ot.ID, nil
+}
+
+func (r *FakeOperationTypeRepo) Update(ctx context.Context, ot domain.OperationType) error {
+	if _, ok := r.types[ot.ID]; !ok {
+		return domain.ErrOperationTypeNotFound
+	}
+	r.types[ot.ID] = ot
+	return nil
+}
+
+func TestGetTransaction(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL"}
+
+	txRepo := &FakeTransactionRepo{}
+	_, _ = txRepo.Create(context.Background(), domain.Transaction{
+		AccountID:       1,
+		OperationTypeID: domain.OperationTypeNormalPurchase,
+		AmountCents:     -1234,
+		BalanceCents:    -1234,
+		EventDate:       time.Now(),
+	})
+
+	getUC := &usecase.GetTransaction{Accounts: accounts, OperationTypes: NewFakeOperationTypeRepo(), Transactions: txRepo}
+	handler := adapterhttp.NewTransactionHandler(nil, nil, getUC, nil)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/transactions/"+id, nil)
+		req.SetPathValue("transactionID", id)
+		w := httptest.NewRecorder()
+		handler.GetTransaction(w, req)
+		return w
+	}
+
+	t.Run("found", func(t *testing.T) {
+		w := get("1")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+
+		var body adapterhttp.TransactionDetailResponse
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Amount != -12.34 {
+			t.Errorf("expected signed amount -12.34, got %v", body.Amount)
+		}
+		if body.OperationTypeDescription != "NORMAL PURCHASE" {
+			t.Errorf("expected description NORMAL PURCHASE, got %q", body.OperationTypeDescription)
+		}
+		if body.Account.ID != 1 {
+			t.Errorf("expected account 1, got %d", body.Account.ID)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if w := get("999"); w.Code != http.StatusNotFound {
+			t.Errorf("expected status 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		if w := get("abc"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+}
+
+func TestReverseTransaction(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL"}
+
+	txRepo := &FakeTransactionRepo{}
+	_, _ = txRepo.Create(context.Background(), domain.Transaction{
+		AccountID:       1,
+		OperationTypeID: domain.OperationTypeNormalPurchase,
+		AmountCents:     -5000,
+		BalanceCents:    -5000,
+		EventDate:       time.Now(),
+	})
+
+	reverseUC := &usecase.ReverseTransaction{
+		Accounts:           accounts,
+		OperationTypes:     NewFakeOperationTypeRepo(),
+		Transactions:       txRepo,
+		Balances:           NewFakeBalanceRepo(),
+		TransactionManager: FakeTransactionManager{},
+	}
+	handler := adapterhttp.NewTransactionHandler(nil, nil, nil, reverseUC)
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		wantStatus int
+	}{
+		{"partial reversal", "1", `{"amount": 30.00}`, http.StatusCreated},
+		{"beyond original", "1", `{"amount": 30.00}`, http.StatusUnprocessableEntity},
+		{"remaining without body", "1", ``, http.StatusCreated},
+		{"reversal of reversal", "2", ``, http.StatusUnprocessableEntity},
+		{"unknown transaction", "999", ``, http.StatusNotFound},
+		{"invalid body", "1", `{`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transactions/"+tt.id+"/reversal", bytes.NewBufferString(tt.body))
+			req.SetPathValue("transactionID", tt.id)
+			w := httptest.NewRecorder()
+
+			handler.ReverseTransaction(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_Installments(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", AvailableCreditLimitCents: 100000}
+
+	txRepo := &FakeTransactionRepo{}
+	balances := NewFakeBalanceRepo()
+	createUC := &usecase.CreateTransaction{
+		Accounts:           accounts,
+		OperationTypes:     NewFakeOperationTypeRepo(),
+		Transactions:       txRepo,
+		Balances:           balances,
+		TransactionManager: FakeTransactionManager{},
+	}
+	getUC := &usecase.GetTransaction{Accounts: accounts, OperationTypes: NewFakeOperationTypeRepo(), Transactions: txRepo}
+	handler := adapterhttp.NewTransactionHandler(createUC, nil, getUC, nil)
+
+	t.Run("invalid installments", func(t *testing.T) {
+		body := `{"account_id": 1, "operation_type_id": 1, "amount": 100.00, "installments": 3}`
+		w := httptest.NewRecorder()
+		handler.CreateTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body)))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected status 400, got %d", w.Code)
+		}
+	})
+
+	body := `{"account_id": 1, "operation_type_id": 2, "amount": 100.00, "installments": 3}`
+	w := httptest.NewRecorder()
+	handler.CreateTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body)))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var created adapterhttp.TransactionResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Installments != 3 || created.Amount != -100 {
+		t.Errorf("unexpected plan %+v", created)
+	}
+
+	if b := balances.balances[1]; b.PostedCents != -3334 || b.PendingCents != 6666 {
+		t.Errorf("expected only the first installment posted, got %+v", b)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/transactions/1", nil)
+	req.SetPathValue("transactionID", strconv.FormatInt(created.ID, 10))
+	w = httptest.NewRecorder()
+	handler.GetTransaction(w, req)
+
+	var details adapterhttp.TransactionDetailResponse
+	if err := json.NewDecoder(w.Body).Decode(&details); err != nil {
+		t.Fatal(err)
+	}
+	if len(details.Schedule) != 3 {
+		t.Fatalf("expected 3 installments, got %d", len(details.Schedule))
+	}
+	for i, installment := range details.Schedule {
+		wantStatus := string(domain.TransactionStatusScheduled)
+		if i == 0 {
+			wantStatus = string(domain.TransactionStatusPosted)
+		}
+		if installment.InstallmentNumber != i+1 || installment.ParentID != created.ID || installment.Status != wantStatus {
+			t.Errorf("unexpected installment %+v", installment)
+		}
+	}
+}
+
+// FakeStatementRepo implements port.StatementRepository
+type FakeStatementRepo struct {
+	statements []domain.Statement
+}
+
+func (r *FakeStatementRepo) Create(ctx context.Context, statement domain.Statement) (int64, error) {
+	statement.ID = int64(len(r.statements) + 1)
+	r.statements = append(r.statements, statement)
+	return statement.ID, nil
+}
+
+func (r *FakeStatementRepo) FindByID(ctx context.Context, id int64) (domain.Statement, error) {
+	if id < 1 || id > int64(len(r.statements)) {
+		return domain.Statement{}, domain.ErrStatementNotFound
+	}
+	return r.statements[id-1], nil
+}
+
+func (r *FakeStatementRepo) FindLatest(ctx context.Context, accountID int64) (domain.Statement, error) {
+	return domain.Statement{}, domain.ErrStatementNotFound
+}
+
+func (r *FakeStatementRepo) ListByAccount(ctx context.Context, accountID int64) ([]domain.Statement, error) {
+	var out []domain.Statement
+	for _, s := range r.statements {
+		if s.AccountID == accountID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func TestStatements(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL"}
+
+	end := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	statements := &FakeStatementRepo{}
+	_, _ = statements.Create(context.Background(), domain.NewStatement(1, end.AddDate(0, -1, 0), end, 0, 12345, 0))
+
+	handler := adapterhttp.NewStatementHandler(
+		&usecase.ListStatements{Accounts: accounts, Statements: statements},
+		&usecase.GetStatement{Statements: statements},
+	)
+
+	t.Run("list", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/accounts/1/statements", nil)
+		req.SetPathValue("accountID", "1")
+		w := httptest.NewRecorder()
+		handler.ListStatements(w, req)
+
+		var resp adapterhttp.StatementListResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Data) != 1 || resp.Data[0].TotalDue != 123.45 || resp.Data[0].MinimumPayment != 25 {
+			t.Errorf("unexpected statements %+v", resp.Data)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		param      string
+		id         string
+		handle     http.HandlerFunc
+		wantStatus int
+	}{
+		{"list unknown account", "/accounts/9/statements", "accountID", "9", handler.ListStatements, http.StatusNotFound},
+		{"get", "/statements/1", "statementID", "1", handler.GetStatement, http.StatusOK},
+		{"get unknown", "/statements/9", "statementID", "9", handler.GetStatement, http.StatusNotFound},
+		{"get invalid id", "/statements/abc", "statementID", "abc", handler.GetStatement, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.SetPathValue(tt.param, tt.id)
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
+
+// FakeAuthorizationRepo implements port.AuthorizationRepository
+type FakeAuthorizationRepo struct {
+	authorizations []domain.Authorization
+}
+
+func (r *FakeAuthorizationRepo) Create(ctx context.Context, auth domain.Authorization) (int64, error) {
+	auth.ID = int64(len(r.authorizations) + 1)
+	r.authorizations = append(r.authorizations, auth)
+	return auth.ID, nil
+}
+
+func (r *FakeAuthorizationRepo) FindByID(ctx context.Context, id int64) (domain.Authorization, error) {
+	if id < 1 || id > int64(len(r.authorizations)) {
+		return domain.Authorization{}, domain.ErrAuthorizationNotFound
+	}
+	return r.authorizations[id-1], nil
+}
+
+func (r *FakeAuthorizationRepo) FindByIDForUpdate(ctx context.Context, id int64) (domain.Authorization, error) {
+	return r.FindByID(ctx, id)
+}
+
+func (r *FakeAuthorizationRepo) Close(ctx context.Context, auth domain.Authorization) error {
+	if r.authorizations[auth.ID-1].Status != domain.AuthorizationStatusPending {
+		return domain.ErrAuthorizationClosed
+	}
+	r.authorizations[auth.ID-1] = auth
+	return nil
+}
+
+func (r *FakeAuthorizationRepo) FindExpired(ctx context.Context, asOf time.Time, limit int) ([]domain.Authorization, error) {
+	return nil, nil
+}
+
+func TestAuthorizations(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", Status: domain.AccountStatusActive, AvailableCreditLimitCents: 10000}
+
+	opTypes := NewFakeOperationTypeRepo()
+	txRepo := &FakeTransactionRepo{}
+	balances := NewFakeBalanceRepo()
+	auths := &FakeAuthorizationRepo{}
+	authorizeUC := &usecase.AuthorizeTransaction{
+		Accounts:           accounts,
+		OperationTypes:     opTypes,
+		Transactions:       txRepo,
+		Balances:           balances,
+		Authorizations:     auths,
+		TransactionManager: FakeTransactionManager{},
+		Clock:              clock.System{},
+	}
+	captureUC := &usecase.CaptureAuthorization{
+		Accounts:           accounts,
+		OperationTypes:     opTypes,
+		Transactions:       txRepo,
+		Balances:           balances,
+		Authorizations:     auths,
+		TransactionManager: FakeTransactionManager{},
+		Clock:              clock.System{},
+	}
+	voidUC := &usecase.VoidAuthorization{
+		Accounts:           accounts,
+		OperationTypes:     opTypes,
+		Transactions:       txRepo,
+		Balances:           balances,
+		Authorizations:     auths,
+		TransactionManager: FakeTransactionManager{},
+		Clock:              clock.System{},
+	}
+	handler := adapterhttp.NewAuthorizationHandler(authorizeUC, &usecase.GetAuthorization{Authorizations: auths}, captureUC, voidUC)
+
+	authorize := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.CreateAuthorization(w, httptest.NewRequest(http.MethodPost, "/authorizations", bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := authorize(`{"account_id": 1, "operation_type_id": 1, "amount": 60.00}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created adapterhttp.AuthorizationResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 1 || created.Status != "PENDING" || created.Amount != 60 || created.ExpiresAt.IsZero() {
+		t.Errorf("unexpected authorization %+v", created)
+	}
+	if b := balances.balances[1]; b.PendingCents != 6000 || accounts.accounts[1].AvailableCreditLimitCents != 4000 {
+		t.Errorf("expected 60.00 held, got balance %+v and limit %d", b, accounts.accounts[1].AvailableCreditLimitCents)
+	}
+
+	t.Run("capture part of the hold", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/authorizations/1/capture", bytes.NewBufferString(`{"amount": 45.00}`))
+		req.SetPathValue("authorizationID", "1")
+		w := httptest.NewRecorder()
+		handler.CaptureAuthorization(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp adapterhttp.CaptureAuthorizationResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Authorization.Status != "CAPTURED" || resp.Authorization.CapturedCents != 4500 || resp.Transaction.AmountCents != -4500 {
+			t.Errorf("unexpected capture %+v", resp)
+		}
+		if resp.Authorization.TransactionID != resp.Transaction.ID {
+			t.Errorf("expected the authorization to point at transaction %d, got %d", resp.Transaction.ID, resp.Authorization.TransactionID)
+		}
+		if b := balances.balances[1]; b.PendingCents != 0 || b.PostedCents != -4500 || accounts.accounts[1].AvailableCreditLimitCents != 5500 {
+			t.Errorf("expected the rest of the hold released, got balance %+v and limit %d", b, accounts.accounts[1].AvailableCreditLimitCents)
+		}
+	})
+
+	if w := authorize(`{"account_id": 1, "operation_type_id": 1, "amount": 10.00}`); w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d", w.Code)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		id         string
+		body       string
+		handle     http.HandlerFunc
+		wantStatus int
+	}{
+		{"get", "/authorizations/2", "2", ``, handler.GetAuthorization, http.StatusOK},
+		{"get unknown", "/authorizations/9", "9", ``, handler.GetAuthorization, http.StatusNotFound},
+		{"capture beyond hold", "/authorizations/2/capture", "2", `{"amount": 10.01}`, handler.CaptureAuthorization, http.StatusUnprocessableEntity},
+		{"capture invalid body", "/authorizations/2/capture", "2", `{`, handler.CaptureAuthorization, http.StatusBadRequest},
+		{"void", "/authorizations/2/void", "2", ``, handler.VoidAuthorization, http.StatusOK},
+		{"void twice", "/authorizations/2/void", "2", ``, handler.VoidAuthorization, http.StatusConflict},
+		{"capture voided", "/authorizations/2/capture", "2", ``, handler.CaptureAuthorization, http.StatusConflict},
+		{"capture unknown", "/authorizations/9/capture", "9", ``, handler.CaptureAuthorization, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			req.SetPathValue("authorizationID", tt.id)
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("rejections", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			body       string
+			wantStatus int
+		}{
+			{"credit operation", `{"account_id": 1, "operation_type_id": 4, "amount": 10.00}`, http.StatusBadRequest},
+			{"over the limit", `{"account_id": 1, "operation_type_id": 1, "amount": 100.00}`, http.StatusBadRequest},
+			{"other currency", `{"account_id": 1, "operation_type_id": 1, "amount": 10.00, "currency": "USD"}`, http.StatusUnprocessableEntity},
+			{"unknown account", `{"account_id": 9, "operation_type_id": 1, "amount": 10.00}`, http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if w := authorize(tt.body); w.Code != tt.wantStatus {
+					t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+				}
+			})
+		}
+	})
+}
+
+// FakeWebhookRepo implements port.WebhookSubscriptionRepository
+type FakeWebhookRepo struct {
+	subs []domain.WebhookSubscription
+}
+
+func (r *FakeWebhookRepo) Create(ctx context.Context, sub domain.WebhookSubscription) (int64, error) {
+	sub.ID = int64(len(r.subs) + 1)
+	r.subs = append(r.subs, sub)
+	return sub.ID, nil
+}
+
+func (r *FakeWebhookRepo) FindByID(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	if id < 1 || id > int64(len(r.subs)) {
+		return domain.WebhookSubscription{}, domain.ErrWebhookNotFound
+	}
+	return r.subs[id-1], nil
+}
+
+func (r *FakeWebhookRepo) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.subs, nil
+}
+
+func (r *FakeWebhookRepo) Deactivate(ctx context.Context, id int64, at time.Time) error {
+	if id < 1 || id > int64(len(r.subs)) {
+		return domain.ErrWebhookNotFound
+	}
+	r.subs[id-1].Active = false
+	return nil
+}
+
+func (r *FakeWebhookRepo) FindMatching(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error) {
+	return nil, nil
+}
+
+// FakeResolver implements port.Resolver. IP literals resolve to themselves and
+// any other host to a public documentation address.
+type FakeResolver struct{}
+
+func (FakeResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return []netip.Addr{addr}, nil
+	}
+	return []netip.Addr{netip.MustParseAddr("203.0.113.10")}, nil
+}
+
+// FakeDeliveryRepo implements port.WebhookDeliveryRepository
+type FakeDeliveryRepo struct {
+	deliveries []domain.WebhookDelivery
+}
+
+func (r *FakeDeliveryRepo) Enqueue(ctx context.Context, d domain.WebhookDelivery) error {
+	d.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *FakeDeliveryRepo) FindByID(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	if id < 1 || id > int64(len(r.deliveries)) {
+		return domain.WebhookDelivery{}, domain.ErrDeliveryNotFound
+	}
+	return r.deliveries[id-1], nil
+}
+
+func (r *FakeDeliveryRepo) ListBySubscription(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var out []domain.WebhookDelivery
+	for i := len(r.deliveries) - 1; i >= 0 && len(out) < filter.Limit; i-- {
+		d := r.deliveries[i]
+		if d.SubscriptionID != filter.SubscriptionID || (filter.Status != "" && d.Status != filter.Status) {
+			continue
+		}
+		if filter.After != nil && d.ID >= filter.After.ID {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out, nil
+}
+
+func (r *FakeDeliveryRepo) ClaimDue(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (r *FakeDeliveryRepo) Update(ctx context.Context, d domain.WebhookDelivery, seen time.Time) error {
+	if !r.deliveries[d.ID-1].NextAttemptAt.Equal(seen) {
+		return domain.ErrConcurrentUpdate
+	}
+	r.deliveries[d.ID-1] = d
+	return nil
+}
+
+func TestWebhooks(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", Status: domain.AccountStatusActive}
+	subs := &FakeWebhookRepo{}
+	deliveries := &FakeDeliveryRepo{}
+	handler := adapterhttp.NewWebhookHandler(
+		&usecase.CreateWebhook{Subscriptions: subs, Accounts: accounts, Resolver: FakeResolver{}},
+		&usecase.ListWebhooks{Subscriptions: subs},
+		&usecase.GetWebhook{Subscriptions: subs},
+		&usecase.DeleteWebhook{Subscriptions: subs},
+		&usecase.ListWebhookDeliveries{Subscriptions: subs, Deliveries: deliveries},
+		&usecase.RedeliverWebhook{Subscriptions: subs, Deliveries: deliveries, Clock: clock.System{}},
+	)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.CreateWebhook(w, httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := create(`{"url": "https://partner.example/hooks", "event_types": ["TransactionPosted"], "account_id": 1}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+	var created adapterhttp.WebhookResponse
+	if err := json.NewDecoder(w.Body).Decode(&created); err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != 1 || !created.Active || created.AccountID != 1 || len(created.EventTypes) != 1 {
+		t.Errorf("unexpected webhook %+v", created)
+	}
+	if !strings.HasPrefix(created.Secret, "whsec_") {
+		t.Errorf("expected the signing secret on creation, got %q", created.Secret)
+	}
+
+	t.Run("secret is not shown again", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/webhooks/1", nil)
+		req.SetPathValue("webhookID", "1")
+		w := httptest.NewRecorder()
+		handler.GetWebhook(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", w.Code)
+		}
+		if strings.Contains(w.Body.String(), created.Secret) {
+			t.Errorf("expected no secret, got %s", w.Body.String())
+		}
+	})
+
+	t.Run("rejections", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			body       string
+			wantStatus int
+			wantCode   string
+		}{
+			{"relative url", `{"url": "/hooks"}`, http.StatusBadRequest, "invalid_url"},
+			{"other scheme", `{"url": "ftp://partner.example"}`, http.StatusBadRequest, "invalid_url"},
+			{"cloud metadata", `{"url": "http://169.254.169.254/latest"}`, http.StatusUnprocessableEntity, "webhook_target_denied"},
+			{"unknown event type", `{"url": "https://partner.example", "event_types": ["AccountDeleted"]}`, http.StatusBadRequest, "invalid_event_type"},
+			{"unknown account", `{"url": "https://partner.example", "account_id": 9}`, http.StatusNotFound, "account_not_found"},
+			{"malformed", `{`, http.StatusBadRequest, "malformed_body"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				w := create(tt.body)
+				if w.Code != tt.wantStatus {
+					t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+				}
+				var p adapterhttp.Problem
+				if err := json.NewDecoder(w.Body).Decode(&p); err != nil || p.Code != tt.wantCode {
+					t.Errorf("expected code %s, got %+v (%v)", tt.wantCode, p, err)
+				}
+			})
+		}
+	})
+
+	event := domain.Event{ID: 7, Type: domain.EventTransactionPosted, AccountID: 1, Payload: []byte(`{}`), CreatedAt: time.Now()}
+	for range 3 {
+		d := domain.NewWebhookDelivery(1, event, time.Now())
+		d.Status = domain.WebhookDeliveryDead
+		d.Attempts = 10
+		_ = deliveries.Enqueue(context.Background(), d)
+	}
+	_ = deliveries.Enqueue(context.Background(), domain.NewWebhookDelivery(2, event, time.Now()))
+
+	t.Run("delivery log", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/webhooks/1/deliveries?status=dead&limit=2", nil)
+		req.SetPathValue("webhookID", "1")
+		w := httptest.NewRecorder()
+		handler.ListDeliveries(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d (%s)", w.Code, w.Body.String())
+		}
+		var resp adapterhttp.WebhookDeliveryListResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Data) != 2 || resp.Data[0].ID != 3 || resp.NextCursor == "" {
+			t.Errorf("expected the two newest deliveries and a cursor, got %+v", resp)
+		}
+		if resp.Data[0].Status != "DEAD" || resp.Data[0].NextAttemptAt != nil {
+			t.Errorf("unexpected delivery %+v", resp.Data[0])
+		}
+	})
+
+	tests := []struct {
+		name       string
+		path       string
+		webhookID  string
+		deliveryID string
+		handle     http.HandlerFunc
+		wantStatus int
+	}{
+		{"redeliver", "/webhooks/1/deliveries/1/redeliver", "1", "1", handler.Redeliver, http.StatusAccepted},
+		{"redeliver another webhook's delivery", "/webhooks/1/deliveries/4/redeliver", "1", "4", handler.Redeliver, http.StatusNotFound},
+		{"redeliver unknown delivery", "/webhooks/1/deliveries/9/redeliver", "1", "9", handler.Redeliver, http.StatusNotFound},
+		{"deliveries of unknown webhook", "/webhooks/9/deliveries", "9", "", handler.ListDeliveries, http.StatusNotFound},
+		{"invalid status", "/webhooks/1/deliveries?status=LOST", "1", "", handler.ListDeliveries, http.StatusBadRequest},
+		{"delete", "/webhooks/1", "1", "", handler.DeleteWebhook, http.StatusNoContent},
+		{"redeliver after delete", "/webhooks/1/deliveries/2/redeliver", "1", "2", handler.Redeliver, http.StatusConflict},
+		{"delete unknown", "/webhooks/9", "9", "", handler.DeleteWebhook, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.SetPathValue("webhookID", tt.webhookID)
+			req.SetPathValue("deliveryID", tt.deliveryID)
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if d := deliveries.deliveries[0]; d.Status != domain.WebhookDeliveryPending || d.Attempts != 0 {
+		t.Errorf("expected the redelivered delivery pending with fresh attempts, got %+v", d)
+	}
+	if subs.subs[0].Active {
+		t.Error("expected the webhook to be deactivated")
+	}
+}
+
+func TestOperationTypes(t *testing.T) {
+	opTypes := NewFakeOperationTypeRepo()
+	handler := adapterhttp.NewOperationTypeHandler(
+		&usecase.CreateOperationType{OperationTypes: opTypes},
+		&usecase.ListOperationTypes{OperationTypes: opTypes},
+		&usecase.GetOperationType{OperationTypes: opTypes},
+		&usecase.UpdateOperationType{OperationTypes: opTypes, TransactionManager: FakeTransactionManager{}},
+	)
+
+	t.Run("create", func(t *testing.T) {
+		body := `{"description": "ANNUAL FEE", "sign": -1, "affects_credit_limit": false}`
+		req := httptest.NewRequest(http.MethodPost, "/operation-types", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		handler.CreateOperationType(w, req)
+
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected status %d, got %d (%s)", http.StatusCreated, w.Code, w.Body.String())
+		}
+		var resp adapterhttp.OperationTypeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.ID != 100 || resp.AffectsCreditLimit || !resp.Reversible || !resp.Active {
+			t.Errorf("unexpected operation type %+v", resp)
+		}
+	})
+
+	t.Run("deactivate", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/operation-types/3", bytes.NewBufferString(`{"active": false}`))
+		req.SetPathValue("operationTypeID", "3")
+		w := httptest.NewRecorder()
+		handler.UpdateOperationType(w, req)
+
+		var resp adapterhttp.OperationTypeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Active || resp.Description != "WITHDRAWAL" || resp.Sign != -1 {
+			t.Errorf("unexpected operation type %+v", resp)
+		}
+	})
+
+	t.Run("list active", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/operation-types?active=true", nil)
+		w := httptest.NewRecorder()
+		handler.ListOperationTypes(w, req)
+
+		var resp adapterhttp.OperationTypeListResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Data) != 6 {
+			t.Fatalf("expected 6 active operation types, got %d", len(resp.Data))
+		}
+		for _, ot := range resp.Data {
+			if ot.ID == domain.OperationTypeWithdrawal {
+				t.Error("inactive operation type listed")
+			}
+		}
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		id         string
+		handle     http.HandlerFunc
+		wantStatus int
+	}{
+		{"get", http.MethodGet, "/operation-types/4", "", "4", handler.GetOperationType, http.StatusOK},
+		{"get unknown", http.MethodGet, "/operation-types/99", "", "99", handler.GetOperationType, http.StatusNotFound},
+		{"create duplicate id", http.MethodPost, "/operation-types", `{"operation_type_id": 1, "description": "X", "sign": -1}`, "", handler.CreateOperationType, http.StatusConflict},
+		{"create invalid sign", http.MethodPost, "/operation-types", `{"description": "X", "sign": 2}`, "", handler.CreateOperationType, http.StatusBadRequest},
+		{"update unknown", http.MethodPatch, "/operation-types/99", `{"active": true}`, "99", handler.UpdateOperationType, http.StatusNotFound},
+		{"update blank description", http.MethodPatch, "/operation-types/1", `{"description": ""}`, "1", handler.UpdateOperationType, http.StatusBadRequest},
+		{"list invalid filter", http.MethodGet, "/operation-types?active=maybe", "", "", handler.ListOperationTypes, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			if tt.id != "" {
+				req.SetPathValue("operationTypeID", tt.id)
+			}
+			w := httptest.NewRecorder()
+			tt.handle(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_InactiveOperationType(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", AvailableCreditLimitCents: 100000, Currency: "BRL"}
+	accounts.nextID = 2
+
+	opTypes := NewFakeOperationTypeRepo()
+	ot := opTypes.types[domain.OperationTypeWithdrawal]
+	ot.Active = false
+	opTypes.types[ot.ID] = ot
+
+	handler := adapterhttp.NewTransactionHandler(&usecase.CreateTransaction{
+		Accounts:           accounts,
+		OperationTypes:     opTypes,
+		Transactions:       &FakeTransactionRepo{},
+		Balances:           NewFakeBalanceRepo(),
+		TransactionManager: FakeTransactionManager{},
+	}, nil, nil, nil)
+
+	body := `{"account_id": 1, "operation_type_id": 3, "amount": 10}`
+	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	handler.CreateTransaction(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status %d, got %d (%s)", http.StatusUnprocessableEntity, w.Code, w.Body.String())
+	}
+	var problem adapterhttp.Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != "operation_type_inactive" {
+		t.Errorf("expected code operation_type_inactive, got %s", problem.Code)
+	}
+}
+
+func TestCreateTransaction_ExactAmounts(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "BRL", AvailableCreditLimitCents: 100000}
+
+	handler := adapterhttp.NewTransactionHandler(&usecase.CreateTransaction{
+		Accounts:           accounts,
+		OperationTypes:     NewFakeOperationTypeRepo(),
+		Transactions:       &FakeTransactionRepo{},
+		Balances:           NewFakeBalanceRepo(),
+		TransactionManager: FakeTransactionManager{},
+	}, nil, nil, nil)
+
+	tests := []struct {
+		name       string
+		amount     string
+		wantStatus int
+		wantCents  int64
+	}{
+		{"number that float truncates", `0.29`, http.StatusCreated, -29},
+		{"string", `"234.56"`, http.StatusCreated, -23456},
+		{"too many decimals", `0.291`, http.StatusBadRequest, 0},
+		{"exponent", `2.9e-1`, http.StatusBadRequest, 0},
+		{"not a number", `"abc"`, http.StatusBadRequest, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"account_id": 1, "operation_type_id": 1, "amount": ` + tt.amount + `}`
+			w := httptest.NewRecorder()
+			handler.CreateTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body)))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusCreated {
+				return
+			}
+
+			var resp adapterhttp.TransactionResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.AmountCents != tt.wantCents || resp.Amount != float64(tt.wantCents)/100 {
+				t.Errorf("expected %d cents, got amount %v and %d cents", tt.wantCents, resp.Amount, resp.AmountCents)
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_Currency(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	accounts.accounts[1] = domain.Account{ID: 1, DocumentNumber: "123", Currency: "JPY", AvailableCreditLimitCents: 100000}
+
+	handler := adapterhttp.NewTransactionHandler(&usecase.CreateTransaction{
+		Accounts:           accounts,
+		OperationTypes:     NewFakeOperationTypeRepo(),
+		Transactions:       &FakeTransactionRepo{},
+		Balances:           NewFakeBalanceRepo(),
+		TransactionManager: FakeTransactionManager{},
+	}, nil, nil, nil)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.CreateTransaction(w, httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body)))
+		return w
+	}
+
+	w := post(`{"account_id": 1, "operation_type_id": 1, "amount": 1500}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d (%s)", w.Code, w.Body.String())
+	}
+
+	var resp adapterhttp.TransactionResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Currency != "JPY" || resp.Amount != -1500 || resp.AmountCents != -1500 {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{"fraction of yen", `{"account_id": 1, "operation_type_id": 1, "amount": 1.5}`, http.StatusBadRequest},
+		{"unsupported currency", `{"account_id": 1, "operation_type_id": 1, "amount": 1, "currency": "XYZ"}`, http.StatusBadRequest},
+		{"currency mismatch", `{"account_id": 1, "operation_type_id": 1, "amount": 1, "currency": "USD"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := post(tt.body); w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// brokenAccountRepo fails every lookup with a driver-looking error.
+type brokenAccountRepo struct {
+	*FakeAccountRepo
+}
+
+func (brokenAccountRepo) FindByID(ctx context.Context, id int64) (domain.Account, error) {
+	return domain.Account{}, errors.New("failed to find account: pq: password authentication failed")
+}
+
+func TestProblemResponses(t *testing.T) {
+	accounts := NewFakeAccountRepo()
+	handler := newAccountHandler(accounts, NewFakeBalanceRepo())
+
+	broken := brokenAccountRepo{NewFakeAccountRepo()}
+	brokenHandler := adapterhttp.NewAccountHandler(nil, &usecase.GetAccount{Accounts: broken}, nil, nil, nil, nil, nil, nil)
+
+	tests := []struct {
+		name       string
+		serve      http.HandlerFunc
+		method     string
+		body       string
+		pathID     string
+		wantStatus int
+		wantCode   string
+		wantField  string
+	}{
+		{"malformed body", handler.CreateAccount, http.MethodPost, `{`, "", http.StatusBadRequest, "malformed_body", ""},
+		{"invalid document", handler.CreateAccount, http.MethodPost, `{"document_number": "123"}`, "", http.StatusBadRequest, "invalid_document_number", "document_number"},
+		{"limit precision", handler.CreateAccount, http.MethodPost, `{"document_number": "52998224725", "available_credit_limit": "1.001"}`, "", http.StatusBadRequest, "amount_precision", "available_credit_limit"},
+		{"invalid path id", handler.GetAccount, http.MethodGet, "", "abc", http.StatusBadRequest, "invalid_parameter", "accountID"},
+		{"not found", handler.GetAccount, http.MethodGet, "", "99", http.StatusNotFound, "account_not_found", ""},
+		{"internal error", brokenHandler.GetAccount, http.MethodGet, "", "1", http.StatusInternalServerError, "internal_error", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/accounts", bytes.NewBufferString(tt.body))
+			if tt.pathID != "" {
+				req.SetPathValue("accountID", tt.pathID)
+			}
+			w := httptest.NewRecorder()
+
+			tt.serve(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d (%s)", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != adapterhttp.ProblemContentType {
+				t.Errorf("expected content type %s, got %s", adapterhttp.ProblemContentType, ct)
+			}
+			if strings.Contains(w.Body.String(), "pq:") {
+				t.Errorf("internal error leaked: %s", w.Body.String())
+			}
+
+			var problem adapterhttp.Problem
+			if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Status != tt.wantStatus || problem.Code != tt.wantCode {
+				t.Errorf("expected %d %s, got %d %s", tt.wantStatus, tt.wantCode, problem.Status, problem.Code)
+			}
+			if problem.Title == "" {
+				t.Error("expected a title")
+			}
+
+			var field string
+			if len(problem.Errors) > 0 {
+				field = problem.Errors[0].Field
+			}
+			if field != tt.wantField {
+				t.Errorf("expected field %q, got %q", tt.wantField, field)
+			}
+		})
+	}
+}
+
+// recordingLogger keeps the fields of the last entry.
+type recordingLogger struct {
+	fields map[string]any
+}
+
+func (l *recordingLogger) Info(msg string, fields map[string]any)  { l.fields = fields }
+func (l *recordingLogger) Error(msg string, fields map[string]any) { l.fields = fields }
+
+func TestProblemResponses_CauseOnlyLogged(t *testing.T) {
+	log := &recordingLogger{}
+	handler := adapterhttp.WithLogging(log)(http.HandlerFunc(newAccountHandler(NewFakeAccountRepo(), NewFakeBalanceRepo()).ListAccounts))
+
+	// "x:1", whose timestamp fails to parse.
+	req := httptest.NewRequest(http.MethodGet, "/accounts?cursor=eDox", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	var problem adapterhttp.Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != "invalid_cursor" || problem.Detail != domain.ErrInvalidCursor.Error() {
+		t.Errorf("expected the fixed invalid_cursor detail, got %s %q", problem.Code, problem.Detail)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Message != problem.Detail {
+		t.Errorf("expected the field error to carry the same detail, got %+v", problem.Errors)
+	}
+	if cause, _ := log.fields["cause"].(string); !strings.Contains(cause, "strconv.ParseInt") {
+		t.Errorf("expected the parse error in the log, got %v", log.fields)
+	}
+}
+
+func TestProblemResponses_TraceID(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	defer otel.SetTracerProvider(previous)
+
+	handler := newAccountHandler(NewFakeAccountRepo(), NewFakeBalanceRepo())
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /accounts/{accountID}", handler.GetAccount)
+	server := adapterhttp.WithTracing("test")(mux)
+
+	w := httptest.NewRecorder()
+	server.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/accounts/99", nil))
+
+	var problem adapterhttp.Problem
+	if err := json.NewDecoder(w.Body).Decode(&problem); err != nil {
+		t.Fatal(err)
+	}
+	if len(problem.TraceID) != 32 {
+		t.Errorf("expected a trace id, got %q", problem.TraceID)
+	}
+}
@@ -0,0 +1,187 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	adapterhttp "github.com/nicolasmmb/pismo-challenge/internal/adapter/http"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/logger"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+// FakeIdempotencyRepo implements port.IdempotencyRepository
+type FakeIdempotencyRepo struct {
+	mu      sync.Mutex
+	records map[string]domain.IdempotencyRecord
+}
+
+func NewFakeIdempotencyRepo() *FakeIdempotencyRepo {
+	return &FakeIdempotencyRepo{records: make(map[string]domain.IdempotencyRecord)}
+}
+
+func (r *FakeIdempotencyRepo) Reserve(ctx context.Context, record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.records[record.Scope+record.Key]
+	if ok && existing.ExpiresAt.After(record.CreatedAt) {
+		return existing, false, nil
+	}
+	r.records[record.Scope+record.Key] = record
+	return record, true, nil
+}
+
+func (r *FakeIdempotencyRepo) Complete(ctx context.Context, record domain.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.records[record.Scope+record.Key]
+	if !ok || existing.Completed() || !existing.CreatedAt.Equal(record.CreatedAt) {
+		return domain.ErrConcurrentUpdate
+	}
+	r.records[record.Scope+record.Key] = record
+	return nil
+}
+
+func (r *FakeIdempotencyRepo) Release(ctx context.Context, record domain.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	existing, ok := r.records[record.Scope+record.Key]
+	if ok && !existing.Completed() && existing.CreatedAt.Equal(record.CreatedAt) {
+		delete(r.records, record.Scope+record.Key)
+	}
+	return nil
+}
+
+func TestWithIdempotency(t *testing.T) {
+	calls := 0
+	status := http.StatusCreated
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"call":` + strconv.Itoa(calls) + `}`))
+	})
+
+	store := NewFakeIdempotencyRepo()
+	handler := adapterhttp.WithIdempotency(store, time.Hour, time.Minute, logger.New())(next)
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(body))
+		if key != "" {
+			req.Header.Set(adapterhttp.IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("first request executes", func(t *testing.T) {
+		w := do("key-1", `{"amount": 10}`)
+		if w.Code != http.StatusCreated || calls != 1 {
+			t.Fatalf("expected 201 after one call, got %d after %d calls", w.Code, calls)
+		}
+	})
+
+	t.Run("retry replays stored response", func(t *testing.T) {
+		w := do("key-1", `{"amount": 10}`)
+		if calls != 1 {
+			t.Fatalf("handler must not run again, ran %d times", calls)
+		}
+		if w.Code != http.StatusCreated || w.Body.String() != `{"call":1}` {
+			t.Errorf("expected replay of first response, got %d %s", w.Code, w.Body.String())
+		}
+		if w.Header().Get(adapterhttp.IdempotentReplayedHeader) != "true" {
+			t.Error("expected replay header")
+		}
+	})
+
+	t.Run("different body is rejected", func(t *testing.T) {
+		w := do("key-1", `{"amount": 11}`)
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("expected status 422, got %d", w.Code)
+		}
+	})
+
+	t.Run("in progress key conflicts", func(t *testing.T) {
+		var nested int
+		slow := adapterhttp.WithIdempotency(store, time.Hour, time.Minute, logger.New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			retry := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(`{}`))
+			retry.Header.Set(adapterhttp.IdempotencyKeyHeader, "key-busy")
+			rw := httptest.NewRecorder()
+			handler.ServeHTTP(rw, retry)
+			nested = rw.Code
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(`{}`))
+		req.Header.Set(adapterhttp.IdempotencyKeyHeader, "key-busy")
+		slow.ServeHTTP(httptest.NewRecorder(), req)
+
+		if nested != http.StatusConflict {
+			t.Errorf("expected status 409, got %d", nested)
+		}
+	})
+
+	t.Run("in progress key holds a lease", func(t *testing.T) {
+		var reserved domain.IdempotencyRecord
+		leased := adapterhttp.WithIdempotency(store, time.Hour, time.Minute, logger.New())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reserved = store.records["POST /transactions"+"key-lease"]
+			w.WriteHeader(http.StatusCreated)
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewBufferString(`{}`))
+		req.Header.Set(adapterhttp.IdempotencyKeyHeader, "key-lease")
+		leased.ServeHTTP(httptest.NewRecorder(), req)
+
+		if lease := reserved.ExpiresAt.Sub(reserved.CreatedAt); lease != time.Minute {
+			t.Errorf("expected a one minute lease while in progress, got %s", lease)
+		}
+		done := store.records["POST /transactions"+"key-lease"]
+		if ttl := done.ExpiresAt.Sub(done.CreatedAt); !done.Completed() || ttl != time.Hour {
+			t.Errorf("expected the response kept for an hour, got %s", ttl)
+		}
+	})
+
+	t.Run("abandoned key is taken over after its lease", func(t *testing.T) {
+		// A request that never completed, as when the process died.
+		started := time.Now().Add(-2 * time.Minute)
+		store.records["POST /transactions"+"key-stuck"] = domain.IdempotencyRecord{
+			Scope:       "POST /transactions",
+			Key:         "key-stuck",
+			RequestHash: "abandoned",
+			CreatedAt:   started,
+			ExpiresAt:   started.Add(time.Minute),
+		}
+
+		before := calls
+		w := do("key-stuck", `{}`)
+		if w.Code != http.StatusCreated || calls != before+1 {
+			t.Errorf("expected the retry to execute, got %d after %d calls", w.Code, calls-before)
+		}
+	})
+
+	t.Run("server errors free the key", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		_ = do("key-2", `{}`)
+		status = http.StatusCreated
+		w := do("key-2", `{}`)
+		if w.Code != http.StatusCreated {
+			t.Errorf("expected retry to execute, got %d", w.Code)
+		}
+	})
+
+	t.Run("requests without key pass through", func(t *testing.T) {
+		before := calls
+		_ = do("", `{}`)
+		_ = do("", `{}`)
+		if calls != before+2 {
+			t.Errorf("expected 2 executions, got %d", calls-before)
+		}
+	})
+}
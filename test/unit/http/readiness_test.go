@@ -0,0 +1,49 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	adapterhttp "github.com/nicolasmmb/pismo-challenge/internal/adapter/http"
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/logger"
+)
+
+func TestReadiness(t *testing.T) {
+	var dbErr error
+	readiness := adapterhttp.NewReadiness(logger.New())
+	readiness.AddCheck("database", func(ctx context.Context) error { return dbErr })
+	readiness.AddCheck("cache", func(ctx context.Context) error { return nil })
+
+	probe := func(t *testing.T) (int, adapterhttp.ReadinessResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		readiness.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var resp adapterhttp.ReadinessResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := probe(t)
+	if code != http.StatusOK || resp.Status != "ready" || resp.Checks["database"] != "ok" || resp.Checks["cache"] != "ok" {
+		t.Errorf("expected ready, got %d %+v", code, resp)
+	}
+
+	dbErr = errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	code, resp = probe(t)
+	if code != http.StatusServiceUnavailable || resp.Status != "not_ready" || resp.Checks["database"] != "failing" || resp.Checks["cache"] != "ok" {
+		t.Errorf("expected the database check to fail, got %d %+v", code, resp)
+	}
+
+	dbErr = nil
+	readiness.Drain()
+	code, resp = probe(t)
+	if code != http.StatusServiceUnavailable || resp.Status != "draining" {
+		t.Errorf("expected draining, got %d %+v", code, resp)
+	}
+}
@@ -0,0 +1,75 @@
+package migration_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/migration"
+	"github.com/nicolasmmb/pismo-challenge/migrations"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_column.sql":      {Data: []byte("ALTER TABLE a ADD COLUMN b INT;")},
+		"002_add_column.down.sql": {Data: []byte("ALTER TABLE a DROP COLUMN b;")},
+		"001_create.sql":          {Data: []byte("CREATE TABLE a (id INT);")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+
+	got, err := migration.Load(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 migrations, got %d", len(got))
+	}
+
+	if got[0].Version != 1 || got[0].Name != "create" || got[0].Down != "" {
+		t.Errorf("unexpected first migration: %+v", got[0])
+	}
+	if got[1].Version != 2 || got[1].Name != "add_column" || got[1].Down != "ALTER TABLE a DROP COLUMN b;" {
+		t.Errorf("unexpected second migration: %+v", got[1])
+	}
+	if len(got[0].Checksum) != 64 || got[0].Checksum == got[1].Checksum {
+		t.Errorf("expected distinct sha256 checksums, got %q and %q", got[0].Checksum, got[1].Checksum)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"no version", fstest.MapFS{"create.sql": {}}},
+		{"zero version", fstest.MapFS{"000_create.sql": {}}},
+		{"duplicate version", fstest.MapFS{"001_a.sql": {}, "001_b.sql": {}}},
+		{"down without up", fstest.MapFS{"001_a.down.sql": {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := migration.Load(tt.fsys); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	got, err := migration.Load(migrations.FS)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for i, m := range got {
+		if m.Version != int64(i+1) {
+			t.Errorf("expected version %d, got %d (gap or duplicate)", i+1, m.Version)
+		}
+		if m.Down == "" {
+			t.Errorf("migration %03d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package publisher_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/publisher"
+	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+)
+
+func event(t *testing.T, id int64) domain.Event {
+	t.Helper()
+	e, err := domain.NewTransactionPosted(domain.Transaction{ID: 10, AccountID: 1, AmountCents: -500, Currency: "BRL"}, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.ID = id
+	return e
+}
+
+func TestWebhook(t *testing.T) {
+	var got publisher.Envelope
+	var header http.Header
+	status := http.StatusNoContent
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("invalid body %s: %v", body, err)
+		}
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	p := publisher.NewWebhook(srv.URL, srv.Client())
+
+	if err := p.Publish(context.Background(), event(t, 7)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != 7 || got.Type != "TransactionPosted" || got.AccountID != 1 {
+		t.Errorf("unexpected envelope %+v", got)
+	}
+	var data domain.TransactionPostedPayload
+	if err := json.Unmarshal(got.Data, &data); err != nil || data.TransactionID != 10 {
+		t.Errorf("unexpected data %s (%v)", got.Data, err)
+	}
+	if header.Get("X-Event-ID") != "7" || header.Get("X-Event-Type") != "TransactionPosted" {
+		t.Errorf("unexpected headers %v", header)
+	}
+
+	status = http.StatusServiceUnavailable
+	if err := p.Publish(context.Background(), event(t, 8)); err == nil {
+		t.Error("expected an error for a 503 answer")
+	}
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	p, err := publisher.OpenFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []int64{1, 2} {
+		if err := p.Publish(context.Background(), event(t, id)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var ids []int64
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e publisher.Envelope
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, e.ID)
+	}
+	if len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Errorf("expected events 1 and 2 in order, got %v", ids)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	p := publisher.NewWriter(&buf)
+	if err := p.Publish(context.Background(), event(t, 3)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) || bytes.Count(buf.Bytes(), []byte("\n")) != 1 {
+		t.Errorf("expected one line, got %q", buf.String())
+	}
+}
+
+func TestMemory(t *testing.T) {
+	p := publisher.NewMemory()
+	for _, id := range []int64{1, 2} {
+		_ = p.Publish(context.Background(), event(t, id))
+	}
+
+	events := p.Events()
+	if len(events) != 2 || events[0].ID != 1 || events[1].ID != 2 {
+		t.Errorf("unexpected events %+v", events)
+	}
+}
+
+func TestSignedSender(t *testing.T) {
+	var body []byte
+	var header http.Header
+	status := http.StatusOK
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	s := publisher.NewSignedSender(srv.Client())
+	sub := domain.WebhookSubscription{ID: 1, URL: srv.URL, Secret: "whsec_test", Active: true}
+	delivery := domain.NewWebhookDelivery(1, event(t, 7), time.Now())
+	delivery.ID = 42
+
+	code, err := s.Send(context.Background(), sub, delivery)
+	if err != nil || code != http.StatusOK {
+		t.Fatalf("expected 200, got %d (%v)", code, err)
+	}
+
+	var got publisher.Envelope
+	if err := json.Unmarshal(body, &got); err != nil || got.ID != 7 || got.Type != "TransactionPosted" {
+		t.Errorf("unexpected envelope %s (%v)", body, err)
+	}
+	if header.Get(publisher.WebhookIDHeader) != "42" || header.Get(publisher.WebhookEventHeader) != "TransactionPosted" {
+		t.Errorf("unexpected headers %v", header)
+	}
+
+	timestamp := header.Get(publisher.WebhookTimestampHeader)
+	if ts, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Errorf("unexpected timestamp %q", timestamp)
+	}
+	if sig := header.Get(publisher.WebhookSignatureHeader); sig != publisher.Sign("whsec_test", timestamp, body) {
+		t.Errorf("signature %q does not match the body", sig)
+	}
+	if publisher.Sign("whsec_other", timestamp, body) == header.Get(publisher.WebhookSignatureHeader) {
+		t.Error("expected the signature to depend on the secret")
+	}
+
+	status = http.StatusInternalServerError
+	if code, err := s.Send(context.Background(), sub, delivery); err == nil || code != http.StatusInternalServerError {
+		t.Errorf("expected an error with status 500, got %d (%v)", code, err)
+	}
+}
+
+func TestPublicTransport(t *testing.T) {
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	// The test server listens on loopback, like anything on the host itself.
+	s := publisher.NewSignedSender(&http.Client{Transport: publisher.NewPublicTransport()})
+	sub := domain.WebhookSubscription{ID: 1, URL: srv.URL, Secret: "whsec_test", Active: true}
+	delivery := domain.NewWebhookDelivery(1, event(t, 7), time.Now())
+
+	code, err := s.Send(context.Background(), sub, delivery)
+	if !errors.Is(err, domain.ErrWebhookTargetDenied) || code != 0 {
+		t.Errorf("expected ErrWebhookTargetDenied, got %d (%v)", code, err)
+	}
+	if called {
+		t.Error("expected the request not to reach the server")
+	}
+}
+
+func TestSign(t *testing.T) {
+	// Known answer, computed with:
+	// printf '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := publisher.Sign("secret", "1700000000", []byte("{}")); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+func TestMulti(t *testing.T) {
+	first, second := publisher.NewMemory(), publisher.NewMemory()
+	p := publisher.NewMulti(first, second)
+	if err := p.Publish(context.Background(), event(t, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if len(first.Events()) != 1 || len(second.Events()) != 1 {
+		t.Errorf("expected the event in both publishers, got %d and %d", len(first.Events()), len(second.Events()))
+	}
+
+	failing := publisher.NewWebhook("http://127.0.0.1:0", nil)
+	p = publisher.NewMulti(failing, second)
+	if err := p.Publish(context.Background(), event(t, 2)); err == nil {
+		t.Error("expected the failure to be returned")
+	}
+	if len(second.Events()) != 1 {
+		t.Error("expected publishers after a failure to be skipped")
+	}
+}
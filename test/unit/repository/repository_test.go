@@ -14,8 +14,10 @@ import (
 	"github.com/ory/dockertest/v3/docker"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/nicolasmmb/pismo-challenge/internal/adapter/migration"
 	"github.com/nicolasmmb/pismo-challenge/internal/adapter/repository"
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/migrations"
 )
 
 var db *sql.DB
@@ -78,33 +80,12 @@ func TestMain(m *testing.M) {
 }
 
 func runMigrations(db *sql.DB) error {
-	queries := []string{
-		`CREATE TABLE IF NOT EXISTS accounts (
-			id BIGSERIAL PRIMARY KEY,
-			document_number TEXT UNIQUE NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		);`,
-		`CREATE TABLE IF NOT EXISTS operation_types (
-			id INT PRIMARY KEY,
-			description TEXT NOT NULL,
-			sign SMALLINT NOT NULL CHECK (sign IN (-1, 1))
-		);`,
-		`CREATE TABLE IF NOT EXISTS transactions (
-			id BIGSERIAL PRIMARY KEY,
-			account_id BIGINT NOT NULL REFERENCES accounts(id),
-			operation_type_id INT NOT NULL REFERENCES operation_types(id),
-			amount_cents BIGINT NOT NULL,
-			event_date TIMESTAMPTZ NOT NULL,
-			created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
-		);`,
-	}
-
-	for _, q := range queries {
-		if _, err := db.Exec(q); err != nil {
-			return err
-		}
+	runner, err := migration.NewRunner(db, migrations.FS)
+	if err != nil {
+		return err
 	}
-	return nil
+	_, err = runner.Up(context.Background())
+	return err
 }
 
 func TestAccountRepository(t *testing.T) {
@@ -123,16 +104,145 @@ func TestAccountRepository(t *testing.T) {
 		assert.Equal(t, acc.DocumentNumber, fetched.DocumentNumber)
 	})
 
+	t.Run("Update Available Credit Limit", func(t *testing.T) {
+		id, err := repo.Create(ctx, domain.Account{DocumentNumber: "55555555500", AvailableCreditLimitCents: 10000})
+		assert.NoError(t, err)
+
+		err = repo.UpdateAvailableCreditLimit(ctx, id, 2500)
+		assert.NoError(t, err)
+
+		fetched, err := repo.FindByID(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2500), fetched.AvailableCreditLimitCents)
+
+		err = repo.UpdateAvailableCreditLimit(ctx, 999999, 1)
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+	})
+
+	t.Run("Status And History", func(t *testing.T) {
+		id, err := repo.Create(ctx, domain.Account{DocumentNumber: "12345678909"})
+		assert.NoError(t, err)
+
+		fetched, err := repo.FindByID(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AccountStatusActive, fetched.Status)
+
+		assert.NoError(t, repo.UpdateStatus(ctx, id, domain.AccountStatusBlocked))
+		fetched, err = repo.FindByID(ctx, id)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.AccountStatusBlocked, fetched.Status)
+
+		changes := repository.NewAccountStatusChangeRepository(db)
+		_, err = changes.Create(ctx, domain.AccountStatusChange{AccountID: id, From: domain.AccountStatusActive, To: domain.AccountStatusBlocked, Reason: "lost card", CreatedAt: time.Now()})
+		assert.NoError(t, err)
+		_, err = changes.Create(ctx, domain.AccountStatusChange{AccountID: 999999, From: domain.AccountStatusActive, To: domain.AccountStatusBlocked, Reason: "x", CreatedAt: time.Now()})
+		assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+		history, err := changes.ListByAccount(ctx, id)
+		assert.NoError(t, err)
+		if assert.Len(t, history, 1) {
+			assert.Equal(t, "lost card", history[0].Reason)
+			assert.Equal(t, domain.AccountStatusBlocked, history[0].To)
+		}
+
+		assert.ErrorIs(t, repo.UpdateStatus(ctx, 999999, domain.AccountStatusClosed), domain.ErrAccountNotFound)
+	})
+
+	t.Run("List Accounts", func(t *testing.T) {
+		base := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+		var ids []int64
+		for i, doc := range []string{"LIST_A", "LIST_B", "LIST_C"} {
+			id, err := repo.Create(ctx, domain.Account{DocumentNumber: doc, CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+			assert.NoError(t, err)
+			ids = append(ids, id)
+		}
+		assert.NoError(t, repo.UpdateStatus(ctx, ids[1], domain.AccountStatusBlocked))
+
+		byDocument, err := repo.List(ctx, domain.AccountFilter{DocumentNumber: "LIST_B", Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, byDocument, 1) {
+			assert.Equal(t, ids[1], byDocument[0].ID)
+		}
+
+		newest, err := repo.List(ctx, domain.AccountFilter{From: base, Limit: 2})
+		assert.NoError(t, err)
+		if assert.Len(t, newest, 2) {
+			assert.Equal(t, []int64{ids[2], ids[1]}, []int64{newest[0].ID, newest[1].ID})
+		}
+
+		cursor := domain.Cursor{Time: newest[1].CreatedAt, ID: newest[1].ID}
+		rest, err := repo.List(ctx, domain.AccountFilter{From: base, After: &cursor, Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, rest, 1) {
+			assert.Equal(t, ids[0], rest[0].ID)
+		}
+
+		blocked, err := repo.List(ctx, domain.AccountFilter{Status: domain.AccountStatusBlocked, From: base, Ascending: true, Limit: 10})
+		assert.NoError(t, err)
+		if assert.Len(t, blocked, 1) {
+			assert.Equal(t, ids[1], blocked[0].ID)
+		}
+	})
+
 	t.Run("Create Duplicate Account", func(t *testing.T) {
 		acc := domain.Account{DocumentNumber: "99999999900"}
 		_, err := repo.Create(ctx, acc)
 		assert.NoError(t, err)
 
 		_, err = repo.Create(ctx, acc)
-		assert.Error(t, err) // Unique constraint violation
+		assert.ErrorIs(t, err, domain.ErrDocumentExists)
 	})
 }
 
+func TestTransactionRepository_ConstraintErrors(t *testing.T) {
+	ctx := context.Background()
+	accountID, err := repository.NewAccountRepository(db).Create(ctx, domain.Account{DocumentNumber: "FK_TEST"})
+	assert.NoError(t, err)
+
+	repo := repository.NewTransactionRepository(db)
+
+	_, err = repo.Create(ctx, domain.Transaction{AccountID: 999999, OperationTypeID: 4, AmountCents: 100, EventDate: time.Now()})
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+	_, err = repo.Create(ctx, domain.Transaction{AccountID: accountID, OperationTypeID: 99, AmountCents: 100, EventDate: time.Now()})
+	assert.ErrorIs(t, err, domain.ErrOperationTypeNotFound)
+}
+
+func TestOperationTypeRepository(t *testing.T) {
+	repo := repository.NewOperationTypeRepository(db)
+	ctx := context.Background()
+
+	reversal, err := repo.FindByID(ctx, domain.OperationTypeDebitReversal)
+	assert.NoError(t, err)
+	assert.True(t, reversal.Active)
+	assert.False(t, reversal.Reversible)
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	id, err := repo.Create(ctx, domain.OperationType{Description: "ANNUAL FEE", Sign: -1, Reversible: true, Active: true, CreatedAt: now, UpdatedAt: now})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, id, domain.FirstNumberedOperationType)
+
+	_, err = repo.Create(ctx, domain.OperationType{ID: id, Description: "DUPLICATE", Sign: -1, CreatedAt: now})
+	assert.ErrorIs(t, err, domain.ErrOperationTypeExists)
+
+	err = repo.Update(ctx, domain.OperationType{ID: id, Description: "ANNUAL FEE", Active: false, UpdatedAt: now})
+	assert.NoError(t, err)
+
+	fetched, err := repo.FindByIDForUpdate(ctx, id)
+	assert.NoError(t, err)
+	assert.False(t, fetched.Active)
+	assert.False(t, fetched.AffectsCreditLimit)
+	assert.Equal(t, -1, fetched.Sign)
+
+	types, err := repo.List(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.OperationTypeNormalPurchase, types[0].ID)
+	assert.Equal(t, id, types[len(types)-1].ID)
+
+	err = repo.Update(ctx, domain.OperationType{ID: 999999, Description: "MISSING"})
+	assert.ErrorIs(t, err, domain.ErrOperationTypeNotFound)
+}
+
 func TestTransactionLocking(t *testing.T) {
 	repo := repository.NewAccountRepository(db)
 	acc := domain.Account{DocumentNumber: "LOCK_TEST"}
@@ -173,3 +283,331 @@ func TestTransactionLocking(t *testing.T) {
 	// If lock worked, goroutine 2 should have waited at least ~900ms (1s minus overhead)
 	assert.GreaterOrEqual(t, duration.Milliseconds(), int64(800), "Transaction 2 should have waited for lock")
 }
+
+func TestIdempotencyRepository_Lease(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewIdempotencyRepository(db)
+
+	started := time.Now().Add(-2 * time.Minute).Truncate(time.Microsecond)
+	stuck := domain.IdempotencyRecord{Scope: "POST /transactions", Key: "lease", RequestHash: "a", CreatedAt: started, ExpiresAt: started.Add(time.Minute)}
+	_, reserved, err := repo.Reserve(ctx, stuck)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	// The lease ran out, so a retry takes the key over.
+	now := time.Now().Truncate(time.Microsecond)
+	retry := domain.IdempotencyRecord{Scope: stuck.Scope, Key: stuck.Key, RequestHash: "a", CreatedAt: now, ExpiresAt: now.Add(time.Minute)}
+	_, reserved, err = repo.Reserve(ctx, retry)
+	assert.NoError(t, err)
+	assert.True(t, reserved)
+
+	// The first request can neither complete nor free the key any more.
+	stuck.StatusCode = 201
+	assert.ErrorIs(t, repo.Complete(ctx, stuck), domain.ErrConcurrentUpdate)
+	assert.NoError(t, repo.Release(ctx, stuck))
+
+	retry.StatusCode = 201
+	retry.ExpiresAt = now.Add(time.Hour)
+	assert.NoError(t, repo.Complete(ctx, retry))
+
+	existing, reserved, err := repo.Reserve(ctx, domain.IdempotencyRecord{Scope: stuck.Scope, Key: stuck.Key, RequestHash: "a", CreatedAt: now.Add(2 * time.Minute), ExpiresAt: now.Add(3 * time.Minute)})
+	assert.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, 201, existing.StatusCode)
+}
+
+func TestAuthorizationRepository(t *testing.T) {
+	ctx := context.Background()
+	accountID, err := repository.NewAccountRepository(db).Create(ctx, domain.Account{DocumentNumber: "AUTH_TEST"})
+	assert.NoError(t, err)
+
+	repo := repository.NewAuthorizationRepository(db)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	newAuth := func(expiresAt time.Time) domain.Authorization {
+		return domain.Authorization{
+			AccountID:       accountID,
+			OperationTypeID: domain.OperationTypeNormalPurchase,
+			AmountCents:     6000,
+			Currency:        "BRL",
+			Status:          domain.AuthorizationStatusPending,
+			ExpiresAt:       expiresAt,
+			CreatedAt:       now,
+		}
+	}
+
+	expiredID, err := repo.Create(ctx, newAuth(now.Add(-time.Minute)))
+	assert.NoError(t, err)
+	openID, err := repo.Create(ctx, newAuth(now.Add(time.Hour)))
+	assert.NoError(t, err)
+
+	_, err = repo.Create(ctx, domain.Authorization{AccountID: 999999, OperationTypeID: 1, AmountCents: 1, Currency: "BRL", Status: domain.AuthorizationStatusPending, ExpiresAt: now, CreatedAt: now})
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+	due, err := repo.FindExpired(ctx, now, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, expiredID, due[0].ID)
+		assert.Equal(t, int64(6000), due[0].AmountCents)
+	}
+
+	balances := repository.NewAccountBalanceRepository(db)
+	assert.NoError(t, balances.Apply(ctx, accountID, 0, 12000))
+	rebuilt, err := balances.Rebuild(ctx, accountID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12000), rebuilt.PendingCents)
+
+	txID, err := repository.NewTransactionRepository(db).Create(ctx, domain.Transaction{AccountID: accountID, OperationTypeID: 1, AmountCents: -4500, EventDate: now, CreatedAt: now})
+	assert.NoError(t, err)
+
+	auth, err := repo.FindByIDForUpdate(ctx, openID)
+	assert.NoError(t, err)
+	auth.Status = domain.AuthorizationStatusCaptured
+	auth.CapturedCents = 4500
+	auth.TransactionID = txID
+	auth.UpdatedAt = now
+	assert.NoError(t, repo.Close(ctx, auth))
+	assert.ErrorIs(t, repo.Close(ctx, auth), domain.ErrAuthorizationClosed)
+
+	fetched, err := repo.FindByID(ctx, openID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AuthorizationStatusCaptured, fetched.Status)
+	assert.Equal(t, txID, fetched.TransactionID)
+
+	expired, err := repo.FindByID(ctx, expiredID)
+	assert.NoError(t, err)
+	assert.Zero(t, expired.TransactionID)
+
+	_, err = repo.FindByID(ctx, 999999)
+	assert.ErrorIs(t, err, domain.ErrAuthorizationNotFound)
+}
+
+func TestOutboxRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewOutboxRepository(db)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	// Two events for one account and one for another.
+	for _, accountID := range []int64{900001, 900001, 900002} {
+		event, err := domain.NewTransactionPosted(domain.Transaction{AccountID: accountID, AmountCents: -100, Currency: "BRL"}, now)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Add(ctx, event))
+	}
+
+	claim := func(ctx context.Context) []domain.Event {
+		events, err := repo.ClaimPending(ctx, now, now.Add(time.Minute), 10)
+		assert.NoError(t, err)
+		return events
+	}
+
+	first := claim(ctx)
+	if !assert.Len(t, first, 2) {
+		return
+	}
+	assert.Equal(t, int64(900001), first[0].AccountID)
+	assert.Equal(t, int64(900002), first[1].AccountID)
+
+	// The lease outlives the claim: the claimed heads, and the rest of their
+	// account's queue, are skipped until they are published or the lease ends.
+	assert.Empty(t, claim(ctx))
+	lateRelay, err := repo.ClaimPending(ctx, now.Add(time.Minute), now.Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Len(t, lateRelay, 2)
+
+	assert.NoError(t, repo.MarkFailed(ctx, first[0].ID, 1, now.Add(time.Minute), "unavailable"))
+	assert.NoError(t, repo.MarkPublished(ctx, first[1].ID, now))
+
+	// The failed head waits for its retry and still holds back its account.
+	assert.Empty(t, claim(ctx))
+
+	assert.NoError(t, repo.MarkPublished(ctx, first[0].ID, now))
+	next := claim(ctx)
+	if assert.Len(t, next, 1) {
+		assert.Equal(t, int64(900001), next[0].AccountID)
+		assert.Greater(t, next[0].ID, first[0].ID)
+	}
+	assert.NoError(t, repo.MarkPublished(ctx, next[0].ID, now))
+
+	// Watchers read an account's events whether published or not.
+	latest, err := repo.LatestID(ctx, 900001, domain.EventTransactionPosted)
+	assert.NoError(t, err)
+	assert.Equal(t, next[0].ID, latest)
+	latest, err = repo.LatestID(ctx, 900002, domain.EventTransactionPosted)
+	assert.NoError(t, err)
+	assert.Equal(t, first[1].ID, latest)
+	latest, err = repo.LatestID(ctx, 900003, domain.EventTransactionPosted)
+	assert.NoError(t, err)
+	assert.Zero(t, latest)
+
+	watched, err := repo.ListByAccount(ctx, 900001, domain.EventTransactionPosted, first[0].ID, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, watched, 1) {
+		assert.Equal(t, next[0].ID, watched[0].ID)
+	}
+	watched, err = repo.ListByAccount(ctx, 900001, domain.EventAccountCreated, 0, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, watched)
+
+	// A dead head stops holding back its account.
+	for range 2 {
+		event, err := domain.NewTransactionPosted(domain.Transaction{AccountID: 900003, AmountCents: -100, Currency: "BRL"}, now)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.Add(ctx, event))
+	}
+	poisoned := claim(ctx)
+	if assert.Len(t, poisoned, 1) {
+		assert.NoError(t, repo.MarkDead(ctx, poisoned[0].ID, 20, now, "rejected"))
+		after := claim(ctx)
+		if assert.Len(t, after, 1) {
+			assert.Equal(t, int64(900003), after[0].AccountID)
+			assert.Greater(t, after[0].ID, poisoned[0].ID)
+			assert.NoError(t, repo.MarkPublished(ctx, after[0].ID, now))
+		}
+	}
+	assert.Empty(t, claim(ctx))
+}
+
+func TestWebhookRepositories(t *testing.T) {
+	ctx := context.Background()
+	accountID, err := repository.NewAccountRepository(db).Create(ctx, domain.Account{DocumentNumber: "WEBHOOK_TEST"})
+	assert.NoError(t, err)
+
+	subs := repository.NewWebhookSubscriptionRepository(db)
+	deliveries := repository.NewWebhookDeliveryRepository(db)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	allID, err := subs.Create(ctx, domain.WebhookSubscription{URL: "https://all.example", Secret: "whsec_a", Active: true, CreatedAt: now})
+	assert.NoError(t, err)
+	scopedID, err := subs.Create(ctx, domain.WebhookSubscription{
+		URL:        "https://scoped.example",
+		Secret:     "whsec_b",
+		EventTypes: []domain.EventType{domain.EventTransactionPosted},
+		AccountID:  accountID,
+		Active:     true,
+		CreatedAt:  now,
+	})
+	assert.NoError(t, err)
+
+	_, err = subs.Create(ctx, domain.WebhookSubscription{URL: "https://x.example", Secret: "s", AccountID: 999999, Active: true, CreatedAt: now})
+	assert.ErrorIs(t, err, domain.ErrAccountNotFound)
+
+	scoped, err := subs.FindByID(ctx, scopedID)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.EventType{domain.EventTransactionPosted}, scoped.EventTypes)
+	assert.Equal(t, accountID, scoped.AccountID)
+
+	ids := func(list []domain.WebhookSubscription) []int64 {
+		var out []int64
+		for _, s := range list {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	posted := domain.Event{ID: 1, Type: domain.EventTransactionPosted, AccountID: accountID, Payload: []byte(`{}`), CreatedAt: now}
+	matching, err := subs.FindMatching(ctx, posted)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{allID, scopedID}, ids(matching))
+
+	created := domain.Event{ID: 2, Type: domain.EventAccountCreated, AccountID: accountID, Payload: []byte(`{}`), CreatedAt: now}
+	matching, err = subs.FindMatching(ctx, created)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{allID}, ids(matching))
+
+	// Enqueueing the same event twice keeps one delivery.
+	for range 2 {
+		assert.NoError(t, deliveries.Enqueue(ctx, domain.NewWebhookDelivery(scopedID, posted, now)))
+	}
+	assert.NoError(t, deliveries.Enqueue(ctx, domain.NewWebhookDelivery(scopedID, created, now.Add(time.Minute))))
+
+	log, err := deliveries.ListBySubscription(ctx, domain.WebhookDeliveryFilter{SubscriptionID: scopedID, Limit: 10})
+	assert.NoError(t, err)
+	if !assert.Len(t, log, 2) {
+		return
+	}
+	assert.Equal(t, created.ID, log[0].EventID)
+	assert.JSONEq(t, `{}`, string(log[0].Payload))
+
+	claimed, err := deliveries.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, claimed, 1) {
+		return
+	}
+	assert.Equal(t, posted.ID, claimed[0].EventID)
+
+	// A claimed delivery is not due again until its lease ends.
+	again, err := deliveries.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, again)
+
+	// A redelivery while the claimed delivery is being sent wins over the
+	// send's result.
+	redelivered := claimed[0]
+	redelivered.NextAttemptAt = now
+	assert.NoError(t, deliveries.Update(ctx, redelivered, claimed[0].NextAttemptAt))
+
+	d := claimed[0]
+	d.Status = domain.WebhookDeliveryDelivered
+	d.Attempts = 1
+	d.LastStatusCode = 200
+	d.DeliveredAt = now
+	assert.ErrorIs(t, deliveries.Update(ctx, d, claimed[0].NextAttemptAt), domain.ErrConcurrentUpdate)
+
+	claimed, err = deliveries.ClaimDue(ctx, now, now.Add(2*time.Minute), 10)
+	assert.NoError(t, err)
+	if !assert.Len(t, claimed, 1) {
+		return
+	}
+	assert.NoError(t, deliveries.Update(ctx, d, claimed[0].NextAttemptAt))
+
+	got, err := deliveries.FindByID(ctx, d.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.WebhookDeliveryDelivered, got.Status)
+	assert.True(t, got.DeliveredAt.Equal(now))
+
+	pending, err := deliveries.ListBySubscription(ctx, domain.WebhookDeliveryFilter{SubscriptionID: scopedID, Status: domain.WebhookDeliveryPending, Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, pending, 1)
+
+	assert.NoError(t, subs.Deactivate(ctx, scopedID, now))
+	matching, err = subs.FindMatching(ctx, posted)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{allID}, ids(matching))
+	assert.ErrorIs(t, subs.Deactivate(ctx, 999999, now), domain.ErrWebhookNotFound)
+
+	_, err = deliveries.FindByID(ctx, 999999)
+	assert.ErrorIs(t, err, domain.ErrDeliveryNotFound)
+	assert.ErrorIs(t, deliveries.Update(ctx, domain.WebhookDelivery{ID: 999999}, now), domain.ErrDeliveryNotFound)
+
+	// Leave no active catch-all subscription behind for the other tests.
+	assert.NoError(t, subs.Deactivate(ctx, allID, now))
+}
+
+func TestMigrationRunner(t *testing.T) {
+	ctx := context.Background()
+	runner, err := migration.NewRunner(db, migrations.FS)
+	assert.NoError(t, err)
+
+	statuses, err := runner.Status(ctx)
+	assert.NoError(t, err)
+	for _, s := range statuses {
+		assert.True(t, s.Applied, "migration %d should be applied", s.Version)
+		assert.False(t, s.Modified)
+	}
+
+	applied, err := runner.Up(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// Cycling the latest migration drops what it created; it runs after the
+	// tests that use it.
+	reverted, err := runner.Down(ctx, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, reverted, 1) {
+		assert.Equal(t, statuses[len(statuses)-1].Version, reverted[0].Version)
+	}
+
+	applied, err = runner.Up(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+}
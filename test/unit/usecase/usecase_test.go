@@ -2,11 +2,18 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/nicolasmmb/pismo-challenge/internal/domain"
+	"github.com/nicolasmmb/pismo-challenge/internal/port"
 	"github.com/nicolasmmb/pismo-challenge/internal/usecase"
 )
 
@@ -14,11 +21,20 @@ import (
 // MOCKS
 // =============================================================================
 
+// cents is an amount given in centavos.
+func cents(v int64) domain.Decimal {
+	return domain.Decimal{Units: v, Scale: 2}
+}
+
 // mockAccountRepo is a mock for AccountRepository.
 type mockAccountRepo struct {
 	createFn          func(ctx context.Context, acc domain.Account) (int64, error)
 	findByIDFn        func(ctx context.Context, id int64) (domain.Account, error)
 	findByIDForUpdate func(ctx context.Context, id int64) (domain.Account, error)
+	updateLimitFn     func(ctx context.Context, id int64, limitCents int64) error
+	listAfterFn       func(ctx context.Context, afterID int64, limit int) ([]domain.Account, error)
+	updateStatusFn    func(ctx context.Context, id int64, status domain.AccountStatus) error
+	listFn            func(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error)
 }
 
 func (m *mockAccountRepo) Create(ctx context.Context, acc domain.Account) (int64, error) {
@@ -32,40 +48,128 @@ func (m *mockAccountRepo) FindByID(ctx context.Context, id int64) (domain.Accoun
 	if m.findByIDFn != nil {
 		return m.findByIDFn(ctx, id)
 	}
-	return domain.Account{ID: id, DocumentNumber: "12345678900"}, nil
+	return domain.Account{ID: id, DocumentNumber: "52998224725", Currency: "BRL", Status: domain.AccountStatusActive}, nil
 }
 
 func (m *mockAccountRepo) FindByIDForUpdate(ctx context.Context, id int64) (domain.Account, error) {
 	if m.findByIDForUpdate != nil {
 		return m.findByIDForUpdate(ctx, id)
 	}
-	return domain.Account{ID: id, DocumentNumber: "12345678900"}, nil
+	return domain.Account{ID: id, DocumentNumber: "52998224725", AvailableCreditLimitCents: 1000000, Currency: "BRL", Status: domain.AccountStatusActive}, nil
+}
+
+func (m *mockAccountRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]domain.Account, error) {
+	if m.listAfterFn != nil {
+		return m.listAfterFn(ctx, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) List(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockAccountRepo) UpdateAvailableCreditLimit(ctx context.Context, id int64, limitCents int64) error {
+	if m.updateLimitFn != nil {
+		return m.updateLimitFn(ctx, id, limitCents)
+	}
+	return nil
+}
+
+func (m *mockAccountRepo) UpdateStatus(ctx context.Context, id int64, status domain.AccountStatus) error {
+	if m.updateStatusFn != nil {
+		return m.updateStatusFn(ctx, id, status)
+	}
+	return nil
+}
+
+// mockStatusChangeRepo is a mock for AccountStatusChangeRepository.
+type mockStatusChangeRepo struct {
+	createFn func(ctx context.Context, change domain.AccountStatusChange) (int64, error)
+	listFn   func(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error)
+}
+
+func (m *mockStatusChangeRepo) Create(ctx context.Context, change domain.AccountStatusChange) (int64, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, change)
+	}
+	return 1, nil
+}
+
+func (m *mockStatusChangeRepo) ListByAccount(ctx context.Context, accountID int64) ([]domain.AccountStatusChange, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, accountID)
+	}
+	return nil, nil
 }
 
 // mockOperationTypeRepo is a mock for OperationTypeRepository.
 type mockOperationTypeRepo struct {
-	findByIDFn func(ctx context.Context, id int) (domain.OperationType, error)
+	findByIDFn          func(ctx context.Context, id int) (domain.OperationType, error)
+	findByIDForUpdateFn func(ctx context.Context, id int) (domain.OperationType, error)
+	listFn              func(ctx context.Context) ([]domain.OperationType, error)
+	createFn            func(ctx context.Context, ot domain.OperationType) (int, error)
+	updateFn            func(ctx context.Context, ot domain.OperationType) error
 }
 
 func (m *mockOperationTypeRepo) FindByID(ctx context.Context, id int) (domain.OperationType, error) {
 	if m.findByIDFn != nil {
 		return m.findByIDFn(ctx, id)
 	}
-	// Default: debit operations (1,2,3) have Sign=-1, credit (4) has Sign=+1
+	// Default: debit operations (1,2,3,6) have Sign=-1, credits (4,5) have Sign=+1
 	sign := -1
-	if id == domain.OperationTypeCreditVoucher {
+	if id == domain.OperationTypeCreditVoucher || id == domain.OperationTypeDebitReversal {
 		sign = 1
 	}
-	return domain.OperationType{ID: id, Sign: sign}, nil
+	return domain.OperationType{ID: id, Sign: sign, AffectsCreditLimit: true, Reversible: true, Active: true}, nil
+}
+
+func (m *mockOperationTypeRepo) FindByIDForUpdate(ctx context.Context, id int) (domain.OperationType, error) {
+	if m.findByIDForUpdateFn != nil {
+		return m.findByIDForUpdateFn(ctx, id)
+	}
+	return m.FindByID(ctx, id)
+}
+
+func (m *mockOperationTypeRepo) List(ctx context.Context) ([]domain.OperationType, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx)
+	}
+	return nil, nil
 }
 
-func (m *mockOperationTypeRepo) SeedDefaults(ctx context.Context) error {
+func (m *mockOperationTypeRepo) Create(ctx context.Context, ot domain.OperationType) (int, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, ot)
+	}
+	if ot.ID == 0 {
+		return 100, nil
+	}
+	return ot.ID, nil
+}
+
+func (m *mockOperationTypeRepo) Update(ctx context.Context, ot domain.OperationType) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, ot)
+	}
 	return nil
 }
 
 // mockTransactionRepo is a mock for TransactionRepository.
 type mockTransactionRepo struct {
-	createFn func(ctx context.Context, tx domain.Transaction) (int64, error)
+	createFn         func(ctx context.Context, tx domain.Transaction) (int64, error)
+	findByIDFn       func(ctx context.Context, id int64) (domain.Transaction, error)
+	findOpenDebitsFn func(ctx context.Context, accountID int64) ([]domain.Transaction, error)
+	updateBalanceFn  func(ctx context.Context, id int64, balanceCents int64) error
+	listByAccountFn  func(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error)
+	sumReversalsFn   func(ctx context.Context, id int64) (int64, error)
+	installmentsFn   func(ctx context.Context, parentID int64) ([]domain.Transaction, error)
+	findDueFn        func(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error)
+	markPostedFn     func(ctx context.Context, id int64, balanceCents int64) error
+	sumByPeriodFn    func(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error)
 }
 
 func (m *mockTransactionRepo) Create(ctx context.Context, tx domain.Transaction) (int64, error) {
@@ -75,6 +179,390 @@ func (m *mockTransactionRepo) Create(ctx context.Context, tx domain.Transaction)
 	return 1, nil
 }
 
+func (m *mockTransactionRepo) FindByID(ctx context.Context, id int64) (domain.Transaction, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return domain.Transaction{ID: id, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: -1000, Currency: "BRL", Status: domain.TransactionStatusPosted}, nil
+}
+
+func (m *mockTransactionRepo) FindOpenDebitsForUpdate(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+	if m.findOpenDebitsFn != nil {
+		return m.findOpenDebitsFn(ctx, accountID)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepo) UpdateBalance(ctx context.Context, id int64, balanceCents int64) error {
+	if m.updateBalanceFn != nil {
+		return m.updateBalanceFn(ctx, id, balanceCents)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepo) ListByAccount(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+	if m.listByAccountFn != nil {
+		return m.listByAccountFn(ctx, filter)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepo) ListInstallments(ctx context.Context, parentID int64) ([]domain.Transaction, error) {
+	if m.installmentsFn != nil {
+		return m.installmentsFn(ctx, parentID)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepo) FindDueScheduled(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error) {
+	if m.findDueFn != nil {
+		return m.findDueFn(ctx, asOf, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockTransactionRepo) MarkPosted(ctx context.Context, id int64, balanceCents int64) error {
+	if m.markPostedFn != nil {
+		return m.markPostedFn(ctx, id, balanceCents)
+	}
+	return nil
+}
+
+func (m *mockTransactionRepo) SumByPeriod(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error) {
+	if m.sumByPeriodFn != nil {
+		return m.sumByPeriodFn(ctx, accountID, from, to)
+	}
+	return 0, 0, nil
+}
+
+func (m *mockTransactionRepo) SumReversals(ctx context.Context, id int64) (int64, error) {
+	if m.sumReversalsFn != nil {
+		return m.sumReversalsFn(ctx, id)
+	}
+	return 0, nil
+}
+
+// mockStatementRepo is a mock for StatementRepository.
+type mockStatementRepo struct {
+	statements []domain.Statement
+}
+
+func (m *mockStatementRepo) Create(ctx context.Context, statement domain.Statement) (int64, error) {
+	statement.ID = int64(len(m.statements) + 1)
+	m.statements = append(m.statements, statement)
+	return statement.ID, nil
+}
+
+func (m *mockStatementRepo) FindByID(ctx context.Context, id int64) (domain.Statement, error) {
+	if id < 1 || id > int64(len(m.statements)) {
+		return domain.Statement{}, domain.ErrStatementNotFound
+	}
+	return m.statements[id-1], nil
+}
+
+func (m *mockStatementRepo) FindLatest(ctx context.Context, accountID int64) (domain.Statement, error) {
+	for i := len(m.statements) - 1; i >= 0; i-- {
+		if m.statements[i].AccountID == accountID {
+			return m.statements[i], nil
+		}
+	}
+	return domain.Statement{}, domain.ErrStatementNotFound
+}
+
+func (m *mockStatementRepo) ListByAccount(ctx context.Context, accountID int64) ([]domain.Statement, error) {
+	var out []domain.Statement
+	for i := len(m.statements) - 1; i >= 0; i-- {
+		if m.statements[i].AccountID == accountID {
+			out = append(out, m.statements[i])
+		}
+	}
+	return out, nil
+}
+
+// mockAuthorizationRepo is a mock for AuthorizationRepository. By default it
+// finds a pending hold of 60.00 on account 1.
+type mockAuthorizationRepo struct {
+	createFn      func(ctx context.Context, auth domain.Authorization) (int64, error)
+	findByIDFn    func(ctx context.Context, id int64) (domain.Authorization, error)
+	closeFn       func(ctx context.Context, auth domain.Authorization) error
+	findExpiredFn func(ctx context.Context, asOf time.Time, limit int) ([]domain.Authorization, error)
+}
+
+func (m *mockAuthorizationRepo) Create(ctx context.Context, auth domain.Authorization) (int64, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, auth)
+	}
+	return 1, nil
+}
+
+func (m *mockAuthorizationRepo) FindByID(ctx context.Context, id int64) (domain.Authorization, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return domain.Authorization{
+		ID:                  id,
+		AccountID:           1,
+		OperationTypeID:     domain.OperationTypeNormalPurchase,
+		AmountCents:         6000,
+		Currency:            "BRL",
+		ReservesCreditLimit: true,
+		Status:              domain.AuthorizationStatusPending,
+		ExpiresAt:           time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func (m *mockAuthorizationRepo) FindByIDForUpdate(ctx context.Context, id int64) (domain.Authorization, error) {
+	return m.FindByID(ctx, id)
+}
+
+func (m *mockAuthorizationRepo) Close(ctx context.Context, auth domain.Authorization) error {
+	if m.closeFn != nil {
+		return m.closeFn(ctx, auth)
+	}
+	return nil
+}
+
+func (m *mockAuthorizationRepo) FindExpired(ctx context.Context, asOf time.Time, limit int) ([]domain.Authorization, error) {
+	if m.findExpiredFn != nil {
+		return m.findExpiredFn(ctx, asOf, limit)
+	}
+	return nil, nil
+}
+
+// mockOutboxRepo is a mock for OutboxRepository that keeps what it is given.
+type mockOutboxRepo struct {
+	added     []domain.Event
+	addErr    error
+	claimFn   func(ctx context.Context, asOf, until time.Time, limit int) ([]domain.Event, error)
+	published []int64
+	failed    map[int64]domain.Event
+	dead      map[int64]domain.Event
+	listFn    func(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error)
+	latestFn  func(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error)
+}
+
+func (m *mockOutboxRepo) Add(ctx context.Context, event domain.Event) error {
+	if m.addErr != nil {
+		return m.addErr
+	}
+	m.added = append(m.added, event)
+	return nil
+}
+
+func (m *mockOutboxRepo) ClaimPending(ctx context.Context, asOf, until time.Time, limit int) ([]domain.Event, error) {
+	if m.claimFn != nil {
+		return m.claimFn(ctx, asOf, until, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockOutboxRepo) MarkPublished(ctx context.Context, id int64, at time.Time) error {
+	m.published = append(m.published, id)
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkFailed(ctx context.Context, id int64, attempts int, nextAttemptAt time.Time, lastError string) error {
+	if m.failed == nil {
+		m.failed = make(map[int64]domain.Event)
+	}
+	m.failed[id] = domain.Event{ID: id, Attempts: attempts, NextAttemptAt: nextAttemptAt, LastError: lastError}
+	return nil
+}
+
+func (m *mockOutboxRepo) MarkDead(ctx context.Context, id int64, attempts int, at time.Time, lastError string) error {
+	if m.dead == nil {
+		m.dead = make(map[int64]domain.Event)
+	}
+	m.dead[id] = domain.Event{ID: id, Attempts: attempts, LastError: lastError}
+	return nil
+}
+
+func (m *mockOutboxRepo) ListByAccount(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, accountID, eventType, afterID, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockOutboxRepo) LatestID(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error) {
+	if m.latestFn != nil {
+		return m.latestFn(ctx, accountID, eventType)
+	}
+	return 0, nil
+}
+
+// mockPublisher is a mock for EventPublisher.
+type mockPublisher struct {
+	publishFn func(ctx context.Context, event domain.Event) error
+}
+
+func (m *mockPublisher) Publish(ctx context.Context, event domain.Event) error {
+	if m.publishFn != nil {
+		return m.publishFn(ctx, event)
+	}
+	return nil
+}
+
+// mockLogger keeps the messages logged as errors.
+type mockLogger struct {
+	errors []string
+}
+
+func (m *mockLogger) Info(msg string, fields map[string]any) {}
+
+func (m *mockLogger) Error(msg string, fields map[string]any) {
+	m.errors = append(m.errors, msg)
+}
+
+// mockWebhookRepo is a mock for WebhookSubscriptionRepository. Without
+// findByIDFn every subscription exists and is active.
+type mockWebhookRepo struct {
+	created    []domain.WebhookSubscription
+	findByIDFn func(ctx context.Context, id int64) (domain.WebhookSubscription, error)
+	matchingFn func(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error)
+}
+
+func (m *mockWebhookRepo) Create(ctx context.Context, sub domain.WebhookSubscription) (int64, error) {
+	m.created = append(m.created, sub)
+	return int64(len(m.created)), nil
+}
+
+func (m *mockWebhookRepo) FindByID(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return domain.WebhookSubscription{ID: id, URL: "https://partner.example/hooks", Secret: "whsec_test", Active: true}, nil
+}
+
+func (m *mockWebhookRepo) List(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return m.created, nil
+}
+
+func (m *mockWebhookRepo) Deactivate(ctx context.Context, id int64, at time.Time) error {
+	return nil
+}
+
+func (m *mockWebhookRepo) FindMatching(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error) {
+	if m.matchingFn != nil {
+		return m.matchingFn(ctx, event)
+	}
+	return nil, nil
+}
+
+// mockDeliveryRepo is a mock for WebhookDeliveryRepository. It records what
+// was enqueued and updated, and the next attempt each update expected;
+// updates of the deliveries in conflicts fail with ErrConcurrentUpdate.
+type mockDeliveryRepo struct {
+	enqueued   []domain.WebhookDelivery
+	updated    map[int64]domain.WebhookDelivery
+	seen       map[int64]time.Time
+	conflicts  map[int64]bool
+	findByIDFn func(ctx context.Context, id int64) (domain.WebhookDelivery, error)
+	claimFn    func(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error)
+}
+
+func (m *mockDeliveryRepo) Enqueue(ctx context.Context, d domain.WebhookDelivery) error {
+	m.enqueued = append(m.enqueued, d)
+	return nil
+}
+
+func (m *mockDeliveryRepo) FindByID(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return domain.WebhookDelivery{}, domain.ErrDeliveryNotFound
+}
+
+func (m *mockDeliveryRepo) ListBySubscription(ctx context.Context, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m *mockDeliveryRepo) ClaimDue(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	if m.claimFn != nil {
+		return m.claimFn(ctx, asOf, until, limit)
+	}
+	return nil, nil
+}
+
+func (m *mockDeliveryRepo) Update(ctx context.Context, d domain.WebhookDelivery, seen time.Time) error {
+	if m.seen == nil {
+		m.seen = make(map[int64]time.Time)
+	}
+	m.seen[d.ID] = seen
+	if m.conflicts[d.ID] {
+		return domain.ErrConcurrentUpdate
+	}
+	if m.updated == nil {
+		m.updated = make(map[int64]domain.WebhookDelivery)
+	}
+	m.updated[d.ID] = d
+	return nil
+}
+
+// mockResolver is a mock for Resolver, answering from hosts.
+type mockResolver struct {
+	hosts map[string][]netip.Addr
+}
+
+func (m *mockResolver) LookupNetIP(ctx context.Context, network, host string) ([]netip.Addr, error) {
+	addrs, ok := m.hosts[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+// mockSender is a mock for WebhookSender.
+type mockSender struct {
+	sent   []int64
+	sendFn func(ctx context.Context, sub domain.WebhookSubscription, d domain.WebhookDelivery) (int, error)
+}
+
+func (m *mockSender) Send(ctx context.Context, sub domain.WebhookSubscription, d domain.WebhookDelivery) (int, error) {
+	m.sent = append(m.sent, d.ID)
+	if m.sendFn != nil {
+		return m.sendFn(ctx, sub, d)
+	}
+	return http.StatusOK, nil
+}
+
+// mockClock always returns the same instant.
+type mockClock struct {
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	return c.now
+}
+
+// mockBalanceRepo is a mock for AccountBalanceRepository.
+type mockBalanceRepo struct {
+	findFn    func(ctx context.Context, accountID int64) (domain.AccountBalance, error)
+	applyFn   func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error
+	rebuildFn func(ctx context.Context, accountID int64) (domain.AccountBalance, error)
+}
+
+func (m *mockBalanceRepo) FindByAccountID(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	if m.findFn != nil {
+		return m.findFn(ctx, accountID)
+	}
+	return domain.AccountBalance{AccountID: accountID}, nil
+}
+
+func (m *mockBalanceRepo) Apply(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+	if m.applyFn != nil {
+		return m.applyFn(ctx, accountID, postedDelta, pendingDelta)
+	}
+	return nil
+}
+
+func (m *mockBalanceRepo) Rebuild(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+	if m.rebuildFn != nil {
+		return m.rebuildFn(ctx, accountID)
+	}
+	return domain.AccountBalance{AccountID: accountID}, nil
+}
+
 // mockTransactionManager is a mock for TransactionManager.
 type mockTransactionManager struct {
 	runFn func(ctx context.Context, fn func(ctx context.Context) error) error
@@ -88,6 +576,19 @@ func (m *mockTransactionManager) RunInTransaction(ctx context.Context, fn func(c
 	return fn(ctx)
 }
 
+// mockDocuments is a mock for DocumentValidator. By default it accepts any
+// number unchanged.
+type mockDocuments struct {
+	normalizeFn func(country, number string) (string, error)
+}
+
+func (m *mockDocuments) Normalize(country, number string) (string, error) {
+	if m.normalizeFn != nil {
+		return m.normalizeFn(country, number)
+	}
+	return number, nil
+}
+
 // =============================================================================
 // CreateAccount Tests
 // =============================================================================
@@ -96,20 +597,30 @@ func TestCreateAccount_Execute(t *testing.T) {
 	tests := []struct {
 		name           string
 		documentNumber string
+		limit          *domain.Decimal
+		wantLimit      int64
 		setupRepo      func(*mockAccountRepo)
 		wantErr        error
 		wantID         int64
 	}{
 		{
 			name:           "success - creates account with valid document",
-			documentNumber: "12345678900",
+			documentNumber: "52998224725",
 			setupRepo: func(m *mockAccountRepo) {
 				m.createFn = func(ctx context.Context, acc domain.Account) (int64, error) {
 					return 42, nil
 				}
 			},
-			wantErr: nil,
-			wantID:  42,
+			wantErr:   nil,
+			wantID:    42,
+			wantLimit: 100000,
+		},
+		{
+			name:           "success - zero credit limit is kept",
+			documentNumber: "52998224725",
+			limit:          &domain.Decimal{},
+			wantID:         1,
+			wantLimit:      0,
 		},
 		{
 			name:           "error - empty document number",
@@ -118,9 +629,17 @@ func TestCreateAccount_Execute(t *testing.T) {
 			wantErr:        usecase.ErrInvalidDocument,
 			wantID:         0,
 		},
+		{
+			name:           "error - negative credit limit",
+			documentNumber: "52998224725",
+			limit:          &domain.Decimal{Units: -1, Scale: 2},
+			setupRepo:      nil,
+			wantErr:        usecase.ErrInvalidCreditLimit,
+			wantID:         0,
+		},
 		{
 			name:           "error - repository fails",
-			documentNumber: "12345678900",
+			documentNumber: "52998224725",
 			setupRepo: func(m *mockAccountRepo) {
 				m.createFn = func(ctx context.Context, acc domain.Account) (int64, error) {
 					return 0, errors.New("db connection failed")
@@ -139,10 +658,14 @@ func TestCreateAccount_Execute(t *testing.T) {
 			}
 
 			uc := usecase.CreateAccount{
-				Accounts: repo,
+				Accounts:  repo,
+				Documents: &mockDocuments{},
 			}
 
-			acc, err := uc.Execute(context.Background(), tt.documentNumber)
+			acc, err := uc.Execute(context.Background(), usecase.CreateAccountInput{
+				DocumentNumber:       tt.documentNumber,
+				AvailableCreditLimit: tt.limit,
+			})
 
 			if tt.wantErr != nil {
 				if err == nil {
@@ -162,6 +685,10 @@ func TestCreateAccount_Execute(t *testing.T) {
 				t.Errorf("expected ID %d, got %d", tt.wantID, acc.ID)
 			}
 
+			if acc.AvailableCreditLimitCents != tt.wantLimit {
+				t.Errorf("expected limit %d cents, got %d", tt.wantLimit, acc.AvailableCreditLimitCents)
+			}
+
 			if acc.DocumentNumber != tt.documentNumber {
 				t.Errorf("expected DocumentNumber %s, got %s", tt.documentNumber, acc.DocumentNumber)
 			}
@@ -174,35 +701,282 @@ func TestCreateAccount_Execute(t *testing.T) {
 	}
 }
 
+func TestCreateAccount_Document(t *testing.T) {
+	tests := []struct {
+		name        string
+		country     string
+		wantCountry string
+		wantDoc     string
+		wantErr     error
+	}{
+		{"defaults to BR", "", "BR", "52998224725", nil},
+		{"explicit country", "AR", "AR", "52998224725", nil},
+		{"rejected by validator", "XX", "XX", "", domain.ErrInvalidDocumentNumber},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotCountry string
+			var stored domain.Account
+			uc := usecase.CreateAccount{
+				Accounts: &mockAccountRepo{
+					createFn: func(ctx context.Context, acc domain.Account) (int64, error) {
+						stored = acc
+						return 1, nil
+					},
+				},
+				Documents: &mockDocuments{
+					normalizeFn: func(country, number string) (string, error) {
+						gotCountry = country
+						if country == "XX" {
+							return "", domain.ErrInvalidDocumentNumber
+						}
+						return "52998224725", nil
+					},
+				},
+			}
+
+			_, err := uc.Execute(context.Background(), usecase.CreateAccountInput{
+				DocumentNumber:  "529.982.247-25",
+				DocumentCountry: tt.country,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if gotCountry != tt.wantCountry {
+				t.Errorf("expected country %s, got %s", tt.wantCountry, gotCountry)
+			}
+			if stored.DocumentNumber != tt.wantDoc {
+				t.Errorf("expected stored document %q, got %q", tt.wantDoc, stored.DocumentNumber)
+			}
+		})
+	}
+}
+
 // =============================================================================
-// GetAccount Tests
+// Account Status Tests
 // =============================================================================
 
-func TestGetAccount_Execute(t *testing.T) {
+func TestChangeAccountStatus_Execute(t *testing.T) {
 	tests := []struct {
-		name      string
-		accountID int64
-		setupRepo func(*mockAccountRepo)
-		wantErr   error
-		wantAcc   domain.Account
+		name    string
+		from    domain.AccountStatus
+		input   usecase.ChangeAccountStatusInput
+		wantErr error
+		want    domain.AccountStatus
 	}{
-		{
-			name:      "success - finds existing account",
-			accountID: 1,
-			setupRepo: func(m *mockAccountRepo) {
-				m.findByIDFn = func(ctx context.Context, id int64) (domain.Account, error) {
-					return domain.Account{
-						ID:             1,
-						DocumentNumber: "12345678900",
-					}, nil
-				}
-			},
-			wantErr: nil,
-			wantAcc: domain.Account{ID: 1, DocumentNumber: "12345678900"},
-		},
-		{
-			name:      "error - account not found",
-			accountID: 999,
+		{"block active", domain.AccountStatusActive, usecase.ChangeAccountStatusInput{Status: "blocked", Reason: "suspected fraud"}, nil, domain.AccountStatusBlocked},
+		{"unblock", domain.AccountStatusBlocked, usecase.ChangeAccountStatusInput{Status: "ACTIVE", Reason: "cleared"}, nil, domain.AccountStatusActive},
+		{"close active", domain.AccountStatusActive, usecase.ChangeAccountStatusInput{Status: "CLOSED", Reason: "customer request"}, nil, domain.AccountStatusClosed},
+		{"close blocked", domain.AccountStatusBlocked, usecase.ChangeAccountStatusInput{Status: "CLOSED", Reason: "fraud confirmed"}, nil, domain.AccountStatusClosed},
+		{"reopen closed", domain.AccountStatusClosed, usecase.ChangeAccountStatusInput{Status: "ACTIVE", Reason: "mistake"}, domain.ErrStatusTransition, ""},
+		{"block blocked", domain.AccountStatusBlocked, usecase.ChangeAccountStatusInput{Status: "BLOCKED", Reason: "again"}, domain.ErrStatusTransition, ""},
+		{"unknown status", domain.AccountStatusActive, usecase.ChangeAccountStatusInput{Status: "FROZEN", Reason: "x"}, domain.ErrInvalidAccountStatus, ""},
+		{"missing reason", domain.AccountStatusActive, usecase.ChangeAccountStatusInput{Status: "BLOCKED", Reason: "  "}, usecase.ErrInvalidReason, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved domain.AccountStatus
+			var recorded []domain.AccountStatusChange
+			accRepo := &mockAccountRepo{
+				findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{ID: id, Currency: "BRL", Status: tt.from}, nil
+				},
+				updateStatusFn: func(ctx context.Context, id int64, status domain.AccountStatus) error {
+					saved = status
+					return nil
+				},
+			}
+			changes := &mockStatusChangeRepo{
+				createFn: func(ctx context.Context, change domain.AccountStatusChange) (int64, error) {
+					recorded = append(recorded, change)
+					return 1, nil
+				},
+			}
+
+			uc := usecase.ChangeAccountStatus{Accounts: accRepo, StatusChanges: changes, TransactionManager: &mockTransactionManager{}}
+			acc, err := uc.Execute(context.Background(), 1, tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if saved != "" || len(recorded) != 0 {
+					t.Error("nothing must be written when the change is rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if acc.Status != tt.want || saved != tt.want {
+				t.Errorf("expected status %s, got %s (saved %s)", tt.want, acc.Status, saved)
+			}
+			if len(recorded) != 1 || recorded[0].From != tt.from || recorded[0].To != tt.want || recorded[0].Reason != strings.TrimSpace(tt.input.Reason) {
+				t.Errorf("unexpected history %+v", recorded)
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_AccountStatus(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          domain.AccountStatus
+		operationTypeID int
+		wantErr         error
+	}{
+		{"blocked rejects debits", domain.AccountStatusBlocked, domain.OperationTypeNormalPurchase, domain.ErrAccountBlocked},
+		{"blocked accepts credits", domain.AccountStatusBlocked, domain.OperationTypeCreditVoucher, nil},
+		{"closed rejects debits", domain.AccountStatusClosed, domain.OperationTypeWithdrawal, domain.ErrAccountClosed},
+		{"closed rejects credits", domain.AccountStatusClosed, domain.OperationTypeCreditVoucher, domain.ErrAccountClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accRepo := &mockAccountRepo{
+				findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{ID: id, AvailableCreditLimitCents: 100000, Currency: "BRL", Status: tt.status}, nil
+				},
+			}
+
+			uc := usecase.CreateTransaction{
+				Accounts:           accRepo,
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       &mockTransactionRepo{},
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			_, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 1, OperationTypeID: tt.operationTypeID, Amount: cents(1000)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListAccounts_Execute(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	stored := make([]domain.Account, 3)
+	for i := range stored {
+		stored[i] = domain.Account{ID: int64(3 - i), CreatedAt: base.Add(-time.Duration(i) * time.Hour)}
+	}
+	stripDots := func(country, number string) (string, error) {
+		return strings.NewReplacer(".", "", "-", "").Replace(number), nil
+	}
+
+	tests := []struct {
+		name       string
+		input      usecase.ListAccountsInput
+		wantErr    error
+		wantFilter domain.AccountFilter
+		wantLen    int
+		wantNextID int64
+	}{
+		{
+			name:       "full page returns cursor to last item",
+			input:      usecase.ListAccountsInput{AccountFilter: domain.AccountFilter{Limit: 2}},
+			wantFilter: domain.AccountFilter{Limit: 3},
+			wantLen:    2,
+			wantNextID: 2,
+		},
+		{
+			name:       "document is normalized and status upper-cased",
+			input:      usecase.ListAccountsInput{AccountFilter: domain.AccountFilter{DocumentNumber: "529.982.247-25", Status: "blocked"}},
+			wantFilter: domain.AccountFilter{DocumentNumber: "52998224725", Status: domain.AccountStatusBlocked, Limit: usecase.DefaultPageSize + 1},
+			wantLen:    3,
+		},
+		{
+			name:    "unknown status",
+			input:   usecase.ListAccountsInput{AccountFilter: domain.AccountFilter{Status: "FROZEN"}},
+			wantErr: domain.ErrInvalidAccountStatus,
+		},
+		{
+			name:    "inverted date range",
+			input:   usecase.ListAccountsInput{AccountFilter: domain.AccountFilter{From: base, To: base.Add(-time.Hour)}},
+			wantErr: usecase.ErrInvalidDateRange,
+		},
+		{
+			name:    "limit above maximum",
+			input:   usecase.ListAccountsInput{AccountFilter: domain.AccountFilter{Limit: usecase.MaxPageSize + 1}},
+			wantErr: usecase.ErrInvalidPageSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got domain.AccountFilter
+			accRepo := &mockAccountRepo{
+				listFn: func(ctx context.Context, filter domain.AccountFilter) ([]domain.Account, error) {
+					got = filter
+					if len(stored) > filter.Limit {
+						return stored[:filter.Limit], nil
+					}
+					return stored, nil
+				},
+			}
+			uc := usecase.ListAccounts{Accounts: accRepo, Documents: &mockDocuments{normalizeFn: stripDots}}
+
+			page, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.wantFilter {
+				t.Errorf("expected filter %+v, got %+v", tt.wantFilter, got)
+			}
+			if len(page.Accounts) != tt.wantLen {
+				t.Errorf("expected %d accounts, got %d", tt.wantLen, len(page.Accounts))
+			}
+			switch {
+			case tt.wantNextID == 0 && page.Next != nil:
+				t.Errorf("expected no cursor, got %+v", page.Next)
+			case tt.wantNextID != 0 && (page.Next == nil || page.Next.ID != tt.wantNextID):
+				t.Errorf("expected cursor at %d, got %+v", tt.wantNextID, page.Next)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// GetAccount Tests
+// =============================================================================
+
+func TestGetAccount_Execute(t *testing.T) {
+	tests := []struct {
+		name      string
+		accountID int64
+		setupRepo func(*mockAccountRepo)
+		wantErr   error
+		wantAcc   domain.Account
+	}{
+		{
+			name:      "success - finds existing account",
+			accountID: 1,
+			setupRepo: func(m *mockAccountRepo) {
+				m.findByIDFn = func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{
+						ID:             1,
+						DocumentNumber: "52998224725",
+					}, nil
+				}
+			},
+			wantErr: nil,
+			wantAcc: domain.Account{ID: 1, DocumentNumber: "52998224725"},
+		},
+		{
+			name:      "error - account not found",
+			accountID: 999,
 			setupRepo: func(m *mockAccountRepo) {
 				m.findByIDFn = func(ctx context.Context, id int64) (domain.Account, error) {
 					return domain.Account{}, usecase.ErrNotFound
@@ -348,12 +1122,25 @@ func TestCreateTransaction_Execute(t *testing.T) {
 			amountCents:     10000,
 			setupMocks: func(accRepo *mockAccountRepo, opRepo *mockOperationTypeRepo, txRepo *mockTransactionRepo, txMgr *mockTransactionManager) {
 				opRepo.findByIDFn = func(ctx context.Context, id int) (domain.OperationType, error) {
-					return domain.OperationType{ID: 1, Sign: 0}, nil // Invalid sign
+					return domain.OperationType{ID: 1, Sign: 0, Active: true}, nil // Invalid sign
 				}
 			},
 			wantErr:    usecase.ErrInvalidOperation,
 			wantAmount: 0,
 		},
+		{
+			name:            "error - inactive operation type",
+			accountID:       1,
+			operationTypeID: domain.OperationTypeWithdrawal,
+			amountCents:     10000,
+			setupMocks: func(accRepo *mockAccountRepo, opRepo *mockOperationTypeRepo, txRepo *mockTransactionRepo, txMgr *mockTransactionManager) {
+				opRepo.findByIDFn = func(ctx context.Context, id int) (domain.OperationType, error) {
+					return domain.OperationType{ID: id, Sign: -1, AffectsCreditLimit: true, Reversible: true}, nil
+				}
+			},
+			wantErr:    domain.ErrOperationTypeInactive,
+			wantAmount: 0,
+		},
 		{
 			name:            "error - transaction create fails",
 			accountID:       1,
@@ -397,10 +1184,15 @@ func TestCreateTransaction_Execute(t *testing.T) {
 				Accounts:           accRepo,
 				OperationTypes:     opRepo,
 				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
 				TransactionManager: txMgr,
 			}
 
-			tx, err := uc.Execute(context.Background(), tt.accountID, tt.operationTypeID, tt.amountCents)
+			tx, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{
+				AccountID:       tt.accountID,
+				OperationTypeID: tt.operationTypeID,
+				Amount:          cents(tt.amountCents),
+			})
 
 			if tt.wantErr != nil {
 				if err == nil {
@@ -435,3 +1227,2501 @@ func TestCreateTransaction_Execute(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateTransaction_Discharge(t *testing.T) {
+	tests := []struct {
+		name        string
+		amountCents int64
+		openDebits  []domain.Transaction
+		wantUpdates map[int64]int64
+		wantBalance int64
+	}{
+		{
+			name:        "credit without open debits keeps full balance",
+			amountCents: 6000,
+			openDebits:  nil,
+			wantUpdates: map[int64]int64{},
+			wantBalance: 6000,
+		},
+		{
+			name:        "credit pays oldest debits first",
+			amountCents: 6000,
+			openDebits: []domain.Transaction{
+				{ID: 1, BalanceCents: -5000},
+				{ID: 2, BalanceCents: -2350},
+				{ID: 3, BalanceCents: -1880},
+			},
+			wantUpdates: map[int64]int64{1: 0, 2: -1350},
+			wantBalance: 0,
+		},
+		{
+			name:        "credit larger than debts keeps the leftover",
+			amountCents: 10000,
+			openDebits: []domain.Transaction{
+				{ID: 1, BalanceCents: -5000},
+				{ID: 2, BalanceCents: -2350},
+			},
+			wantUpdates: map[int64]int64{1: 0, 2: 0},
+			wantBalance: 2650,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := map[int64]int64{}
+			txRepo := &mockTransactionRepo{
+				findOpenDebitsFn: func(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+					return tt.openDebits, nil
+				},
+				updateBalanceFn: func(ctx context.Context, id int64, balanceCents int64) error {
+					updates[id] = balanceCents
+					return nil
+				},
+			}
+
+			uc := usecase.CreateTransaction{
+				Accounts:           &mockAccountRepo{},
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			tx, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 1, OperationTypeID: domain.OperationTypeCreditVoucher, Amount: cents(tt.amountCents)})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tx.BalanceCents != tt.wantBalance {
+				t.Errorf("expected BalanceCents %d, got %d", tt.wantBalance, tx.BalanceCents)
+			}
+
+			if len(updates) != len(tt.wantUpdates) {
+				t.Fatalf("expected %d balance updates, got %d (%v)", len(tt.wantUpdates), len(updates), updates)
+			}
+			for id, want := range tt.wantUpdates {
+				if got, ok := updates[id]; !ok || got != want {
+					t.Errorf("expected transaction %d balance %d, got %d", id, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_DebitIsNotDischarged(t *testing.T) {
+	txRepo := &mockTransactionRepo{
+		findOpenDebitsFn: func(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+			t.Fatal("debits must not look up open debits")
+			return nil, nil
+		},
+	}
+
+	uc := usecase.CreateTransaction{
+		Accounts:           &mockAccountRepo{},
+		OperationTypes:     &mockOperationTypeRepo{},
+		Transactions:       txRepo,
+		Balances:           &mockBalanceRepo{},
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	tx, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, Amount: cents(5000)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.BalanceCents != -5000 {
+		t.Errorf("expected BalanceCents -5000, got %d", tx.BalanceCents)
+	}
+}
+
+// =============================================================================
+// Account Balance Tests
+// =============================================================================
+
+func TestCreateTransaction_AppliesBalance(t *testing.T) {
+	var gotAccount, gotPosted, gotPending int64
+	balances := &mockBalanceRepo{
+		applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+			gotAccount, gotPosted, gotPending = accountID, postedDelta, pendingDelta
+			return nil
+		},
+	}
+
+	uc := usecase.CreateTransaction{
+		Accounts:           &mockAccountRepo{},
+		OperationTypes:     &mockOperationTypeRepo{},
+		Transactions:       &mockTransactionRepo{},
+		Balances:           balances,
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	if _, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 7, OperationTypeID: domain.OperationTypeWithdrawal, Amount: cents(2500)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if gotAccount != 7 || gotPosted != -2500 || gotPending != 0 {
+		t.Errorf("expected Apply(7, -2500, 0), got Apply(%d, %d, %d)", gotAccount, gotPosted, gotPending)
+	}
+}
+
+func TestGetAccountBalance_Execute(t *testing.T) {
+	tests := []struct {
+		name          string
+		setupMocks    func(*mockAccountRepo, *mockBalanceRepo)
+		wantErr       error
+		wantAvailable int64
+	}{
+		{
+			name: "success - returns projection",
+			setupMocks: func(accRepo *mockAccountRepo, balances *mockBalanceRepo) {
+				balances.findFn = func(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+					return domain.AccountBalance{AccountID: accountID, PostedCents: 10000, PendingCents: 2500}, nil
+				}
+			},
+			wantAvailable: 7500,
+		},
+		{
+			name: "error - account not found",
+			setupMocks: func(accRepo *mockAccountRepo, balances *mockBalanceRepo) {
+				accRepo.findByIDFn = func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{}, domain.ErrAccountNotFound
+				}
+			},
+			wantErr: domain.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accRepo := &mockAccountRepo{}
+			balances := &mockBalanceRepo{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(accRepo, balances)
+			}
+
+			uc := usecase.GetAccountBalance{Accounts: accRepo, Balances: balances}
+
+			balance, err := uc.Execute(context.Background(), 1)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if balance.AvailableCents() != tt.wantAvailable {
+				t.Errorf("expected available %d, got %d", tt.wantAvailable, balance.AvailableCents())
+			}
+		})
+	}
+}
+
+func TestRebuildAccountBalance_Execute(t *testing.T) {
+	locked := false
+	accRepo := &mockAccountRepo{
+		findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+			locked = true
+			return domain.Account{ID: id}, nil
+		},
+	}
+	balances := &mockBalanceRepo{
+		rebuildFn: func(ctx context.Context, accountID int64) (domain.AccountBalance, error) {
+			if !locked {
+				t.Fatal("rebuild must run after the account is locked")
+			}
+			return domain.AccountBalance{AccountID: accountID, PostedCents: -4200}, nil
+		},
+	}
+
+	uc := usecase.RebuildAccountBalance{
+		Accounts:           accRepo,
+		Balances:           balances,
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	balance, err := uc.Execute(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if balance.PostedCents != -4200 {
+		t.Errorf("expected posted -4200, got %d", balance.PostedCents)
+	}
+}
+
+// =============================================================================
+// Credit Limit Tests
+// =============================================================================
+
+func TestCreateTransaction_CreditLimit(t *testing.T) {
+	tests := []struct {
+		name            string
+		limitCents      int64
+		operationTypeID int
+		amountCents     int64
+		wantErr         error
+		wantLimit       int64
+	}{
+		{
+			name:            "debit within limit decrements it",
+			limitCents:      10000,
+			operationTypeID: domain.OperationTypeNormalPurchase,
+			amountCents:     4000,
+			wantLimit:       6000,
+		},
+		{
+			name:            "debit consuming the whole limit",
+			limitCents:      10000,
+			operationTypeID: domain.OperationTypeWithdrawal,
+			amountCents:     10000,
+			wantLimit:       0,
+		},
+		{
+			name:            "debit above limit is rejected",
+			limitCents:      10000,
+			operationTypeID: domain.OperationTypeNormalPurchase,
+			amountCents:     10001,
+			wantErr:         domain.ErrInsufficientFunds,
+		},
+		{
+			name:            "credit voucher restores limit",
+			limitCents:      1000,
+			operationTypeID: domain.OperationTypeCreditVoucher,
+			amountCents:     2500,
+			wantLimit:       3500,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLimit := int64(-1)
+			created := false
+			accRepo := &mockAccountRepo{
+				findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{ID: id, AvailableCreditLimitCents: tt.limitCents, Currency: "BRL"}, nil
+				},
+				updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+					gotLimit = limitCents
+					return nil
+				},
+			}
+			txRepo := &mockTransactionRepo{
+				createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) {
+					created = true
+					return 1, nil
+				},
+			}
+
+			uc := usecase.CreateTransaction{
+				Accounts:           accRepo,
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			_, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 1, OperationTypeID: tt.operationTypeID, Amount: cents(tt.amountCents)})
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				if created {
+					t.Error("transaction must not be created when the limit is exceeded")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotLimit != tt.wantLimit {
+				t.Errorf("expected limit %d, got %d", tt.wantLimit, gotLimit)
+			}
+		})
+	}
+}
+
+func TestCreateTransaction_LimitExemptOperationType(t *testing.T) {
+	limitUpdated := false
+	accRepo := &mockAccountRepo{
+		findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+			return domain.Account{ID: id, AvailableCreditLimitCents: 100, Currency: "BRL"}, nil
+		},
+		updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+			limitUpdated = true
+			return nil
+		},
+	}
+	opRepo := &mockOperationTypeRepo{
+		findByIDFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+			return domain.OperationType{ID: id, Sign: -1, Active: true}, nil
+		},
+	}
+
+	uc := usecase.CreateTransaction{
+		Accounts:           accRepo,
+		OperationTypes:     opRepo,
+		Transactions:       &mockTransactionRepo{},
+		Balances:           &mockBalanceRepo{},
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	// The fee is above the available limit, but the type does not touch it.
+	tx, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{AccountID: 1, OperationTypeID: 100, Amount: cents(500)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tx.AmountCents != -500 {
+		t.Errorf("expected AmountCents -500, got %d", tx.AmountCents)
+	}
+	if limitUpdated {
+		t.Error("credit limit must not change for a limit-exempt operation type")
+	}
+}
+
+func TestUpdateCreditLimit_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		limitCents int64
+		setupRepo  func(*mockAccountRepo)
+		wantErr    error
+	}{
+		{
+			name:       "success - replaces limit",
+			limitCents: 50000,
+		},
+		{
+			name:       "error - negative limit",
+			limitCents: -100,
+			wantErr:    usecase.ErrInvalidCreditLimit,
+		},
+		{
+			name:       "error - account not found",
+			limitCents: 50000,
+			setupRepo: func(m *mockAccountRepo) {
+				m.findByIDForUpdate = func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{}, domain.ErrAccountNotFound
+				}
+			},
+			wantErr: domain.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &mockAccountRepo{}
+			if tt.setupRepo != nil {
+				tt.setupRepo(repo)
+			}
+
+			uc := usecase.UpdateCreditLimit{Accounts: repo, TransactionManager: &mockTransactionManager{}}
+
+			acc, err := uc.Execute(context.Background(), 1, cents(tt.limitCents))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if acc.AvailableCreditLimitCents != tt.limitCents {
+				t.Errorf("expected limit %d, got %d", tt.limitCents, acc.AvailableCreditLimitCents)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// ListTransactions Tests
+// =============================================================================
+
+func TestListTransactions_Execute(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := make([]domain.Transaction, 5)
+	for i := range history {
+		history[i] = domain.Transaction{ID: int64(5 - i), AccountID: 1, EventDate: base.Add(-time.Duration(i) * time.Hour)}
+	}
+
+	tests := []struct {
+		name       string
+		filter     domain.TransactionFilter
+		stored     []domain.Transaction
+		setupAcc   func(*mockAccountRepo)
+		wantErr    error
+		wantLen    int
+		wantNextID int64
+		wantLimit  int
+	}{
+		{
+			name:       "full page returns cursor to last item",
+			filter:     domain.TransactionFilter{AccountID: 1, Limit: 3},
+			stored:     history[:4],
+			wantLen:    3,
+			wantNextID: 3,
+			wantLimit:  4,
+		},
+		{
+			name:      "last page has no cursor",
+			filter:    domain.TransactionFilter{AccountID: 1, Limit: 10},
+			stored:    history,
+			wantLen:   5,
+			wantLimit: 11,
+		},
+		{
+			name:      "zero limit uses default",
+			filter:    domain.TransactionFilter{AccountID: 1},
+			stored:    nil,
+			wantLen:   0,
+			wantLimit: usecase.DefaultPageSize + 1,
+		},
+		{
+			name:    "limit above maximum",
+			filter:  domain.TransactionFilter{AccountID: 1, Limit: usecase.MaxPageSize + 1},
+			wantErr: usecase.ErrInvalidPageSize,
+		},
+		{
+			name:    "inverted date range",
+			filter:  domain.TransactionFilter{AccountID: 1, From: base, To: base.Add(-time.Hour)},
+			wantErr: usecase.ErrInvalidDateRange,
+		},
+		{
+			name:   "unknown account",
+			filter: domain.TransactionFilter{AccountID: 9},
+			setupAcc: func(m *mockAccountRepo) {
+				m.findByIDFn = func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{}, domain.ErrAccountNotFound
+				}
+			},
+			wantErr: domain.ErrAccountNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accRepo := &mockAccountRepo{}
+			if tt.setupAcc != nil {
+				tt.setupAcc(accRepo)
+			}
+
+			var gotLimit int
+			txRepo := &mockTransactionRepo{
+				listByAccountFn: func(ctx context.Context, filter domain.TransactionFilter) ([]domain.Transaction, error) {
+					gotLimit = filter.Limit
+					if len(tt.stored) > filter.Limit {
+						return tt.stored[:filter.Limit], nil
+					}
+					return tt.stored, nil
+				},
+			}
+
+			uc := usecase.ListTransactions{Accounts: accRepo, Transactions: txRepo}
+
+			page, err := uc.Execute(context.Background(), tt.filter)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if gotLimit != tt.wantLimit {
+				t.Errorf("expected repository limit %d, got %d", tt.wantLimit, gotLimit)
+			}
+
+			if len(page.Transactions) != tt.wantLen {
+				t.Errorf("expected %d transactions, got %d", tt.wantLen, len(page.Transactions))
+			}
+
+			switch {
+			case tt.wantNextID == 0 && page.Next != nil:
+				t.Errorf("expected no next cursor, got %+v", page.Next)
+			case tt.wantNextID != 0 && (page.Next == nil || page.Next.ID != tt.wantNextID):
+				t.Errorf("expected next cursor at %d, got %+v", tt.wantNextID, page.Next)
+			}
+		})
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := domain.Cursor{Time: time.Date(2026, 3, 4, 5, 6, 7, 890, time.UTC), ID: 42}
+
+	decoded, err := domain.DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !decoded.Time.Equal(c.Time) || decoded.ID != c.ID {
+		t.Errorf("expected %+v, got %+v", c, decoded)
+	}
+
+	if _, err := domain.DecodeCursor("not-a-cursor"); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+// =============================================================================
+// GetTransaction Tests
+// =============================================================================
+
+func TestGetTransaction_Execute(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(*mockAccountRepo, *mockOperationTypeRepo, *mockTransactionRepo)
+		wantErr    error
+	}{
+		{
+			name: "success - returns transaction with account and operation type",
+		},
+		{
+			name: "error - transaction not found",
+			setupMocks: func(accRepo *mockAccountRepo, opRepo *mockOperationTypeRepo, txRepo *mockTransactionRepo) {
+				txRepo.findByIDFn = func(ctx context.Context, id int64) (domain.Transaction, error) {
+					return domain.Transaction{}, domain.ErrTransactionNotFound
+				}
+			},
+			wantErr: domain.ErrTransactionNotFound,
+		},
+		{
+			name: "error - operation type lookup fails",
+			setupMocks: func(accRepo *mockAccountRepo, opRepo *mockOperationTypeRepo, txRepo *mockTransactionRepo) {
+				opRepo.findByIDFn = func(ctx context.Context, id int) (domain.OperationType, error) {
+					return domain.OperationType{}, errors.New("db error")
+				}
+			},
+			wantErr: errors.New("db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accRepo := &mockAccountRepo{}
+			opRepo := &mockOperationTypeRepo{}
+			txRepo := &mockTransactionRepo{}
+			if tt.setupMocks != nil {
+				tt.setupMocks(accRepo, opRepo, txRepo)
+			}
+
+			uc := usecase.GetTransaction{Accounts: accRepo, OperationTypes: opRepo, Transactions: txRepo}
+
+			details, err := uc.Execute(context.Background(), 10)
+
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if details.Transaction.ID != 10 {
+				t.Errorf("expected transaction 10, got %d", details.Transaction.ID)
+			}
+			if details.Account.ID != details.Transaction.AccountID {
+				t.Errorf("expected account %d, got %d", details.Transaction.AccountID, details.Account.ID)
+			}
+			if details.OperationType.ID != details.Transaction.OperationTypeID {
+				t.Errorf("expected operation type %d, got %d", details.Transaction.OperationTypeID, details.OperationType.ID)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// ReverseTransaction Tests
+// =============================================================================
+
+func TestReverseTransaction_Execute(t *testing.T) {
+	posted := domain.TransactionStatusPosted
+	purchase := domain.Transaction{ID: 10, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: -5000, BalanceCents: -5000, Currency: "BRL", Status: posted}
+	voucher := domain.Transaction{ID: 20, AccountID: 1, OperationTypeID: domain.OperationTypeCreditVoucher, AmountCents: 3000, BalanceCents: 1000, Currency: "BRL", Status: posted}
+
+	tests := []struct {
+		name        string
+		original    domain.Transaction
+		reversed    int64
+		amountCents int64
+		openDebits  []domain.Transaction
+		wantErr     error
+		wantAmount  int64
+		wantBalance int64
+		wantOpType  int
+		wantUpdates map[int64]int64
+	}{
+		{
+			name:        "partial reversal of a purchase pays the purchase first",
+			original:    purchase,
+			amountCents: 2000,
+			openDebits: []domain.Transaction{
+				{ID: 3, BalanceCents: -700},
+				purchase,
+			},
+			wantAmount:  2000,
+			wantBalance: 0,
+			wantOpType:  domain.OperationTypeDebitReversal,
+			wantUpdates: map[int64]int64{10: -3000},
+		},
+		{
+			name:        "zero amount reverses what is left",
+			original:    purchase,
+			reversed:    1500,
+			amountCents: 0,
+			wantAmount:  3500,
+			wantBalance: 3500,
+			wantOpType:  domain.OperationTypeDebitReversal,
+			wantUpdates: map[int64]int64{},
+		},
+		{
+			name:        "reversal of a credit takes back its unused balance",
+			original:    voucher,
+			amountCents: 3000,
+			wantAmount:  -3000,
+			wantBalance: -2000,
+			wantOpType:  domain.OperationTypeCreditReversal,
+			wantUpdates: map[int64]int64{20: 0},
+		},
+		{
+			name:        "reversal beyond the original is rejected",
+			original:    purchase,
+			reversed:    4000,
+			amountCents: 1001,
+			wantErr:     usecase.ErrReversalExceeded,
+		},
+		{
+			name:        "fully reversed transaction is rejected",
+			original:    purchase,
+			reversed:    5000,
+			amountCents: 0,
+			wantErr:     usecase.ErrReversalExceeded,
+		},
+		{
+			name:        "reversals cannot be reversed",
+			original:    domain.Transaction{ID: 30, AccountID: 1, AmountCents: 100, Currency: "BRL", ReversedOf: 10, Status: posted},
+			amountCents: 0,
+			wantErr:     usecase.ErrNotReversible,
+		},
+		{
+			name:        "installment plans are reversed per installment",
+			original:    domain.Transaction{ID: 40, AccountID: 1, AmountCents: -9000, Installments: 3, Status: posted},
+			amountCents: 0,
+			wantErr:     usecase.ErrNotReversible,
+		},
+		{
+			name:        "scheduled installments cannot be reversed yet",
+			original:    domain.Transaction{ID: 42, AccountID: 1, AmountCents: -3000, ParentID: 40, InstallmentNumber: 2, Status: domain.TransactionStatusScheduled},
+			amountCents: 0,
+			wantErr:     usecase.ErrNotReversible,
+		},
+		{
+			name:        "negative amount",
+			original:    purchase,
+			amountCents: -1,
+			wantErr:     usecase.ErrInvalidAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updates := map[int64]int64{}
+			txRepo := &mockTransactionRepo{
+				findByIDFn: func(ctx context.Context, id int64) (domain.Transaction, error) {
+					return tt.original, nil
+				},
+				sumReversalsFn: func(ctx context.Context, id int64) (int64, error) {
+					return tt.reversed, nil
+				},
+				findOpenDebitsFn: func(ctx context.Context, accountID int64) ([]domain.Transaction, error) {
+					return tt.openDebits, nil
+				},
+				updateBalanceFn: func(ctx context.Context, id int64, balanceCents int64) error {
+					updates[id] = balanceCents
+					return nil
+				},
+			}
+
+			uc := usecase.ReverseTransaction{
+				Accounts:           &mockAccountRepo{},
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			tx, err := uc.Execute(context.Background(), tt.original.ID, cents(tt.amountCents))
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tx.AmountCents != tt.wantAmount {
+				t.Errorf("expected AmountCents %d, got %d", tt.wantAmount, tx.AmountCents)
+			}
+			if tx.BalanceCents != tt.wantBalance {
+				t.Errorf("expected BalanceCents %d, got %d", tt.wantBalance, tx.BalanceCents)
+			}
+			if tx.OperationTypeID != tt.wantOpType {
+				t.Errorf("expected OperationTypeID %d, got %d", tt.wantOpType, tx.OperationTypeID)
+			}
+			if tx.ReversedOf != tt.original.ID {
+				t.Errorf("expected ReversedOf %d, got %d", tt.original.ID, tx.ReversedOf)
+			}
+			if len(updates) != len(tt.wantUpdates) {
+				t.Fatalf("expected balance updates %v, got %v", tt.wantUpdates, updates)
+			}
+			for id, want := range tt.wantUpdates {
+				if updates[id] != want {
+					t.Errorf("expected transaction %d balance %d, got %d", id, want, updates[id])
+				}
+			}
+		})
+	}
+}
+
+func TestReverseTransaction_OperationTypeRules(t *testing.T) {
+	purchase := domain.Transaction{ID: 10, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: -5000, BalanceCents: -5000, Currency: "BRL", Status: domain.TransactionStatusPosted}
+
+	tests := []struct {
+		name     string
+		original domain.OperationType
+		reversal domain.OperationType
+		wantErr  error
+	}{
+		{
+			name:     "original type not reversible",
+			original: domain.OperationType{Sign: -1, Active: true},
+			reversal: domain.OperationType{Sign: 1, Active: true},
+			wantErr:  usecase.ErrNotReversible,
+		},
+		{
+			name:     "reversal type inactive",
+			original: domain.OperationType{Sign: -1, Active: true, Reversible: true},
+			reversal: domain.OperationType{Sign: 1},
+			wantErr:  domain.ErrOperationTypeInactive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opRepo := &mockOperationTypeRepo{
+				findByIDFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+					if id == purchase.OperationTypeID {
+						return tt.original, nil
+					}
+					return tt.reversal, nil
+				},
+			}
+			txRepo := &mockTransactionRepo{
+				findByIDFn: func(ctx context.Context, id int64) (domain.Transaction, error) {
+					return purchase, nil
+				},
+			}
+
+			uc := usecase.ReverseTransaction{
+				Accounts:           &mockAccountRepo{},
+				OperationTypes:     opRepo,
+				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			_, err := uc.Execute(context.Background(), purchase.ID, cents(0))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReverseTransaction_CreditLimitFollowsOriginal(t *testing.T) {
+	tests := []struct {
+		name         string
+		recorded     bool
+		reversalType bool
+		wantLimit    []int64
+	}{
+		{
+			name:         "original did not take the limit",
+			recorded:     false,
+			reversalType: true,
+		},
+		{
+			name:         "original took the limit before its type changed",
+			recorded:     true,
+			reversalType: false,
+			wantLimit:    []int64{12000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purchase := domain.Transaction{ID: 10, AccountID: 1, OperationTypeID: 7, AmountCents: -5000, BalanceCents: -5000, Currency: "BRL", Status: domain.TransactionStatusPosted, AffectsCreditLimit: tt.recorded}
+
+			var limits []int64
+			var created domain.Transaction
+			accRepo := &mockAccountRepo{
+				findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+					return domain.Account{ID: id, Currency: "BRL", Status: domain.AccountStatusActive, AvailableCreditLimitCents: 7000}, nil
+				},
+				updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+					limits = append(limits, limitCents)
+					return nil
+				},
+			}
+			opRepo := &mockOperationTypeRepo{
+				findByIDFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+					if id == purchase.OperationTypeID {
+						return domain.OperationType{ID: id, Sign: -1, Reversible: true, Active: true}, nil
+					}
+					return domain.OperationType{ID: id, Sign: 1, AffectsCreditLimit: tt.reversalType, Active: true}, nil
+				},
+			}
+			txRepo := &mockTransactionRepo{
+				findByIDFn: func(ctx context.Context, id int64) (domain.Transaction, error) {
+					return purchase, nil
+				},
+				createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) {
+					created = tx
+					return 11, nil
+				},
+			}
+
+			uc := usecase.ReverseTransaction{
+				Accounts:           accRepo,
+				OperationTypes:     opRepo,
+				Transactions:       txRepo,
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			if _, err := uc.Execute(context.Background(), purchase.ID, cents(0)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !slices.Equal(limits, tt.wantLimit) {
+				t.Errorf("expected limit updates %v, got %v", tt.wantLimit, limits)
+			}
+			if created.AffectsCreditLimit != tt.recorded {
+				t.Errorf("expected the reversal to record AffectsCreditLimit %v, got %v", tt.recorded, created.AffectsCreditLimit)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Operation Type Tests
+// =============================================================================
+
+func TestCreateOperationType_Execute(t *testing.T) {
+	no := false
+
+	tests := []struct {
+		name    string
+		input   usecase.CreateOperationTypeInput
+		wantErr error
+		want    domain.OperationType
+	}{
+		{
+			name:  "rules default to true",
+			input: usecase.CreateOperationTypeInput{Description: " annual fee ", Sign: -1},
+			want:  domain.OperationType{ID: 100, Description: "annual fee", Sign: -1, AffectsCreditLimit: true, Reversible: true, Active: true},
+		},
+		{
+			name:  "explicit id and rules",
+			input: usecase.CreateOperationTypeInput{ID: 7, Description: "CASHBACK", Sign: 1, AffectsCreditLimit: &no, Reversible: &no},
+			want:  domain.OperationType{ID: 7, Description: "CASHBACK", Sign: 1, Active: true},
+		},
+		{
+			name:    "blank description",
+			input:   usecase.CreateOperationTypeInput{Description: "  ", Sign: 1},
+			wantErr: usecase.ErrInvalidDescription,
+		},
+		{
+			name:    "zero sign",
+			input:   usecase.CreateOperationTypeInput{Description: "FEE"},
+			wantErr: usecase.ErrInvalidSign,
+		},
+		{
+			name:    "negative id",
+			input:   usecase.CreateOperationTypeInput{ID: -1, Description: "FEE", Sign: -1},
+			wantErr: usecase.ErrInvalidOperation,
+		},
+		{
+			name:    "id in the numbered range",
+			input:   usecase.CreateOperationTypeInput{ID: domain.FirstNumberedOperationType, Description: "FEE", Sign: -1},
+			wantErr: usecase.ErrInvalidOperation,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.CreateOperationType{OperationTypes: &mockOperationTypeRepo{}}
+
+			got, err := uc.Execute(context.Background(), tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got.CreatedAt, got.UpdatedAt = time.Time{}, time.Time{}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestUpdateOperationType_Execute(t *testing.T) {
+	stored := domain.OperationType{ID: 4, Description: "CREDIT VOUCHER", Sign: 1, AffectsCreditLimit: true, Reversible: true, Active: true}
+	blank := " "
+	no := false
+
+	tests := []struct {
+		name    string
+		input   usecase.UpdateOperationTypeInput
+		wantErr error
+		want    domain.OperationType
+	}{
+		{
+			name:  "deactivate keeps the other fields",
+			input: usecase.UpdateOperationTypeInput{Active: &no},
+			want:  domain.OperationType{ID: 4, Description: "CREDIT VOUCHER", Sign: 1, AffectsCreditLimit: true, Reversible: true},
+		},
+		{
+			name:    "blank description",
+			input:   usecase.UpdateOperationTypeInput{Description: &blank},
+			wantErr: usecase.ErrInvalidDescription,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved domain.OperationType
+			opRepo := &mockOperationTypeRepo{
+				findByIDForUpdateFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+					return stored, nil
+				},
+				updateFn: func(ctx context.Context, ot domain.OperationType) error {
+					saved = ot
+					return nil
+				},
+			}
+			uc := usecase.UpdateOperationType{OperationTypes: opRepo, TransactionManager: &mockTransactionManager{}}
+
+			got, err := uc.Execute(context.Background(), stored.ID, tt.input)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got.UpdatedAt, saved.UpdatedAt = time.Time{}, time.Time{}
+			if got != tt.want || saved != tt.want {
+				t.Errorf("expected %+v, got %+v (saved %+v)", tt.want, got, saved)
+			}
+		})
+	}
+}
+
+func TestListOperationTypes_ActiveOnly(t *testing.T) {
+	opRepo := &mockOperationTypeRepo{
+		listFn: func(ctx context.Context) ([]domain.OperationType, error) {
+			return []domain.OperationType{{ID: 1, Active: true}, {ID: 2}, {ID: 3, Active: true}}, nil
+		},
+	}
+	uc := usecase.ListOperationTypes{OperationTypes: opRepo}
+
+	got, err := uc.Execute(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].ID != 1 || got[1].ID != 3 {
+		t.Errorf("expected types 1 and 3, got %+v", got)
+	}
+}
+
+// =============================================================================
+// Installment Tests
+// =============================================================================
+
+func TestSplitInstallments(t *testing.T) {
+	tests := []struct {
+		total int64
+		n     int
+		want  []int64
+	}{
+		{total: 10000, n: 3, want: []int64{3334, 3333, 3333}},
+		{total: 10001, n: 4, want: []int64{2501, 2500, 2500, 2500}},
+		{total: 500, n: 1, want: []int64{500}},
+		{total: 12, n: 12, want: []int64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		got := domain.SplitInstallments(tt.total, tt.n)
+
+		var sum int64
+		for _, v := range got {
+			sum += v
+		}
+		if sum != tt.total {
+			t.Errorf("SplitInstallments(%d, %d) sums to %d", tt.total, tt.n, sum)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("SplitInstallments(%d, %d) = %v, want %v", tt.total, tt.n, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SplitInstallments(%d, %d) = %v, want %v", tt.total, tt.n, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestInstallmentDate(t *testing.T) {
+	purchase := time.Date(2024, time.January, 31, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		number int
+		want   time.Time
+	}{
+		{1, purchase},
+		{2, time.Date(2024, time.February, 29, 15, 4, 5, 0, time.UTC)},
+		{3, time.Date(2024, time.March, 31, 15, 4, 5, 0, time.UTC)},
+		{4, time.Date(2024, time.April, 30, 15, 4, 5, 0, time.UTC)},
+		{13, time.Date(2025, time.January, 31, 15, 4, 5, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := domain.InstallmentDate(purchase, tt.number); !got.Equal(tt.want) {
+			t.Errorf("InstallmentDate(%d) = %v, want %v", tt.number, got, tt.want)
+		}
+	}
+}
+
+func TestCreateTransaction_Installments(t *testing.T) {
+	var created []domain.Transaction
+	var limit, posted, pending int64
+
+	uc := usecase.CreateTransaction{
+		Accounts: &mockAccountRepo{
+			updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+				limit = limitCents
+				return nil
+			},
+		},
+		OperationTypes: &mockOperationTypeRepo{},
+		Transactions: &mockTransactionRepo{
+			createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) {
+				created = append(created, tx)
+				return int64(len(created)), nil
+			},
+		},
+		Balances: &mockBalanceRepo{
+			applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+				posted += postedDelta
+				pending += pendingDelta
+				return nil
+			},
+		},
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	parent, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{
+		AccountID:       1,
+		OperationTypeID: domain.OperationTypePurchaseInstallment,
+		Amount:          cents(10000),
+		Installments:    3,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if parent.ID != 1 || parent.AmountCents != -10000 || parent.BalanceCents != 0 || parent.Installments != 3 {
+		t.Errorf("unexpected parent %+v", parent)
+	}
+	if len(created) != 4 {
+		t.Fatalf("expected parent and 3 installments, got %d transactions", len(created))
+	}
+
+	wantAmounts := []int64{-3334, -3333, -3333}
+	for i, child := range created[1:] {
+		if child.ParentID != parent.ID || child.InstallmentNumber != i+1 || child.AmountCents != wantAmounts[i] {
+			t.Errorf("unexpected installment %d: %+v", i+1, child)
+		}
+		if !child.EventDate.Equal(domain.InstallmentDate(parent.EventDate, i+1)) {
+			t.Errorf("installment %d event date %v", i+1, child.EventDate)
+		}
+
+		wantStatus, wantBalance := domain.TransactionStatusScheduled, int64(0)
+		if i == 0 {
+			wantStatus, wantBalance = domain.TransactionStatusPosted, wantAmounts[0]
+		}
+		if child.Status != wantStatus || child.BalanceCents != wantBalance {
+			t.Errorf("installment %d: expected %s with balance %d, got %s with %d", i+1, wantStatus, wantBalance, child.Status, child.BalanceCents)
+		}
+	}
+
+	if limit != 1000000-10000 {
+		t.Errorf("expected the whole purchase to use the credit limit, got %d", limit)
+	}
+	if posted != -3334 || pending != 6666 {
+		t.Errorf("expected posted -3334 and pending 6666, got %d and %d", posted, pending)
+	}
+}
+
+func TestCreateTransaction_InvalidInstallments(t *testing.T) {
+	tests := []struct {
+		name            string
+		operationTypeID int
+		amountCents     int64
+		installments    int
+	}{
+		{"not an installment purchase", domain.OperationTypeNormalPurchase, 10000, 3},
+		{"negative count", domain.OperationTypePurchaseInstallment, 10000, -1},
+		{"too many installments", domain.OperationTypePurchaseInstallment, 10000, domain.MaxInstallments + 1},
+		{"less than a cent per installment", domain.OperationTypePurchaseInstallment, 2, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.CreateTransaction{
+				Accounts:           &mockAccountRepo{},
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       &mockTransactionRepo{},
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+			}
+
+			_, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{
+				AccountID:       1,
+				OperationTypeID: tt.operationTypeID,
+				Amount:          cents(tt.amountCents),
+				Installments:    tt.installments,
+			})
+			if !errors.Is(err, usecase.ErrInvalidInstallments) {
+				t.Errorf("expected ErrInvalidInstallments, got %v", err)
+			}
+		})
+	}
+}
+
+func TestPostDueInstallments_Execute(t *testing.T) {
+	due := []domain.Transaction{
+		{ID: 2, AccountID: 1, AmountCents: -3333, ParentID: 1, InstallmentNumber: 2, Status: domain.TransactionStatusScheduled},
+		{ID: 3, AccountID: 1, AmountCents: -3333, ParentID: 1, InstallmentNumber: 3, Status: domain.TransactionStatusScheduled},
+	}
+
+	var postedIDs []int64
+	var posted, pending int64
+
+	uc := usecase.PostDueInstallments{
+		Accounts: &mockAccountRepo{},
+		Transactions: &mockTransactionRepo{
+			findDueFn: func(ctx context.Context, asOf time.Time, limit int) ([]domain.Transaction, error) {
+				if limit != usecase.DefaultPostingBatchSize {
+					t.Errorf("expected default batch size, got %d", limit)
+				}
+				return due, nil
+			},
+			markPostedFn: func(ctx context.Context, id int64, balanceCents int64) error {
+				// The second one was posted by a concurrent run.
+				if id == 3 {
+					return domain.ErrTransactionNotFound
+				}
+				if balanceCents != -3333 {
+					t.Errorf("expected balance -3333, got %d", balanceCents)
+				}
+				postedIDs = append(postedIDs, id)
+				return nil
+			},
+		},
+		Balances: &mockBalanceRepo{
+			applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+				posted += postedDelta
+				pending += pendingDelta
+				return nil
+			},
+		},
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	n, err := uc.Execute(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n != 1 || len(postedIDs) != 1 || postedIDs[0] != 2 {
+		t.Errorf("expected only installment 2 to be posted, got %d (%v)", n, postedIDs)
+	}
+	if posted != -3333 || pending != -3333 {
+		t.Errorf("expected posted -3333 and pending -3333, got %d and %d", posted, pending)
+	}
+}
+
+// =============================================================================
+// Statement Tests
+// =============================================================================
+
+func TestMinimumPayment(t *testing.T) {
+	tests := []struct {
+		totalDue int64
+		want     int64
+	}{
+		{totalDue: 0, want: 0},
+		{totalDue: 1000, want: 1000},
+		{totalDue: 10000, want: 2500},
+		{totalDue: 100000, want: 15000},
+		{totalDue: 100001, want: 15001},
+	}
+
+	for _, tt := range tests {
+		if got := domain.MinimumPayment(tt.totalDue); got != tt.want {
+			t.Errorf("MinimumPayment(%d) = %d, want %d", tt.totalDue, got, tt.want)
+		}
+	}
+}
+
+func TestLastClosing(t *testing.T) {
+	tests := []struct {
+		closingDay int
+		now        time.Time
+		want       time.Time
+	}{
+		{10, time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{10, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{10, time.Date(2024, 3, 9, 23, 59, 0, 0, time.UTC), time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)},
+		{28, time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := domain.LastClosing(tt.closingDay, tt.now); !got.Equal(tt.want) {
+			t.Errorf("LastClosing(%d, %v) = %v, want %v", tt.closingDay, tt.now, got, tt.want)
+		}
+	}
+}
+
+func TestCloseStatements_Execute(t *testing.T) {
+	created := time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)
+	account := domain.Account{ID: 1, ClosingDay: 5, CreatedAt: created}
+	fresh := domain.Account{ID: 2, ClosingDay: 5, CreatedAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)}
+
+	type period struct{ from, to time.Time }
+	var periods []period
+
+	statements := &mockStatementRepo{}
+	clock := &mockClock{now: time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)}
+
+	uc := usecase.CloseStatements{
+		Accounts: &mockAccountRepo{
+			listAfterFn: func(ctx context.Context, afterID int64, limit int) ([]domain.Account, error) {
+				if afterID != 0 {
+					t.Errorf("expected a single page, got afterID %d", afterID)
+				}
+				return []domain.Account{account, fresh}, nil
+			},
+			findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+				if id == fresh.ID {
+					return fresh, nil
+				}
+				return account, nil
+			},
+		},
+		Transactions: &mockTransactionRepo{
+			sumByPeriodFn: func(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error) {
+				periods = append(periods, period{from, to})
+				return 60000, 10000, nil
+			},
+		},
+		Statements:         statements,
+		TransactionManager: &mockTransactionManager{},
+		Clock:              clock,
+	}
+
+	closed, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The fresh account was created after the last closing and has no cycle yet.
+	if closed != 1 || len(statements.statements) != 1 {
+		t.Fatalf("expected 1 statement, got %d", closed)
+	}
+
+	first := statements.statements[0]
+	wantEnd := time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC)
+	if !first.PeriodStart.Equal(created) || !first.PeriodEnd.Equal(wantEnd) {
+		t.Errorf("unexpected period %v - %v", first.PeriodStart, first.PeriodEnd)
+	}
+	if first.ClosingBalanceCents != -50000 || first.TotalDueCents != 50000 || first.MinimumPaymentCents != 7500 {
+		t.Errorf("unexpected totals %+v", first)
+	}
+	if !first.DueDate.Equal(wantEnd.AddDate(0, 0, domain.StatementDueDays)) || !first.ClosedAt.Equal(clock.now) {
+		t.Errorf("unexpected due date %v or closed at %v", first.DueDate, first.ClosedAt)
+	}
+
+	// Same cycle: nothing new to close.
+	if closed, err = uc.Execute(context.Background()); err != nil || closed != 0 {
+		t.Fatalf("expected no statement, got %d (%v)", closed, err)
+	}
+
+	// Next cycle starts where the previous ended and carries its balance.
+	clock.now = time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	if closed, err = uc.Execute(context.Background()); err != nil || closed != 2 {
+		t.Fatalf("expected 2 statements, got %d (%v)", closed, err)
+	}
+
+	second := statements.statements[1]
+	if !second.PeriodStart.Equal(wantEnd) || !second.PeriodEnd.Equal(clock.now) {
+		t.Errorf("unexpected period %v - %v", second.PeriodStart, second.PeriodEnd)
+	}
+	if second.OpeningBalanceCents != -50000 || second.ClosingBalanceCents != -100000 || second.TotalDueCents != 100000 {
+		t.Errorf("unexpected totals %+v", second)
+	}
+	if len(periods) != 3 {
+		t.Errorf("expected 3 aggregated periods, got %d", len(periods))
+	}
+}
+
+func TestCloseStatements_CatchesUpMissedCycles(t *testing.T) {
+	created := time.Date(2024, 1, 20, 9, 0, 0, 0, time.UTC)
+	account := domain.Account{ID: 1, ClosingDay: 5, Currency: "BRL", CreatedAt: created}
+
+	statements := &mockStatementRepo{}
+	uc := usecase.CloseStatements{
+		Accounts: &mockAccountRepo{
+			listAfterFn: func(ctx context.Context, afterID int64, limit int) ([]domain.Account, error) {
+				return []domain.Account{account}, nil
+			},
+			findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+				return account, nil
+			},
+		},
+		Transactions: &mockTransactionRepo{
+			sumByPeriodFn: func(ctx context.Context, accountID int64, from, to time.Time) (int64, int64, error) {
+				// 100.00 spent in every cycle.
+				return 10000, 0, nil
+			},
+		},
+		Statements:         statements,
+		TransactionManager: &mockTransactionManager{},
+		// The job has not run since the account was opened: three cycles
+		// ended meanwhile.
+		Clock: &mockClock{now: time.Date(2024, 4, 6, 8, 0, 0, 0, time.UTC)},
+	}
+
+	closed, err := uc.Execute(context.Background())
+	if err != nil || closed != 3 || len(statements.statements) != 3 {
+		t.Fatalf("expected 3 statements, got %d (%v)", closed, err)
+	}
+
+	ends := []time.Time{
+		time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 4, 5, 0, 0, 0, 0, time.UTC),
+	}
+	start := created
+	for i, s := range statements.statements {
+		if !s.PeriodStart.Equal(start) || !s.PeriodEnd.Equal(ends[i]) {
+			t.Errorf("statement %d: expected %v - %v, got %v - %v", i, start, ends[i], s.PeriodStart, s.PeriodEnd)
+		}
+		if want := int64(-10000 * (i + 1)); s.OpeningBalanceCents != want+10000 || s.ClosingBalanceCents != want {
+			t.Errorf("statement %d: expected the balance carried over, got %d to %d", i, s.OpeningBalanceCents, s.ClosingBalanceCents)
+		}
+		start = ends[i]
+	}
+}
+
+func TestCreateAccount_ClosingDay(t *testing.T) {
+	tests := []struct {
+		name       string
+		closingDay int
+		want       int
+		wantErr    error
+	}{
+		{"defaults", 0, domain.DefaultClosingDay, nil},
+		{"explicit", 15, 15, nil},
+		{"after the 28th", 29, 0, usecase.ErrInvalidClosingDay},
+		{"negative", -1, 0, usecase.ErrInvalidClosingDay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc := usecase.CreateAccount{Accounts: &mockAccountRepo{}, Documents: &mockDocuments{}}
+
+			acc, err := uc.Execute(context.Background(), usecase.CreateAccountInput{DocumentNumber: "52998224725", ClosingDay: tt.closingDay})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if acc.ClosingDay != tt.want {
+				t.Errorf("expected closing day %d, got %d", tt.want, acc.ClosingDay)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Money Tests
+// =============================================================================
+
+func TestParseMinorUnits(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    int64
+		wantErr error
+	}{
+		{in: "0.29", want: 29},
+		{in: "0.1", want: 10},
+		{in: "123.45", want: 12345},
+		{in: "10", want: 1000},
+		{in: "-50.5", want: -5050},
+		{in: "1.230", want: 123},
+		{in: "92233720368547758.07", want: 9223372036854775807},
+		{in: "1.234", wantErr: domain.ErrAmountPrecision},
+		{in: "0.001", wantErr: domain.ErrAmountPrecision},
+		{in: "92233720368547758.08", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "1e2", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "+1", wantErr: domain.ErrInvalidAmountFormat},
+		{in: ".5", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "5.", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "1,50", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "", wantErr: domain.ErrInvalidAmountFormat},
+		{in: "-", wantErr: domain.ErrInvalidAmountFormat},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.ParseMinorUnits(tt.in, 2)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseMinorUnits(%q) error = %v, want %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMinorUnits(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+// =============================================================================
+// Currency Tests
+// =============================================================================
+
+// mockRates quotes pairs from a map keyed by "FROM/TO".
+type mockRates map[string]domain.Decimal
+
+func (m mockRates) Rate(ctx context.Context, from, to string) (domain.Decimal, error) {
+	rate, ok := m[from+"/"+to]
+	if !ok {
+		return domain.Decimal{}, domain.ErrRateUnavailable
+	}
+	return rate, nil
+}
+
+func TestConvert(t *testing.T) {
+	usd, _ := domain.LookupCurrency("USD")
+	brl, _ := domain.LookupCurrency("BRL")
+	jpy, _ := domain.LookupCurrency("JPY")
+	bhd, _ := domain.LookupCurrency("BHD")
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to domain.Currency
+		rate     domain.Decimal
+		want     int64
+	}{
+		{"USD to BRL", 1000, usd, brl, domain.Decimal{Units: 525, Scale: 2}, 5250},
+		{"rounds half up", 1, usd, brl, domain.Decimal{Units: 55, Scale: 1}, 6},
+		{"negative rounds away from zero", -1, usd, brl, domain.Decimal{Units: 55, Scale: 1}, -6},
+		{"to zero-decimal currency", 1000, usd, jpy, domain.Decimal{Units: 150123, Scale: 3}, 1501},
+		{"from zero-decimal currency", 1000, jpy, usd, domain.Decimal{Units: 666, Scale: 5}, 666},
+		{"to three-decimal currency", 1000, usd, bhd, domain.Decimal{Units: 376, Scale: 3}, 3760},
+	}
+
+	for _, tt := range tests {
+		got, err := domain.Convert(tt.amount, tt.from, tt.to, tt.rate)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCreateTransaction_Currency(t *testing.T) {
+	tests := []struct {
+		name            string
+		accountCurrency string
+		amount          string
+		currency        string
+		rates           port.ExchangeRates
+		wantErr         error
+		wantAmount      int64
+		wantOriginal    int64
+	}{
+		{name: "defaults to the account currency", accountCurrency: "BRL", amount: "10.50", wantAmount: -1050},
+		{name: "zero-decimal currency", accountCurrency: "JPY", amount: "1500", wantAmount: -1500},
+		{name: "zero-decimal currency rejects fractions", accountCurrency: "JPY", amount: "1500.5", wantErr: domain.ErrAmountPrecision},
+		{name: "three-decimal currency", accountCurrency: "BHD", amount: "1.005", wantAmount: -1005},
+		{name: "too many decimals for the currency", accountCurrency: "BRL", amount: "1.005", wantErr: domain.ErrAmountPrecision},
+		{name: "unsupported currency", accountCurrency: "BRL", amount: "10", currency: "XYZ", wantErr: domain.ErrUnsupportedCurrency},
+		{name: "mismatch without rates", accountCurrency: "BRL", amount: "10", currency: "USD", wantErr: usecase.ErrCurrencyMismatch},
+		{name: "mismatch without a quote", accountCurrency: "BRL", amount: "10", currency: "EUR", rates: mockRates{}, wantErr: domain.ErrRateUnavailable},
+		{
+			name:            "converted through rates",
+			accountCurrency: "BRL",
+			amount:          "10",
+			currency:        "USD",
+			rates:           mockRates{"USD/BRL": {Units: 525, Scale: 2}},
+			wantAmount:      -5250,
+			wantOriginal:    -1000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := domain.ParseDecimal(tt.amount)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			uc := usecase.CreateTransaction{
+				Accounts: &mockAccountRepo{
+					findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+						return domain.Account{ID: id, AvailableCreditLimitCents: 1000000, Currency: tt.accountCurrency}, nil
+					},
+				},
+				OperationTypes:     &mockOperationTypeRepo{},
+				Transactions:       &mockTransactionRepo{},
+				Balances:           &mockBalanceRepo{},
+				TransactionManager: &mockTransactionManager{},
+				Rates:              tt.rates,
+			}
+
+			tx, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{
+				AccountID:       1,
+				OperationTypeID: domain.OperationTypeNormalPurchase,
+				Amount:          amount,
+				Currency:        tt.currency,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if tx.AmountCents != tt.wantAmount || tx.Currency != tt.accountCurrency {
+				t.Errorf("expected %d %s, got %d %s", tt.wantAmount, tt.accountCurrency, tx.AmountCents, tx.Currency)
+			}
+			if tx.OriginalAmountCents != tt.wantOriginal {
+				t.Errorf("expected original amount %d, got %d", tt.wantOriginal, tx.OriginalAmountCents)
+			}
+			if tt.wantOriginal != 0 && tx.OriginalCurrency != tt.currency {
+				t.Errorf("expected original currency %s, got %s", tt.currency, tx.OriginalCurrency)
+			}
+		})
+	}
+}
+
+func TestCreateAccount_Currency(t *testing.T) {
+	tests := []struct {
+		name      string
+		currency  string
+		limit     string
+		want      string
+		wantLimit int64
+		wantErr   error
+	}{
+		{name: "defaults to BRL", limit: "100.50", want: "BRL", wantLimit: 10050},
+		{name: "lower case code", currency: "jpy", limit: "5000", want: "JPY", wantLimit: 5000},
+		{name: "limit precision follows the currency", currency: "JPY", limit: "0.5", wantErr: domain.ErrAmountPrecision},
+		{name: "unsupported", currency: "BTC", limit: "1", wantErr: domain.ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limit, err := domain.ParseDecimal(tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			uc := usecase.CreateAccount{Accounts: &mockAccountRepo{}, Documents: &mockDocuments{}}
+			acc, err := uc.Execute(context.Background(), usecase.CreateAccountInput{
+				DocumentNumber:       "52998224725",
+				AvailableCreditLimit: &limit,
+				Currency:             tt.currency,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if acc.Currency != tt.want || acc.AvailableCreditLimitCents != tt.wantLimit {
+				t.Errorf("expected %d %s, got %d %s", tt.wantLimit, tt.want, acc.AvailableCreditLimitCents, acc.Currency)
+			}
+		})
+	}
+}
+
+// =============================================================================
+// Authorization Tests
+// =============================================================================
+
+func TestAuthorizeTransaction_Execute(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		operationTypeID int
+		amount          domain.Decimal
+		currency        string
+		account         domain.Account
+		wantErr         error
+	}{
+		{"holds a debit", domain.OperationTypeNormalPurchase, cents(6000), "", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, nil},
+		{"same currency in another case", domain.OperationTypeNormalPurchase, cents(6000), "brl", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, nil},
+		{"credit operation", domain.OperationTypeCreditVoucher, cents(6000), "", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, usecase.ErrInvalidOperation},
+		{"zero amount", domain.OperationTypeNormalPurchase, cents(0), "", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, usecase.ErrInvalidAmount},
+		{"over the limit", domain.OperationTypeNormalPurchase, cents(10001), "", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, domain.ErrInsufficientFunds},
+		{"other currency", domain.OperationTypeNormalPurchase, cents(6000), "USD", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusActive}, usecase.ErrCurrencyMismatch},
+		{"blocked account", domain.OperationTypeNormalPurchase, cents(6000), "", domain.Account{ID: 1, AvailableCreditLimitCents: 10000, Currency: "BRL", Status: domain.AccountStatusBlocked}, domain.ErrAccountBlocked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var limit, pending int64 = -1, 0
+			var created domain.Authorization
+
+			uc := usecase.AuthorizeTransaction{
+				Accounts: &mockAccountRepo{
+					findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+						return tt.account, nil
+					},
+					updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+						limit = limitCents
+						return nil
+					},
+				},
+				OperationTypes: &mockOperationTypeRepo{},
+				Transactions:   &mockTransactionRepo{},
+				Balances: &mockBalanceRepo{
+					applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+						if postedDelta != 0 {
+							t.Errorf("expected nothing posted, got %d", postedDelta)
+						}
+						pending += pendingDelta
+						return nil
+					},
+				},
+				Authorizations: &mockAuthorizationRepo{
+					createFn: func(ctx context.Context, auth domain.Authorization) (int64, error) {
+						created = auth
+						return 7, nil
+					},
+				},
+				TransactionManager: &mockTransactionManager{},
+				Clock:              &mockClock{now: now},
+				TTL:                time.Hour,
+			}
+
+			auth, err := uc.Execute(context.Background(), usecase.AuthorizeTransactionInput{
+				AccountID:       1,
+				OperationTypeID: tt.operationTypeID,
+				Amount:          tt.amount,
+				Currency:        tt.currency,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if pending != 0 || created.AmountCents != 0 {
+					t.Errorf("expected no hold, got pending %d and %+v", pending, created)
+				}
+				return
+			}
+
+			if auth.ID != 7 || auth.Status != domain.AuthorizationStatusPending || auth.Currency != "BRL" {
+				t.Errorf("unexpected authorization %+v", auth)
+			}
+			if !created.ExpiresAt.Equal(now.Add(time.Hour)) {
+				t.Errorf("expected expiry at %v, got %v", now.Add(time.Hour), created.ExpiresAt)
+			}
+			if limit != 4000 || pending != 6000 {
+				t.Errorf("expected limit 4000 and pending 6000, got %d and %d", limit, pending)
+			}
+			if !created.ReservesCreditLimit {
+				t.Error("expected the authorization to record that it reserved the limit")
+			}
+		})
+	}
+}
+
+func TestCaptureAuthorization_Execute(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		amount      domain.Decimal
+		status      domain.AuthorizationStatus
+		expiresAt   time.Time
+		wantErr     error
+		wantCapture int64
+		wantLimit   int64
+	}{
+		{"whole hold", cents(0), domain.AuthorizationStatusPending, now.Add(time.Hour), nil, 6000, 4000},
+		{"part of the hold", cents(4500), domain.AuthorizationStatusPending, now.Add(time.Hour), nil, 4500, 5500},
+		{"beyond the hold", cents(6001), domain.AuthorizationStatusPending, now.Add(time.Hour), usecase.ErrCaptureExceeded, 0, 0},
+		{"expired", cents(0), domain.AuthorizationStatusPending, now, domain.ErrAuthorizationExpired, 0, 0},
+		{"voided", cents(0), domain.AuthorizationStatusVoided, now.Add(time.Hour), domain.ErrAuthorizationClosed, 0, 0},
+		{"negative amount", cents(-1), domain.AuthorizationStatusPending, now.Add(time.Hour), usecase.ErrInvalidAmount, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The hold of 60.00 already took the account from 100.00 to 40.00.
+			acc := domain.Account{ID: 1, AvailableCreditLimitCents: 4000, Currency: "BRL", Status: domain.AccountStatusActive}
+			var posted, pending int64
+			var closed domain.Authorization
+
+			uc := usecase.CaptureAuthorization{
+				Accounts: &mockAccountRepo{
+					findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+						return acc, nil
+					},
+					updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+						acc.AvailableCreditLimitCents = limitCents
+						return nil
+					},
+				},
+				OperationTypes: &mockOperationTypeRepo{},
+				Transactions: &mockTransactionRepo{
+					createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) {
+						return 42, nil
+					},
+				},
+				Balances: &mockBalanceRepo{
+					applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+						posted += postedDelta
+						pending += pendingDelta
+						return nil
+					},
+				},
+				Authorizations: &mockAuthorizationRepo{
+					findByIDFn: func(ctx context.Context, id int64) (domain.Authorization, error) {
+						return domain.Authorization{ID: id, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: 6000, Currency: "BRL", ReservesCreditLimit: true, Status: tt.status, ExpiresAt: tt.expiresAt}, nil
+					},
+					closeFn: func(ctx context.Context, auth domain.Authorization) error {
+						closed = auth
+						return nil
+					},
+				},
+				TransactionManager: &mockTransactionManager{},
+				Clock:              &mockClock{now: now},
+			}
+
+			auth, tx, err := uc.Execute(context.Background(), 3, tt.amount)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if tx.ID != 42 || tx.AmountCents != -tt.wantCapture {
+				t.Errorf("expected transaction 42 of %d, got %+v", -tt.wantCapture, tx)
+			}
+			if auth.Status != domain.AuthorizationStatusCaptured || auth.CapturedCents != tt.wantCapture || auth.TransactionID != 42 {
+				t.Errorf("unexpected authorization %+v", auth)
+			}
+			if closed.ID != 3 || closed.Status != domain.AuthorizationStatusCaptured {
+				t.Errorf("expected authorization 3 to be closed as captured, got %+v", closed)
+			}
+			if posted != -tt.wantCapture || pending != -6000 {
+				t.Errorf("expected posted %d and the hold released, got %d and %d", -tt.wantCapture, posted, pending)
+			}
+			if acc.AvailableCreditLimitCents != tt.wantLimit {
+				t.Errorf("expected limit %d, got %d", tt.wantLimit, acc.AvailableCreditLimitCents)
+			}
+		})
+	}
+}
+
+func TestCaptureAuthorization_PostsAsHeld(t *testing.T) {
+	// The type's AffectsCreditLimit is flipped between the hold and the
+	// capture; the capture follows the hold.
+	tests := []struct {
+		name      string
+		reserved  bool
+		limit     int64
+		wantLimit int64
+	}{
+		{"hold reserved the limit", true, 4000, 4000},
+		{"hold left the limit alone", false, 1000, 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acc := domain.Account{ID: 1, AvailableCreditLimitCents: tt.limit, Currency: "BRL", Status: domain.AccountStatusActive}
+
+			uc := usecase.CaptureAuthorization{
+				Accounts: &mockAccountRepo{
+					findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+						return acc, nil
+					},
+					updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+						acc.AvailableCreditLimitCents = limitCents
+						return nil
+					},
+				},
+				OperationTypes: &mockOperationTypeRepo{
+					findByIDFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+						return domain.OperationType{ID: id, Sign: -1, AffectsCreditLimit: !tt.reserved, Active: true}, nil
+					},
+				},
+				Transactions: &mockTransactionRepo{
+					createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) {
+						return 42, nil
+					},
+				},
+				Balances: &mockBalanceRepo{},
+				Authorizations: &mockAuthorizationRepo{
+					findByIDFn: func(ctx context.Context, id int64) (domain.Authorization, error) {
+						return domain.Authorization{ID: id, AccountID: 1, OperationTypeID: 7, AmountCents: 6000, Currency: "BRL", ReservesCreditLimit: tt.reserved, Status: domain.AuthorizationStatusPending, ExpiresAt: time.Now().Add(time.Hour)}, nil
+					},
+				},
+				TransactionManager: &mockTransactionManager{},
+				Clock:              &mockClock{now: time.Now()},
+			}
+
+			_, tx, err := uc.Execute(context.Background(), 3, cents(0))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if acc.AvailableCreditLimitCents != tt.wantLimit {
+				t.Errorf("expected limit %d, got %d", tt.wantLimit, acc.AvailableCreditLimitCents)
+			}
+			if tx.AffectsCreditLimit != tt.reserved {
+				t.Errorf("expected the transaction to record AffectsCreditLimit %v, got %v", tt.reserved, tx.AffectsCreditLimit)
+			}
+		})
+	}
+}
+
+func TestVoidAuthorization_Execute(t *testing.T) {
+	var limit, pending int64
+	var closed domain.Authorization
+
+	uc := usecase.VoidAuthorization{
+		Accounts: &mockAccountRepo{
+			findByIDForUpdate: func(ctx context.Context, id int64) (domain.Account, error) {
+				return domain.Account{ID: id, AvailableCreditLimitCents: 4000, Currency: "BRL", Status: domain.AccountStatusBlocked}, nil
+			},
+			updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+				limit = limitCents
+				return nil
+			},
+		},
+		OperationTypes: &mockOperationTypeRepo{},
+		Transactions:   &mockTransactionRepo{},
+		Balances: &mockBalanceRepo{
+			applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+				pending += pendingDelta
+				return nil
+			},
+		},
+		Authorizations: &mockAuthorizationRepo{
+			closeFn: func(ctx context.Context, auth domain.Authorization) error {
+				closed = auth
+				return nil
+			},
+		},
+		TransactionManager: &mockTransactionManager{},
+		Clock:              &mockClock{now: time.Now()},
+	}
+
+	auth, err := uc.Execute(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth.Status != domain.AuthorizationStatusVoided || closed.Status != domain.AuthorizationStatusVoided {
+		t.Errorf("expected the authorization voided, got %+v", auth)
+	}
+	if limit != 10000 || pending != -6000 {
+		t.Errorf("expected limit 10000 and pending -6000, got %d and %d", limit, pending)
+	}
+}
+
+func TestVoidAuthorization_ReleasesAsHeld(t *testing.T) {
+	// The hold was placed while the type did not affect the limit; it has
+	// been changed to affect it since.
+	limitUpdated := false
+	var pending int64
+
+	uc := usecase.VoidAuthorization{
+		Accounts: &mockAccountRepo{
+			updateLimitFn: func(ctx context.Context, id int64, limitCents int64) error {
+				limitUpdated = true
+				return nil
+			},
+		},
+		OperationTypes: &mockOperationTypeRepo{
+			findByIDFn: func(ctx context.Context, id int) (domain.OperationType, error) {
+				return domain.OperationType{ID: id, Sign: -1, AffectsCreditLimit: true, Active: true}, nil
+			},
+		},
+		Transactions: &mockTransactionRepo{},
+		Balances: &mockBalanceRepo{
+			applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+				pending += pendingDelta
+				return nil
+			},
+		},
+		Authorizations: &mockAuthorizationRepo{
+			findByIDFn: func(ctx context.Context, id int64) (domain.Authorization, error) {
+				return domain.Authorization{ID: id, AccountID: 1, OperationTypeID: 7, AmountCents: 6000, Currency: "BRL", Status: domain.AuthorizationStatusPending}, nil
+			},
+		},
+		TransactionManager: &mockTransactionManager{},
+		Clock:              &mockClock{now: time.Now()},
+	}
+
+	if _, err := uc.Execute(context.Background(), 3); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if limitUpdated {
+		t.Error("expected the limit untouched by a hold that did not reserve it")
+	}
+	if pending != -6000 {
+		t.Errorf("expected pending -6000, got %d", pending)
+	}
+}
+
+func TestExpireAuthorizations_Execute(t *testing.T) {
+	asOf := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	due := []domain.Authorization{
+		{ID: 1, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: 6000, Currency: "BRL", Status: domain.AuthorizationStatusPending, ExpiresAt: asOf},
+		{ID: 2, AccountID: 1, OperationTypeID: domain.OperationTypeNormalPurchase, AmountCents: 2000, Currency: "BRL", Status: domain.AuthorizationStatusPending, ExpiresAt: asOf},
+	}
+
+	var expiredIDs []int64
+	var pending int64
+
+	uc := usecase.ExpireAuthorizations{
+		Accounts:       &mockAccountRepo{},
+		OperationTypes: &mockOperationTypeRepo{},
+		Transactions:   &mockTransactionRepo{},
+		Balances: &mockBalanceRepo{
+			applyFn: func(ctx context.Context, accountID int64, postedDelta, pendingDelta int64) error {
+				pending += pendingDelta
+				return nil
+			},
+		},
+		Authorizations: &mockAuthorizationRepo{
+			findExpiredFn: func(ctx context.Context, at time.Time, limit int) ([]domain.Authorization, error) {
+				if limit != usecase.DefaultExpiryBatchSize {
+					t.Errorf("expected default batch size, got %d", limit)
+				}
+				return due, nil
+			},
+			findByIDFn: func(ctx context.Context, id int64) (domain.Authorization, error) {
+				auth := due[id-1]
+				// The second one was captured while the job waited for the lock.
+				if id == 2 {
+					auth.Status = domain.AuthorizationStatusCaptured
+				}
+				return auth, nil
+			},
+			closeFn: func(ctx context.Context, auth domain.Authorization) error {
+				if auth.Status != domain.AuthorizationStatusExpired {
+					t.Errorf("expected status EXPIRED, got %s", auth.Status)
+				}
+				expiredIDs = append(expiredIDs, auth.ID)
+				return nil
+			},
+		},
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	n, err := uc.Execute(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(expiredIDs) != 1 || expiredIDs[0] != 1 {
+		t.Errorf("expected only authorization 1 to expire, got %d (%v)", n, expiredIDs)
+	}
+	if pending != -6000 {
+		t.Errorf("expected pending -6000, got %d", pending)
+	}
+}
+
+// =============================================================================
+// Outbox Tests
+// =============================================================================
+
+func TestCreateAccount_Outbox(t *testing.T) {
+	outbox := &mockOutboxRepo{}
+	uc := usecase.CreateAccount{
+		Accounts:           &mockAccountRepo{createFn: func(ctx context.Context, acc domain.Account) (int64, error) { return 9, nil }},
+		Documents:          &mockDocuments{},
+		Outbox:             outbox,
+		TransactionManager: &mockTransactionManager{},
+	}
+
+	acc, err := uc.Execute(context.Background(), usecase.CreateAccountInput{DocumentNumber: "52998224725"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outbox.added) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(outbox.added))
+	}
+
+	event := outbox.added[0]
+	var payload domain.AccountCreatedPayload
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != domain.EventAccountCreated || event.AccountID != acc.ID || payload.AccountID != 9 || payload.DocumentNumber != "52998224725" {
+		t.Errorf("unexpected event %+v with payload %+v", event, payload)
+	}
+
+	t.Run("outbox failure fails the request", func(t *testing.T) {
+		uc.Outbox = &mockOutboxRepo{addErr: errors.New("db down")}
+		if _, err := uc.Execute(context.Background(), usecase.CreateAccountInput{DocumentNumber: "52998224725"}); err == nil {
+			t.Error("expected an error")
+		}
+	})
+}
+
+func TestCreateTransaction_Outbox(t *testing.T) {
+	outbox := &mockOutboxRepo{}
+	uc := usecase.CreateTransaction{
+		Accounts:           &mockAccountRepo{},
+		OperationTypes:     &mockOperationTypeRepo{},
+		Transactions:       &mockTransactionRepo{createFn: func(ctx context.Context, tx domain.Transaction) (int64, error) { return 5, nil }},
+		Balances:           &mockBalanceRepo{},
+		TransactionManager: &mockTransactionManager{},
+		Outbox:             outbox,
+	}
+
+	_, err := uc.Execute(context.Background(), usecase.CreateTransactionInput{
+		AccountID:       1,
+		OperationTypeID: domain.OperationTypeNormalPurchase,
+		Amount:          cents(1234),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(outbox.added) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(outbox.added))
+	}
+
+	var payload domain.TransactionPostedPayload
+	if err := json.Unmarshal(outbox.added[0].Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if outbox.added[0].Type != domain.EventTransactionPosted || payload.TransactionID != 5 || payload.AmountCents != -1234 || payload.Currency != "BRL" {
+		t.Errorf("unexpected event %+v with payload %+v", outbox.added[0], payload)
+	}
+}
+
+func TestRelayEvents_Execute(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+	outbox := &mockOutboxRepo{
+		claimFn: func(ctx context.Context, asOf, until time.Time, limit int) ([]domain.Event, error) {
+			if !asOf.Equal(now) || !until.Equal(now.Add(usecase.DefaultRelayLease)) || limit != usecase.DefaultRelayBatchSize {
+				t.Errorf("unexpected claim at %v until %v for %d", asOf, until, limit)
+			}
+			return []domain.Event{
+				{ID: 1, AccountID: 1},
+				{ID: 2, AccountID: 2, Attempts: 2},
+				{ID: 3, AccountID: 3, Attempts: 30},
+				{ID: 4, AccountID: 4, Attempts: 39},
+			}, nil
+		},
+	}
+	log := &mockLogger{}
+
+	uc := usecase.RelayEvents{
+		Outbox: outbox,
+		Publisher: &mockPublisher{publishFn: func(ctx context.Context, event domain.Event) error {
+			if event.ID == 1 {
+				return nil
+			}
+			return errors.New("endpoint unavailable")
+		}},
+		Clock:       &mockClock{now: now},
+		MaxBackoff:  time.Minute,
+		MaxAttempts: 40,
+		Logger:      log,
+	}
+
+	n, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 || len(outbox.published) != 1 || outbox.published[0] != 1 {
+		t.Errorf("expected only event 1 published, got %d (%v)", n, outbox.published)
+	}
+
+	tests := []struct {
+		id           int64
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{2, 3, now.Add(4 * time.Second)},
+		{3, 31, now.Add(time.Minute)},
+	}
+	for _, tt := range tests {
+		failed, ok := outbox.failed[tt.id]
+		if !ok {
+			t.Errorf("expected event %d to be marked failed", tt.id)
+			continue
+		}
+		if failed.Attempts != tt.wantAttempts || !failed.NextAttemptAt.Equal(tt.wantNext) || failed.LastError != "endpoint unavailable" {
+			t.Errorf("event %d: expected attempt %d retried at %v, got %+v", tt.id, tt.wantAttempts, tt.wantNext, failed)
+		}
+	}
+
+	// Event 4 used its last attempt: it is parked instead of retried, so the
+	// rest of its account's queue can go out.
+	if _, ok := outbox.failed[4]; ok {
+		t.Error("expected event 4 not to be retried")
+	}
+	if dead, ok := outbox.dead[4]; !ok || dead.Attempts != 40 || dead.LastError != "endpoint unavailable" {
+		t.Errorf("expected event 4 dead after 40 attempts, got %+v", dead)
+	}
+	if len(log.errors) != 1 || log.errors[0] != "event is dead" {
+		t.Errorf("expected the dead event to be logged, got %v", log.errors)
+	}
+}
+
+func TestWatchTransactions_Execute(t *testing.T) {
+	posted := func(id, txID int64) domain.Event {
+		event, err := domain.NewTransactionPosted(domain.Transaction{ID: txID, AccountID: 1}, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		event.ID = id
+		return event
+	}
+
+	var afterIDs []int64
+	outbox := &mockOutboxRepo{
+		latestFn: func(ctx context.Context, accountID int64, eventType domain.EventType) (int64, error) {
+			if accountID != 1 || eventType != domain.EventTransactionPosted {
+				t.Errorf("unexpected latest %s event of account %d", eventType, accountID)
+			}
+			return 10, nil
+		},
+		listFn: func(ctx context.Context, accountID int64, eventType domain.EventType, afterID int64, limit int) ([]domain.Event, error) {
+			if accountID != 1 || eventType != domain.EventTransactionPosted {
+				t.Errorf("unexpected listing of %s events of account %d", eventType, accountID)
+			}
+			afterIDs = append(afterIDs, afterID)
+			switch afterID {
+			case 10:
+				return []domain.Event{posted(11, 100), posted(13, 101)}, nil
+			case 13:
+				return []domain.Event{posted(14, 102)}, nil
+			}
+			return nil, nil
+		},
+	}
+	uc := usecase.WatchTransactions{
+		Accounts:     &mockAccountRepo{},
+		Transactions: &mockTransactionRepo{},
+		Outbox:       outbox,
+		PollInterval: time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []usecase.PostedTransaction
+	err := uc.Execute(ctx, 1, 0, func(p usecase.PostedTransaction) error {
+		got = append(got, p)
+		if len(got) == 3 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if len(got) != 3 || got[0].EventID != 11 || got[1].Transaction.ID != 101 || got[2].EventID != 14 {
+		t.Errorf("unexpected transactions %+v", got)
+	}
+	if len(afterIDs) < 2 || afterIDs[0] != 10 || afterIDs[1] != 13 {
+		t.Errorf("expected the watch to start at the latest event and follow it, got %v", afterIDs)
+	}
+
+	t.Run("resume", func(t *testing.T) {
+		afterIDs = nil
+		sendErr := errors.New("client gone")
+		err := uc.Execute(context.Background(), 1, 13, func(p usecase.PostedTransaction) error { return sendErr })
+		if !errors.Is(err, sendErr) {
+			t.Errorf("expected the send error, got %v", err)
+		}
+		if len(afterIDs) != 1 || afterIDs[0] != 13 {
+			t.Errorf("expected the watch to resume after event 13, got %v", afterIDs)
+		}
+	})
+
+	t.Run("unknown account", func(t *testing.T) {
+		uc.Accounts = &mockAccountRepo{findByIDFn: func(ctx context.Context, id int64) (domain.Account, error) {
+			return domain.Account{}, domain.ErrAccountNotFound
+		}}
+		err := uc.Execute(context.Background(), 1, 0, func(usecase.PostedTransaction) error { return nil })
+		if !errors.Is(err, domain.ErrAccountNotFound) {
+			t.Errorf("expected ErrAccountNotFound, got %v", err)
+		}
+	})
+}
+
+// =============================================================================
+// Webhook Tests
+// =============================================================================
+
+func TestWebhookFanout_Publish(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+	deliveries := &mockDeliveryRepo{}
+	uc := usecase.WebhookFanout{
+		Subscriptions: &mockWebhookRepo{matchingFn: func(ctx context.Context, event domain.Event) ([]domain.WebhookSubscription, error) {
+			return []domain.WebhookSubscription{{ID: 1, Active: true}, {ID: 2, Active: true}}, nil
+		}},
+		Deliveries: deliveries,
+		Clock:      &mockClock{now: now},
+	}
+
+	event := domain.Event{ID: 7, Type: domain.EventTransactionPosted, AccountID: 3, Payload: json.RawMessage(`{"transaction_id":5}`), CreatedAt: now.Add(-time.Minute)}
+	if err := uc.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(deliveries.enqueued) != 2 {
+		t.Fatalf("expected one delivery per subscription, got %d", len(deliveries.enqueued))
+	}
+	for i, d := range deliveries.enqueued {
+		if d.SubscriptionID != int64(i+1) || d.EventID != 7 || d.Status != domain.WebhookDeliveryPending || !d.NextAttemptAt.Equal(now) || !d.OccurredAt.Equal(event.CreatedAt) {
+			t.Errorf("unexpected delivery %+v", d)
+		}
+	}
+}
+
+func TestDeliverWebhooks_Execute(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+	lease := now.Add(usecase.DefaultDeliveryLease)
+	deliveries := &mockDeliveryRepo{
+		claimFn: func(ctx context.Context, asOf, until time.Time, limit int) ([]domain.WebhookDelivery, error) {
+			if !asOf.Equal(now) || !until.Equal(lease) || limit != usecase.DefaultDeliveryBatchSize {
+				t.Errorf("unexpected claim at %v until %v for %d", asOf, until, limit)
+			}
+			return []domain.WebhookDelivery{
+				{ID: 1, SubscriptionID: 1, Status: domain.WebhookDeliveryPending},
+				{ID: 2, SubscriptionID: 1, Status: domain.WebhookDeliveryPending, Attempts: 2},
+				{ID: 3, SubscriptionID: 1, Status: domain.WebhookDeliveryPending, Attempts: 4},
+				{ID: 4, SubscriptionID: 2, Status: domain.WebhookDeliveryPending},
+				{ID: 5, SubscriptionID: 1, Status: domain.WebhookDeliveryPending},
+				{ID: 6, SubscriptionID: 1, Status: domain.WebhookDeliveryPending, NextAttemptAt: lease},
+			}, nil
+		},
+		// Delivery 6 is redelivered while it is being sent.
+		conflicts: map[int64]bool{6: true},
+	}
+	sender := &mockSender{sendFn: func(ctx context.Context, sub domain.WebhookSubscription, d domain.WebhookDelivery) (int, error) {
+		switch d.ID {
+		case 1:
+			return http.StatusNoContent, nil
+		case 5:
+			// The host now resolves to a private address.
+			return 0, fmt.Errorf("failed to call webhook: %w", domain.ErrWebhookTargetDenied)
+		}
+		return http.StatusServiceUnavailable, errors.New("webhook answered 503")
+	}}
+
+	uc := usecase.DeliverWebhooks{
+		Subscriptions: &mockWebhookRepo{findByIDFn: func(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+			// Subscription 2 was deleted.
+			return domain.WebhookSubscription{ID: id, Active: id == 1}, nil
+		}},
+		Deliveries:  deliveries,
+		Sender:      sender,
+		Clock:       &mockClock{now: now},
+		MaxAttempts: 5,
+		MaxBackoff:  time.Minute,
+	}
+
+	n, err := uc.Execute(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected 1 delivered, got %d", n)
+	}
+	if len(sender.sent) != 5 {
+		t.Errorf("expected nothing sent to the deleted subscription, sent %v", sender.sent)
+	}
+
+	tests := []struct {
+		id           int64
+		wantStatus   domain.WebhookDeliveryStatus
+		wantAttempts int
+		wantNext     time.Time
+	}{
+		{1, domain.WebhookDeliveryDelivered, 1, time.Time{}},
+		{2, domain.WebhookDeliveryPending, 3, now.Add(4 * time.Second)},
+		{3, domain.WebhookDeliveryDead, 5, time.Time{}},
+		{4, domain.WebhookDeliveryDead, 0, time.Time{}},
+		{5, domain.WebhookDeliveryDead, 1, time.Time{}},
+	}
+	for _, tt := range tests {
+		d, ok := deliveries.updated[tt.id]
+		if !ok {
+			t.Errorf("expected delivery %d to be updated", tt.id)
+			continue
+		}
+		if d.Status != tt.wantStatus || d.Attempts != tt.wantAttempts || !d.NextAttemptAt.Equal(tt.wantNext) {
+			t.Errorf("delivery %d: expected %s after %d attempts, next at %v, got %+v", tt.id, tt.wantStatus, tt.wantAttempts, tt.wantNext, d)
+		}
+	}
+	if d := deliveries.updated[1]; !d.DeliveredAt.Equal(now) || d.LastStatusCode != http.StatusNoContent {
+		t.Errorf("expected delivery 1 delivered at %v with 204, got %+v", now, d)
+	}
+	if d := deliveries.updated[3]; d.LastError != "webhook answered 503" || d.LastStatusCode != http.StatusServiceUnavailable {
+		t.Errorf("expected the last failure recorded on the dead delivery, got %+v", d)
+	}
+	if _, ok := deliveries.updated[6]; ok || !deliveries.seen[6].Equal(lease) {
+		t.Errorf("expected the redelivery of 6 to stand over its claimed attempt, seen %v", deliveries.seen[6])
+	}
+}
+
+func TestCreateWebhook_Execute(t *testing.T) {
+	subs := &mockWebhookRepo{}
+	resolver := &mockResolver{hosts: map[string][]netip.Addr{
+		"partner.example":  {netip.MustParseAddr("203.0.113.10"), netip.MustParseAddr("2001:db8::10")},
+		"internal.example": {netip.MustParseAddr("203.0.113.11"), netip.MustParseAddr("10.0.0.5")},
+		"169.254.169.254":  {netip.MustParseAddr("169.254.169.254")},
+		"::1":              {netip.MustParseAddr("::1")},
+		"mapped.example":   {netip.MustParseAddr("::ffff:127.0.0.1")},
+	}}
+	uc := usecase.CreateWebhook{Subscriptions: subs, Accounts: &mockAccountRepo{}, Resolver: resolver}
+
+	sub, err := uc.Execute(context.Background(), usecase.CreateWebhookInput{
+		URL:        "https://partner.example/hooks",
+		EventTypes: []domain.EventType{domain.EventAccountCreated},
+		AccountID:  1,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sub.ID != 1 || !sub.Active || !strings.HasPrefix(sub.Secret, "whsec_") || len(sub.Secret) != len("whsec_")+64 {
+		t.Errorf("unexpected subscription %+v", sub)
+	}
+
+	other, _ := uc.Execute(context.Background(), usecase.CreateWebhookInput{URL: "http://partner.example"})
+	if other.Secret == sub.Secret {
+		t.Error("expected every subscription to get its own secret")
+	}
+
+	tests := []struct {
+		name    string
+		input   usecase.CreateWebhookInput
+		wantErr error
+	}{
+		{"no url", usecase.CreateWebhookInput{}, usecase.ErrInvalidURL},
+		{"no host", usecase.CreateWebhookInput{URL: "https://"}, usecase.ErrInvalidURL},
+		{"unknown host", usecase.CreateWebhookInput{URL: "https://nowhere.example"}, usecase.ErrInvalidURL},
+		{"one private address", usecase.CreateWebhookInput{URL: "https://internal.example/hooks"}, domain.ErrWebhookTargetDenied},
+		{"cloud metadata", usecase.CreateWebhookInput{URL: "http://169.254.169.254/latest/meta-data"}, domain.ErrWebhookTargetDenied},
+		{"loopback", usecase.CreateWebhookInput{URL: "http://[::1]:8080/hooks"}, domain.ErrWebhookTargetDenied},
+		{"mapped loopback", usecase.CreateWebhookInput{URL: "http://mapped.example"}, domain.ErrWebhookTargetDenied},
+		{"unknown type", usecase.CreateWebhookInput{URL: "https://partner.example", EventTypes: []domain.EventType{"Nope"}}, usecase.ErrInvalidEventType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := uc.Execute(context.Background(), tt.input); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRedeliverWebhook_Execute(t *testing.T) {
+	now := time.Date(2024, 2, 12, 8, 0, 0, 0, time.UTC)
+	lastAttempt := now.Add(-time.Hour)
+	deliveries := &mockDeliveryRepo{findByIDFn: func(ctx context.Context, id int64) (domain.WebhookDelivery, error) {
+		return domain.WebhookDelivery{ID: id, SubscriptionID: 1, Status: domain.WebhookDeliveryDead, Attempts: 10, NextAttemptAt: lastAttempt, LastError: "webhook answered 500"}, nil
+	}}
+	uc := usecase.RedeliverWebhook{
+		Subscriptions: &mockWebhookRepo{},
+		Deliveries:    deliveries,
+		Clock:         &mockClock{now: now},
+	}
+
+	d, err := uc.Execute(context.Background(), 1, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Status != domain.WebhookDeliveryPending || d.Attempts != 0 || !d.NextAttemptAt.Equal(now) || deliveries.updated[5].Status != domain.WebhookDeliveryPending {
+		t.Errorf("expected the delivery queued again, got %+v", d)
+	}
+	if !deliveries.seen[5].Equal(lastAttempt) {
+		t.Errorf("expected the update to expect the delivery as read, got %v", deliveries.seen[5])
+	}
+
+	t.Run("changed meanwhile", func(t *testing.T) {
+		deliveries.conflicts = map[int64]bool{5: true}
+		defer func() { deliveries.conflicts = nil }()
+		if _, err := uc.Execute(context.Background(), 1, 5); !errors.Is(err, domain.ErrConcurrentUpdate) {
+			t.Errorf("expected ErrConcurrentUpdate, got %v", err)
+		}
+	})
+
+	t.Run("delivery of another subscription", func(t *testing.T) {
+		if _, err := uc.Execute(context.Background(), 2, 5); !errors.Is(err, domain.ErrDeliveryNotFound) {
+			t.Errorf("expected ErrDeliveryNotFound, got %v", err)
+		}
+	})
+
+	t.Run("deleted subscription", func(t *testing.T) {
+		uc.Subscriptions = &mockWebhookRepo{findByIDFn: func(ctx context.Context, id int64) (domain.WebhookSubscription, error) {
+			return domain.WebhookSubscription{ID: id}, nil
+		}}
+		if _, err := uc.Execute(context.Background(), 1, 5); !errors.Is(err, domain.ErrWebhookInactive) {
+			t.Errorf("expected ErrWebhookInactive, got %v", err)
+		}
+	})
+}